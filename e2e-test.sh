@@ -26,7 +26,7 @@ echo "📈 Test 2: Generate regular traffic"
 echo "-----------------------------------"
 echo "Hitting various endpoints to generate spans..."
 curl -s "$BASE_URL/" > /dev/null && echo "  ✓ GET /"
-curl -s "$BASE_URL/api/users?limit=10" > /dev/null && echo "  ✓ GET /api/users"
+curl -s "$BASE_URL/api/users?per_page=10" > /dev/null && echo "  ✓ GET /api/users"
 curl -s "$BASE_URL/api/order?amount=500&items=3" > /dev/null && echo "  ✓ GET /api/order (amount=500)"
 curl -s "$BASE_URL/api/order?amount=1500&items=5" > /dev/null && echo "  ✓ GET /api/order (amount=1500)"
 curl -s "$BASE_URL/api/order?amount=2000&items=7" > /dev/null && echo "  ✓ GET /api/order (amount=2000)"
@@ -116,7 +116,7 @@ echo "📊 Final traffic generation"
 echo "--------------------------"
 echo "Generating final burst of traffic..."
 for i in {1..20}; do
-  curl -s "$BASE_URL/api/users?limit=5" > /dev/null
+  curl -s "$BASE_URL/api/users?per_page=5" > /dev/null
   curl -s "$BASE_URL/api/order?amount=1200&items=4" > /dev/null
   sleep 0.2
 done
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"strings"
+
+	"github.com/Tracekit-Dev/test-app/internal/webhook"
+)
+
+// testCase is one request and what it must produce
+type testCase struct {
+	method string
+	// path may hold {name} placeholders filled from earlier saves
+	path string
+	// tmpl is the route template, when path has parameters
+	tmpl        string
+	body        string
+	contentType string
+	headers     map[string]string
+
+	status int
+	alsoOK []int
+	// keys must be present in the JSON response
+	keys []string
+	// respType is the expected Content-Type prefix of a non-JSON response
+	respType string
+	// save stores this JSON key for later paths
+	save string
+
+	// untraced routes are skipped by the sampler and must export nothing
+	untraced bool
+	// admin routes are served on the admin listener, which is never traced
+	admin bool
+	// spans must appear in the request's trace besides the server span
+	spans []string
+	// async spans end after the response, e.g. in a message consumer
+	async []string
+	// failed spans must have an Error status
+	failed []string
+	attrs  []attr
+
+	// needs runs the case only when this dependency is running, and without
+	// only when it isn't
+	needs, without string
+}
+
+// attr is an attribute value expected on a span; an empty span means the
+// server span
+type attr struct {
+	span, key, value string
+}
+
+func (c testCase) route() string {
+	if c.tmpl != "" {
+		return c.tmpl
+	}
+	path, _, _ := strings.Cut(c.path, "?")
+	return path
+}
+
+// unconfigured is a route that answers 503 while dep isn't running
+func unconfigured(dep, method, path, tmpl, body string) testCase {
+	return testCase{method: method, path: path, tmpl: tmpl, body: body, status: 503, keys: []string{"error", "message"}, without: dep}
+}
+
+// cases covers every route the app registers, in handlers.Register order
+func cases() []testCase {
+	webhookBody := `{"id": "evt_e2e", "type": "payment.succeeded"}`
+	uploadBody, uploadType := multipartFile("report.txt", "hello from e2e")
+	session := map[string]string{"X-Session-ID": "e2e-session"}
+
+	return []testCase{
+		{method: "GET", path: "/", status: 200, keys: []string{"message", "service"}},
+		{method: "GET", path: "/health", status: 200, keys: []string{"status", "service"}, untraced: true},
+		{method: "GET", path: "/health/deep", status: 200, keys: []string{"status"}, spans: []string{"deepHealthCheck"}},
+		{method: "GET", path: "/livez", status: 200, keys: []string{"status"}, untraced: true},
+		{method: "GET", path: "/readyz", status: 200, keys: []string{"status", "checks"}, untraced: true},
+
+		{method: "GET", path: "/api/users", status: 200, keys: []string{"users", "source"}, spans: []string{"fetchUsers"}},
+		// The first call filled the cache
+		{method: "GET", path: "/api/users", status: 200, keys: []string{"users", "source"}, spans: []string{"fetchUsers", "cache.get"},
+			attrs: []attr{{"fetchUsers", "users.source", "cache"}}, needs: "redis"},
+		{method: "GET", path: "/api/users?page=2&per_page=5", status: 200, keys: []string{"users", "page", "total", "total_pages"}, spans: []string{"fetchUsers"},
+			attrs: []attr{{"fetchUsers", "pagination.page", "2"}, {"fetchUsers", "pagination.returned_count", "5"}}},
+		{method: "GET", path: "/api/users?per_page=500", status: 400, keys: []string{"error"}},
+		{method: "GET", path: "/api/users/usr_0002", tmpl: "/api/users/:id", status: 200, keys: []string{"id", "name", "email"},
+			spans: []string{"getUser", "validateUserID"},
+			attrs: []attr{{"", "http.route.param.id", "usr_0002"}}},
+		{method: "GET", path: "/api/users/2/profile", tmpl: "/api/users/:id/profile", status: 200, keys: []string{"id", "email"},
+			spans: []string{"getUserProfile"}, attrs: []attr{{"getUserProfile", "http.etag_match", "false"}}},
+		{method: "GET", path: "/api/users/2/profile", tmpl: "/api/users/:id/profile", headers: map[string]string{"If-None-Match": "*"}, status: 304,
+			spans: []string{"getUserProfile"}, attrs: []attr{{"getUserProfile", "http.etag_match", "true"}}},
+		{method: "PUT", path: "/api/users/1", tmpl: "/api/users/:id", body: `{"name": "E2E User"}`, status: 200, keys: []string{"id", "name"},
+			spans: []string{"updateUser", "validateUserID"}},
+		// The update dropped the cached list
+		{method: "GET", path: "/api/users", status: 200, keys: []string{"users", "source"}, spans: []string{"fetchUsers", "cache.get"},
+			attrs: []attr{{"fetchUsers", "users.source", "origin"}}, needs: "redis"},
+		unconfigured("postgres", "GET", "/api/users-db", "", ""),
+		unconfigured("postgres", "GET", "/api/slow-query", "", ""),
+		unconfigured("postgres", "GET", "/api/products", "", ""),
+		unconfigured("postgres", "POST", "/api/products", "", `{"name": "e2e"}`),
+		unconfigured("postgres", "GET", "/api/products/1", "/api/products/:id", ""),
+		unconfigured("postgres", "PUT", "/api/products/1", "/api/products/:id", `{"name": "e2e"}`),
+		{method: "GET", path: "/api/users-db", status: 200, keys: []string{"users", "source"}, spans: []string{"fetchUsersFromDB", "db.select"},
+			needs: "postgres"},
+		{method: "GET", path: "/api/slow-query?ms=10", status: 200, keys: []string{"query", "duration_ms"}, spans: []string{"slowQuery"},
+			needs: "postgres"},
+		{method: "POST", path: "/api/products", body: `{"sku": "E2E-1", "name": "E2E product", "price_cents": 1299, "stock": 3}`,
+			status: 201, keys: []string{"id", "sku", "name"}, save: "id", spans: []string{"createProduct", "gorm.create"}, needs: "postgres"},
+		{method: "GET", path: "/api/products/{id}", tmpl: "/api/products/:id", status: 200, keys: []string{"id", "sku"},
+			spans: []string{"getProduct", "gorm.query"}, needs: "postgres"},
+		{method: "PUT", path: "/api/products/{id}", tmpl: "/api/products/:id", body: `{"stock": 5}`, status: 200, keys: []string{"id", "stock"},
+			spans: []string{"updateProduct", "gorm.update"}, needs: "postgres"},
+		{method: "GET", path: "/api/products", status: 200, keys: []string{"products", "source"}, spans: []string{"listProducts"}, needs: "postgres"},
+		unconfigured("mongodb", "GET", "/api/documents", "", ""),
+		unconfigured("mongodb", "POST", "/api/documents", "", `{"title": "e2e"}`),
+		unconfigured("mongodb", "GET", "/api/documents/1", "/api/documents/:id", ""),
+		unconfigured("mongodb", "PUT", "/api/documents/1", "/api/documents/:id", `{"title": "e2e"}`),
+		unconfigured("mongodb", "DELETE", "/api/documents/1", "/api/documents/:id", ""),
+		// cust-123 is in the new-order-validation rollout
+		{method: "POST", path: "/api/order", status: 201, keys: []string{"order_id", "amount", "status", "validation"}, save: "order_id",
+			spans: []string{"createOrder", "inventory.check", "feature_flag.evaluate", "order.validate"},
+			attrs: []attr{{"createOrder", "order.validation", "v2"}}},
+		// Without cgo there's no SQLite store, and order lookups are 503
+		{method: "GET", path: "/api/order/{order_id}", tmpl: "/api/order/:id", status: 200, alsoOK: []int{503}},
+		{method: "POST", path: "/api/order?saga=true", status: 201, keys: []string{"order_id", "status"}, spans: []string{"createOrderSaga"}},
+		{method: "POST", path: "/api/orders/bulk", body: `{"orders": [{"sku": "SKU-1", "quantity": 1, "amount": 10}, {"sku": "SKU-9", "quantity": 1, "amount": 10}]}`,
+			status: 207, keys: []string{"results", "succeeded", "failed"}, spans: []string{"bulkCreateOrders", "bulkCreateOrders.item"},
+			attrs: []attr{{"bulkCreateOrders", "bulk.succeeded", "1"}, {"bulkCreateOrders", "bulk.failed", "1"}}},
+		{method: "POST", path: "/api/orders/bulk", body: `{"orders": [{"sku": "` + strings.Repeat("x", 1<<20) + `"}]}`, status: 413, keys: []string{"error", "max_bytes"},
+			spans: []string{"bulkCreateOrders"}},
+		{method: "GET", path: "/api/customers/42/orders/7", tmpl: "/api/customers/:id/orders/:order_id", status: 200,
+			keys:  []string{"customer_id", "order_id", "total"},
+			spans: []string{"getCustomerOrder"},
+			attrs: []attr{
+				{"", "http.route", "/api/customers/:id/orders/:order_id"},
+				{"", "http.route.param.id", "42"},
+				{"", "http.route.param.order_id", "7"},
+			}},
+		unconfigured("kafka", "POST", "/api/publish-order", "", ""),
+		// The consumer continues the trace from the message headers
+		{method: "POST", path: "/api/publish-order", status: 202, keys: []string{"order_id", "amount", "status"},
+			spans: []string{"publishOrder", "orders publish"}, async: []string{"orders process", "fulfillOrder"}, needs: "kafka"},
+		{method: "GET", path: "/api/cart", headers: session, status: 200, spans: []string{"viewCart"}},
+		{method: "POST", path: "/api/cart/items", headers: session, body: `{"sku": "SKU-1", "quantity": 2, "price": 9.99}`, status: 200,
+			spans: []string{"addToCart"}},
+		{method: "POST", path: "/api/cart/checkout", headers: session, status: 201, keys: []string{"order_id", "session_id", "cart_id"},
+			spans: []string{"checkoutCart"}},
+		{method: "POST", path: "/api/pay", headers: map[string]string{"Idempotency-Key": "e2e-pay"}, body: `{"amount": 42.5, "currency": "usd"}`,
+			status: 201, keys: []string{"payment", "idempotent_replay"}, spans: []string{"pay"}},
+		{method: "POST", path: "/api/pay", headers: map[string]string{"Idempotency-Key": "e2e-pay"}, body: `{"amount": 42.5, "currency": "usd"}`,
+			status: 200, keys: []string{"payment", "idempotent_replay"}, spans: []string{"pay"}},
+		{method: "POST", path: "/api/notify?fail=none", status: 200, keys: []string{"status", "deliveries"}, spans: []string{"notify"}},
+		{method: "POST", path: "/api/notify?fail=sms", status: 207, keys: []string{"status", "deliveries"}, spans: []string{"notify"},
+			attrs: []attr{{"notify", "notify.status", "partial"}}},
+		{method: "POST", path: "/api/webhooks/stripe", tmpl: "/api/webhooks/:provider", body: webhookBody,
+			headers: map[string]string{webhook.SignatureHeader: webhook.Sign([]byte(webhookSecret), []byte(webhookBody))},
+			status:  200, keys: []string{"received", "provider", "event_id"},
+			spans: []string{"receiveWebhook", "webhook.verify", "webhook.process"}},
+		{method: "POST", path: "/api/outbound-webhooks", status: 202, keys: []string{"event_id", "type", "status"}, spans: []string{"emitWebhook"}},
+		{method: "GET", path: "/api/outbound-webhooks/dead-letters", status: 200, keys: []string{"dead_letters"}},
+		unconfigured("nats", "GET", "/api/check-stock", "", ""),
+		unconfigured("rabbitmq", "POST", "/api/enqueue", "", ""),
+		unconfigured("sqs", "POST", "/api/enqueue-sqs", "", ""),
+		{method: "POST", path: "/api/jobs?type=report", status: 202, keys: []string{"job_id", "type", "status"}, spans: []string{"submitJob"}},
+		{method: "POST", path: "/api/batch-process", body: `{"item": "e2e"}`, status: 200, keys: []string{"item", "batch_id", "batch_size"},
+			spans: []string{"batchProcess"}},
+		{method: "POST", path: "/api/batch?count=5&failure_rate=0", status: 200, keys: []string{"size", "concurrency", "processed"},
+			spans: []string{"batch", "batch.item"}},
+		{method: "GET", path: "/api/stream?events=2&interval=10ms", status: 200, respType: "text/event-stream", spans: []string{"streamEvents"}},
+		{method: "GET", path: "/api/poll?topic=e2e&timeout_ms=100", status: 200, keys: []string{"events", "cursor", "timed_out"}, spans: []string{"longPoll", "poll.wait"},
+			attrs: []attr{{"longPoll", "poll.outcome", "timeout"}, {"", "http.long_poll", "true"}}},
+		{method: "POST", path: "/api/poll?topic=e2e", body: `{"message": "hello"}`, status: 201, keys: []string{"seq", "topic", "data"}, spans: []string{"publishPollEvent"}},
+		{method: "POST", path: "/api/poll?topic=e2e", body: `{"message": "` + strings.Repeat("x", 64<<10) + `"}`, status: 413, keys: []string{"error", "max_bytes"},
+			spans: []string{"publishPollEvent"}},
+		{method: "GET", path: "/api/poll?topic=e2e&since=0", status: 200, keys: []string{"events", "cursor"}, spans: []string{"longPoll"},
+			attrs: []attr{{"longPoll", "poll.outcome", "ready"}, {"longPoll", "poll.events", "1"}}},
+		{method: "POST", path: "/api/upload", body: uploadBody, contentType: uploadType, status: 201, keys: []string{"files", "file_count", "total_bytes"},
+			spans: []string{"upload", "storage.write"}},
+		{method: "GET", path: "/api/download/1", tmpl: "/api/download/:size", status: 200, respType: "application/octet-stream", spans: []string{"download"}},
+		{method: "GET", path: "/api/export/users.csv?rows=10", status: 200, respType: "text/csv", spans: []string{"exportUsersCSV"}},
+
+		{method: "POST", path: "/graphql", body: `{"query": "query UserWithOrders { user(id: \"1\") { name orders { id amount } } }"}`,
+			status: 200, keys: []string{"data"}, spans: []string{"graphql.query UserWithOrders", "graphql.resolve Query.user", "graphql.resolve User.orders"},
+			attrs: []attr{{"graphql.resolve Query.user", "graphql.args.id", "1"}}},
+		// The schema's default limit shows up as an argument
+		{method: "POST", path: "/graphql", body: `{"query": "{ users { id } }"}`, status: 200, keys: []string{"data"},
+			spans: []string{"graphql.query", "graphql.resolve Query.users"}, attrs: []attr{{"graphql.resolve Query.users", "graphql.args.limit", "10"}}},
+		{method: "POST", path: "/graphql", body: `{"query": "{ users(limit: -1) { id } }"}`, status: 200, keys: []string{"errors"},
+			spans: []string{"graphql.query", "graphql.resolve Query.users"}, failed: []string{"graphql.query", "graphql.resolve Query.users"}},
+		{method: "GET", path: "/static/style.css", tmpl: "/static/*filepath", status: 200, respType: "text/css"},
+		{method: "HEAD", path: "/static/style.css", tmpl: "/static/*filepath", status: 200},
+		{method: "GET", path: "/api/metrics", status: 200, keys: []string{"message", "metrics"}},
+		{method: "GET", path: "/api/sampling", status: 200, keys: []string{"settings", "request"}},
+		{method: "GET", path: "/api/flags?key=cust-3", status: 200, keys: []string{"targeting_key", "flags", "evaluations"},
+			spans: []string{"evaluateFlags", "feature_flag.evaluate"}},
+
+		{method: "GET", path: "/api/call-node", status: 200, keys: []string{"called", "response"}, spans: []string{"callNodeService"},
+			attrs: []attr{{"callNodeService", "target.protocol", "http"}, {"HTTP GET", "loadbalancer.policy", "round_robin"},
+				{"HTTP GET", "http.client.pool.max_idle_conns", "20"}}},
+		{method: "GET", path: "/api/chain", status: 200, keys: []string{"message", "node_response"}, spans: []string{"chainCall"}},
+		{method: "GET", path: "/api/internal", status: 200, keys: []string{"message", "service"}, spans: []string{"internalEndpoint"}},
+		{method: "GET", path: "/api/data", status: 200, keys: []string{"service", "data"}, spans: []string{"processData"}},
+		{method: "GET", path: "/api/data", status: 200, keys: []string{"service", "data"},
+			attrs: []attr{{"", "cache.hit", "true"}, {"", "cache.stale", "false"}}},
+		{method: "GET", path: "/api/data?cache=bypass", status: 200, keys: []string{"service", "data"}, spans: []string{"processData"},
+			attrs: []attr{{"", "cache.bypass", "true"}, {"", "cache.hit", "false"}}},
+		{method: "GET", path: "/api/call-python", status: 200, keys: []string{"called", "response"}, spans: []string{"callPythonService"}},
+		{method: "GET", path: "/api/call-laravel", status: 200, keys: []string{"called", "response"}, spans: []string{"callLaravelService"}},
+		{method: "GET", path: "/api/call-php", status: 200, keys: []string{"called", "response"}, spans: []string{"callPHPService"}},
+		{method: "GET", path: "/api/call-all", status: 200, keys: []string{"service", "chain"}, spans: []string{"callAllServices"}},
+		{method: "GET", path: "/api/aggregate", status: 200, keys: []string{"budget_ms", "complete", "abandoned"}, spans: []string{"aggregate"}},
+		{method: "GET", path: "/api/call-grpc", status: 200, keys: []string{"called", "order"}, spans: []string{"callOrderService"}},
+		{method: "GET", path: "/api/grpc-stream?orders=2", status: 200, keys: []string{"called", "sent", "updates"},
+			spans: []string{"streamOrderStatus", "trackOrders"}},
+		{method: "GET", path: "/v1/orders/ORD-42", tmpl: "/v1/orders/:order_id", status: 200, keys: []string{"order_id", "status"},
+			spans: []string{"grpc-gateway GetOrder", "lookupOrder"},
+			attrs: []attr{{"lookupOrder", "order.id", "ORD-42"}}},
+		{method: "GET", path: "/api/call-flaky?failure_rate=0", status: 200, keys: []string{"attempts", "response"},
+			spans: []string{"callFlaky", "callFlaky.attempt"}},
+		{method: "GET", path: "/api/cancel-demo?work_ms=10", status: 200, keys: []string{"completed", "elapsed_ms"}, spans: []string{"cancelDemo"}},
+		{method: "GET", path: "/api/hedged-call?delay_ms=0", status: 200, keys: []string{"winner", "attempts", "response"},
+			spans: []string{"hedgedCall", "hedgedCall.attempt"}},
+		{method: "GET", path: "/api/breakers", status: 200, keys: []string{"breakers"}},
+		{method: "GET", path: "/api/baggage", status: 200, keys: []string{"sent", "echoed", "header"}, spans: []string{"baggageDemo"}},
+		{method: "GET", path: "/api/baggage/echo", status: 200, keys: []string{"service", "baggage"}, spans: []string{"baggageEcho"}},
+		{method: "GET", path: "/api/debug/propagation", status: 200, keys: []string{"received", "derived", "outbound"}, spans: []string{"debugPropagation"}},
+		{method: "GET", path: "/api/call-legacy?format=b3", status: 200, spans: []string{"callLegacy"}},
+		{method: "GET", path: "/api/call-graphql?user_id=2", status: 200, keys: []string{"operation", "data"},
+			spans: []string{"callGraphQL", "graphql.query UserWithOrders"},
+			attrs: []attr{{"callGraphQL", "graphql.variables.count", "1"}}},
+		{method: "GET", path: "/api/call-graphql?broken=true", status: 502, keys: []string{"operation", "errors"},
+			spans: []string{"callGraphQL"}, failed: []string{"callGraphQL"}},
+		{method: "GET", path: "/api/call-soap?order_id=ORD-1003", status: 200, keys: []string{"called", "order"},
+			spans: []string{"callSOAP", "soap.marshal", "soap.call", "soap.unmarshal", "soap GetOrderStatus"},
+			attrs: []attr{{"soap GetOrderStatus", "order.id", "ORD-1003"}}},
+		{method: "GET", path: "/api/call-soap?order_id=ORD-0", status: 502, keys: []string{"called", "fault"},
+			spans: []string{"callSOAP", "soap GetOrderStatus"}, failed: []string{"callSOAP"},
+			attrs: []attr{{"callSOAP", "soap.fault.code", "soap:Client"}}},
+		{method: "POST", path: "/soap/orders", body: `<Envelope/>`, contentType: "text/xml", status: 500, respType: "text/xml"},
+		{method: "GET", path: "/api/call-external", status: 200, keys: []string{"called", "peer_service", "response"},
+			spans: []string{"callExternalAPI"}, attrs: []attr{{"callExternalAPI", "peer.service", "external-api"}}},
+		{method: "GET", path: "/proxy/api/users/1", tmpl: "/proxy/*path", status: 200, keys: []string{"id", "name"},
+			spans: []string{"proxy"}, attrs: []attr{{"proxy", "proxy.path", "/api/users/1"}}},
+		{method: "POST", path: "/proxy/api/cart/items", tmpl: "/proxy/*path", body: `{"sku": "SKU-1", "quantity": 1}`, status: 200, alsoOK: []int{201},
+			spans: []string{"proxy"}},
+		{method: "GET", path: "/api/legacy/b3", tmpl: "/api/legacy/:format", status: 200, keys: []string{"format", "received", "parent"}},
+
+		{method: "GET", path: "/api/error", status: 500, keys: []string{"error"}, spans: []string{"triggerError"}, failed: []string{"triggerError"}},
+		{method: "GET", path: "/api/errors/404", tmpl: "/api/errors/:code", status: 404, keys: []string{"error", "message", "class"},
+			spans: []string{"errorCode"},
+			attrs: []attr{{"errorCode", "error.class", "client_error"}}},
+		{method: "GET", path: "/api/errors/503", tmpl: "/api/errors/:code", status: 503, keys: []string{"error", "message", "class"},
+			spans: []string{"errorCode"}, failed: []string{"errorCode"},
+			attrs: []attr{{"errorCode", "error.class", "server_error"}}},
+		{method: "GET", path: "/api/chaos?latency_ms=5", status: 200, keys: []string{"message", "latency_ms"}, spans: []string{"chaos"}},
+		{method: "GET", path: "/api/flaky?error_pct=0", status: 200, keys: []string{"message", "roll"}, spans: []string{"flaky"},
+			attrs: []attr{{"flaky", "flaky.outcome", "success"}}},
+		{method: "GET", path: "/api/flaky?error_pct=100", status: 500, keys: []string{"error", "roll"}, spans: []string{"flaky"}, failed: []string{"flaky"},
+			attrs: []attr{{"flaky", "flaky.outcome", "failure"}}},
+		{method: "GET", path: "/api/latency?dist=uniform&p50=5&p99=20", status: 200, keys: []string{"dist", "p50_ms", "p99_ms"}, spans: []string{"simulateLatency"}},
+		{method: "GET", path: "/api/contract/nested", tmpl: "/api/contract/:scenario", status: 200, keys: []string{"scenario", "version", "trace_id", "spans"},
+			spans: []string{"contract nested", "contract.level1", "contract.level2", "contract.level3"},
+			attrs: []attr{{"contract.level3", "contract.depth", "3"}}},
+		{method: "GET", path: "/api/contract/error", tmpl: "/api/contract/:scenario", status: 200, keys: []string{"spans"},
+			spans: []string{"contract.ok", "contract.failing"}, failed: []string{"contract.failing"}},
+		{method: "GET", path: "/security-test", status: 200, keys: []string{"message"}},
+
+		{method: "GET", path: "/metrics", status: 200, respType: "text/plain", admin: true, untraced: true},
+		{method: "GET", path: "/debug/runtime", status: 200, keys: []string{"goroutines", "memory"}, admin: true, untraced: true},
+		{method: "GET", path: "/debug/transport", status: 200, keys: []string{"clients"}, admin: true, untraced: true},
+	}
+}
+
+// multipartFile returns an upload body holding one file, and its Content-Type
+func multipartFile(name, content string) (string, string) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, _ := mw.CreateFormFile("file", name)
+	fw.Write([]byte(content))
+	mw.Close()
+	return buf.String(), mw.FormDataContentType()
+}
@@ -0,0 +1,161 @@
+//go:build docker
+
+// With the docker build tag the end-to-end suite also runs Postgres, Redis,
+// and Kafka with testcontainers, points the app at them, and checks the
+// routes that use them instead of their 503s. It needs a Docker daemon and
+// is meant for checking the integrations before a release:
+//
+//	go test -tags docker .
+//	E2E_DEPS=postgres go test -tags docker -run TestRoutes .
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// startupTimeout bounds how long a container may take to become ready
+const startupTimeout = 2 * time.Minute
+
+func init() {
+	startDeps = startContainers
+}
+
+// dependency is a backing service the suite runs in a container
+type dependency struct {
+	name string
+	// request describes the container; hostPort is a free local port for
+	// services that have to know their published port up front
+	request func(hostPort string) testcontainers.ContainerRequest
+	// appEnv points the app at the container's host:port
+	appEnv func(endpoint string) (key, value string)
+}
+
+var dependencies = []dependency{
+	{
+		name: "postgres",
+		request: func(string) testcontainers.ContainerRequest {
+			return testcontainers.ContainerRequest{
+				Image:        "postgres:16-alpine",
+				ExposedPorts: []string{"5432/tcp"},
+				Env:          map[string]string{"POSTGRES_PASSWORD": "postgres"},
+				WaitingFor:   wait.ForExec([]string{"pg_isready", "-U", "postgres", "-h", "localhost"}).WithStartupTimeout(startupTimeout),
+			}
+		},
+		appEnv: func(endpoint string) (string, string) {
+			return "DATABASE_URL", "postgres://postgres:postgres@" + endpoint + "/postgres?sslmode=disable"
+		},
+	},
+	{
+		name: "redis",
+		request: func(string) testcontainers.ContainerRequest {
+			return testcontainers.ContainerRequest{
+				Image:        "redis:7-alpine",
+				ExposedPorts: []string{"6379/tcp"},
+				WaitingFor:   wait.ForExec([]string{"redis-cli", "ping"}).WithStartupTimeout(startupTimeout),
+			}
+		},
+		appEnv: func(endpoint string) (string, string) { return "REDIS_ADDR", endpoint },
+	},
+	{
+		// A single KRaft node. Clients inside the container use the INTERNAL
+		// listener; the app uses EXTERNAL, which has to advertise the
+		// published port, so that port is fixed rather than left to Docker.
+		name: "kafka",
+		request: func(hostPort string) testcontainers.ContainerRequest {
+			return testcontainers.ContainerRequest{
+				Image:        "apache/kafka:3.8.0",
+				ExposedPorts: []string{"127.0.0.1:" + hostPort + ":9094/tcp"},
+				Env: map[string]string{
+					"KAFKA_NODE_ID":                                  "1",
+					"KAFKA_PROCESS_ROLES":                            "broker,controller",
+					"KAFKA_LISTENERS":                                "INTERNAL://:9092,EXTERNAL://:9094,CONTROLLER://:9093",
+					"KAFKA_ADVERTISED_LISTENERS":                     "INTERNAL://localhost:9092,EXTERNAL://127.0.0.1:" + hostPort,
+					"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "INTERNAL:PLAINTEXT,EXTERNAL:PLAINTEXT,CONTROLLER:PLAINTEXT",
+					"KAFKA_INTER_BROKER_LISTENER_NAME":               "INTERNAL",
+					"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+					"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:9093",
+					"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+					"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+					"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+					"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS":         "0",
+				},
+				// Creating the topic up front keeps the consumer from racing
+				// auto-creation
+				WaitingFor: wait.ForExec([]string{"/opt/kafka/bin/kafka-topics.sh", "--bootstrap-server", "localhost:9092",
+					"--create", "--if-not-exists", "--topic", "orders", "--partitions", "1"}).WithStartupTimeout(startupTimeout),
+			}
+		},
+		appEnv: func(endpoint string) (string, string) { return "KAFKA_BROKERS", endpoint },
+	},
+}
+
+// startContainers runs the dependencies E2E_DEPS names, all of them by
+// default, and waits until each is ready
+func startContainers(ctx context.Context) (running []string, env map[string]string, stop func(), err error) {
+	var started []testcontainers.Container
+	stop = func() {
+		for _, c := range started {
+			testcontainers.TerminateContainer(c)
+		}
+	}
+	// testcontainers panics when it can't find a Docker daemon
+	defer func() {
+		if r := recover(); r != nil {
+			running, env, err = nil, nil, fmt.Errorf("docker: %v", r)
+		}
+	}()
+
+	deps, err := selectDeps(os.Getenv("E2E_DEPS"))
+	if err != nil {
+		return nil, nil, stop, err
+	}
+	env = map[string]string{}
+	for _, d := range deps {
+		c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: d.request(freePort()),
+			Started:          true,
+		})
+		// A container that failed to become ready still has to be removed
+		started = append(started, c)
+		if err != nil {
+			return nil, nil, stop, fmt.Errorf("start %s: %w", d.name, err)
+		}
+		endpoint, err := c.Endpoint(ctx, "")
+		if err != nil {
+			return nil, nil, stop, fmt.Errorf("%s endpoint: %w", d.name, err)
+		}
+		key, value := d.appEnv(endpoint)
+		env[key] = value
+		running = append(running, d.name)
+	}
+	return running, env, stop, nil
+}
+
+// selectDeps resolves E2E_DEPS: a comma-separated list of names, or empty
+// for all of them
+func selectDeps(names string) ([]dependency, error) {
+	if names == "" {
+		return dependencies, nil
+	}
+	var picked []dependency
+	for _, name := range strings.Split(names, ",") {
+		found := false
+		for _, d := range dependencies {
+			if d.name == strings.TrimSpace(name) {
+				picked = append(picked, d)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown dependency %q in E2E_DEPS (have postgres, redis, kafka)", name)
+		}
+	}
+	return picked, nil
+}
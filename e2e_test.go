@@ -0,0 +1,434 @@
+// The end-to-end tests check every route of the example: the status code,
+// the shape of the response, and the spans each request produced. TestMain
+// wires the app up in standalone mode, serves its router with httptest, and
+// collects the spans in memory; the route table is in e2e_cases_test.go.
+//
+//	go test -run TestRoutes -v .
+//	go test -run 'TestRoutes/POST_/api/order' .
+//
+// They start the whole app, so -short skips them.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/config"
+	"github.com/Tracekit-Dev/test-app/internal/export"
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/requestid"
+	"github.com/Tracekit-Dev/test-app/internal/testcollector"
+)
+
+// webhookSecret signs the inbound webhook case
+const webhookSecret = "e2e-secret"
+
+// suite is the app the tests run against; nil with -short
+var suite *e2e
+
+// startDeps starts the backing services the app connects to, returning their
+// names and the environment pointing the app at them. stop must be called
+// even when err is set. Only the docker build sets it.
+var startDeps func(ctx context.Context) (running []string, env map[string]string, stop func(), err error)
+
+type e2e struct {
+	base, adminBase string
+	client          *http.Client
+
+	// spans gets every span as it ends; exported gets what the TraceKit
+	// exporter sends over OTLP
+	spans, exported *testcollector.Collector
+
+	// running names the backing services the app is connected to
+	running map[string]bool
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		os.Exit(m.Run())
+	}
+	os.Exit(runSuite(m))
+}
+
+// runSuite starts the app, runs the tests against it, and shuts it down. It
+// returns the exit code.
+func runSuite(m *testing.M) int {
+	tmp, err := os.MkdirTemp("", "tracekit-e2e-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "e2e:", err)
+		return 2
+	}
+	defer os.RemoveAll(tmp)
+
+	// Keep a developer's .env out of it
+	wd, err := os.Getwd()
+	if err == nil {
+		err = os.Chdir(tmp)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "e2e:", err)
+		return 2
+	}
+	defer os.Chdir(wd)
+
+	exported := testcollector.Start()
+	defer exported.Close()
+
+	// Outbound webhooks need somewhere to go
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	// /api/call-external must not depend on the internet; this plays an
+	// untraced third-party API
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"url": %q}`, r.URL.String())
+	}))
+	defer external.Close()
+
+	// The API listens on PORT, where the app's calls to itself go
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "e2e:", err)
+		return 2
+	}
+	port := strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+	adminPort := freePort()
+
+	env := map[string]string{
+		"TRACEKIT_API_KEY":      "e2e",
+		"TRACEKIT_ENDPOINT":     exported.Endpoint(),
+		"TRACEKIT_USE_SSL":      "false",
+		"TRACEKIT_SAMPLE_RATIO": "1",
+		"STANDALONE":            "true",
+		"PORT":                  port,
+		"GRPC_PORT":             freePort(),
+		"ADMIN_PORT":            adminPort,
+		"SQLITE_PATH":           filepath.Join(tmp, "orders.db"),
+		"LOG_LEVEL":             "warn",
+		"RATE_LIMIT_RPS":        "0",
+		"OUT_OF_STOCK_RATE":     "0",
+		"SCHEDULER_ENABLED":     "false",
+		"WEBHOOK_SECRET":        webhookSecret,
+		"WEBHOOK_TARGET_URL":    receiver.URL,
+		"EXTERNAL_API_URL":      external.URL + "/get",
+		"RESPONSE_CACHE_ROUTES": "/api/data=1m",
+	}
+	running := map[string]bool{}
+	if startDeps != nil {
+		names, depEnv, stop, err := startDeps(context.Background())
+		defer stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "e2e:", err)
+			return 2
+		}
+		for _, name := range names {
+			running[name] = true
+		}
+		maps.Copy(env, depEnv)
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+	cfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "e2e:", err)
+		return 2
+	}
+	logger, err := logging.Setup(cfg.LogLevel, cfg.Environment)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "e2e:", err)
+		return 2
+	}
+
+	gin.SetMode(gin.TestMode)
+	a := newApp(cfg, logger)
+	defer a.close()
+
+	// Spans are checked as they end rather than after the exporter's batch
+	spans := testcollector.New()
+	if err := export.Register(spans.Processor()); err != nil {
+		fmt.Fprintln(os.Stderr, "e2e:", err)
+		return 2
+	}
+
+	a.serveGRPC()
+	srv := httptest.NewUnstartedServer(a.router)
+	srv.Listener.Close()
+	srv.Listener = lis
+	srv.Start()
+
+	suite = &e2e{
+		base:      srv.URL,
+		adminBase: "http://localhost:" + adminPort,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		spans:     spans,
+		exported:  exported,
+		running:   running,
+	}
+	code := m.Run()
+
+	a.polls.Close()
+	srv.Close()
+	shutdown(logger, a.sdk, nil, a.admin.Server, a.grpcServer, a.stopBackground)
+	return code
+}
+
+// TestRoutes runs the route table in order, since later cases use values
+// saved from earlier responses
+func TestRoutes(t *testing.T) {
+	if suite == nil {
+		t.Skip("starts the whole app")
+	}
+	vars := map[string]string{}
+	for _, c := range cases() {
+		if (c.needs != "" && !suite.running[c.needs]) || (c.without != "" && suite.running[c.without]) {
+			continue
+		}
+		t.Run(c.method+" "+c.path, func(t *testing.T) {
+			traceID := suite.do(t, c, vars)
+			suite.checkSpans(t, c, traceID)
+		})
+	}
+}
+
+// TestOTLPExport checks that spans leave the app through the TraceKit
+// exporter, not only through the processor the other tests read
+func TestOTLPExport(t *testing.T) {
+	if suite == nil {
+		t.Skip("starts the whole app")
+	}
+	resp, err := suite.client.Get(suite.base + "/api/internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	traceID := resp.Header.Get(requestid.TraceHeader)
+	if traceID == "" {
+		t.Fatalf("no %s header; the request was not sampled", requestid.TraceHeader)
+	}
+
+	// The server span ends after the response; flush once it has
+	inTrace := testcollector.InTrace(traceID)
+	if _, err := suite.spans.WaitFor(5*time.Second, 2, inTrace); err != nil {
+		t.Fatal(err)
+	}
+	if tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider); ok {
+		tp.ForceFlush(context.Background())
+	}
+
+	spans, err := suite.exported.WaitFor(10*time.Second, 2, inTrace)
+	if err != nil {
+		t.Fatalf("%v (got %s)", err, names(spans))
+	}
+	byName := map[string]testcollector.Span{}
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	for _, name := range []string{"GET /api/internal", "internalEndpoint"} {
+		s, ok := byName[name]
+		if !ok {
+			t.Errorf("no %q span exported in trace %s (got %s)", name, traceID, names(spans))
+		} else if s.Service != "go-test-app" {
+			t.Errorf("span %q exported for service %q, want go-test-app", name, s.Service)
+		}
+	}
+}
+
+// freePort asks the kernel for a port that's free right now
+func freePort() string {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		panic(err)
+	}
+	defer lis.Close()
+	return strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+}
+
+// do sends one case and checks the response, returning the request's trace
+// ID. Values the case saves go into vars.
+func (s *e2e) do(t *testing.T, c testCase, vars map[string]string) string {
+	t.Helper()
+
+	path := c.path
+	for k, v := range vars {
+		path = strings.ReplaceAll(path, "{"+k+"}", v)
+	}
+	var body io.Reader
+	if c.body != "" {
+		body = strings.NewReader(c.body)
+	}
+	base := s.base
+	if c.admin {
+		base = s.adminBase
+	}
+	req, err := http.NewRequest(c.method, base+path, body)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	if c.body != "" {
+		ct := c.contentType
+		if ct == "" {
+			ct = "application/json"
+		}
+		req.Header.Set("Content-Type", ct)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	traceID := resp.Header.Get(requestid.TraceHeader)
+
+	if resp.StatusCode != c.status && !contains(c.alsoOK, resp.StatusCode) {
+		t.Errorf("status %d, want %d: %s", resp.StatusCode, c.status, snippet(raw))
+	}
+	if c.respType != "" && !strings.HasPrefix(resp.Header.Get("Content-Type"), c.respType) {
+		t.Errorf("Content-Type %q, want %s", resp.Header.Get("Content-Type"), c.respType)
+	}
+
+	if len(c.keys) > 0 || c.save != "" {
+		var doc map[string]any
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			t.Errorf("response is not a JSON object: %s", snippet(raw))
+			return traceID
+		}
+		for _, k := range c.keys {
+			if _, ok := doc[k]; !ok {
+				t.Errorf("response has no %q: %s", k, snippet(raw))
+			}
+		}
+		if c.save != "" {
+			if v, ok := doc[c.save]; ok {
+				vars[c.save] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	if c.untraced && traceID != "" {
+		t.Errorf("%s set on an untraced route", requestid.TraceHeader)
+	}
+	if !c.untraced && traceID == "" && !t.Failed() {
+		t.Errorf("no %s header; the request was not sampled", requestid.TraceHeader)
+	}
+	return traceID
+}
+
+// checkSpans checks the spans recorded for the case's trace
+func (s *e2e) checkSpans(t *testing.T, c testCase, traceID string) {
+	t.Helper()
+
+	serverName := c.method + " " + c.route()
+	if c.untraced {
+		// Give a span that shouldn't exist a moment to show up
+		if found, err := s.spans.WaitFor(200*time.Millisecond, 1, testcollector.Named(serverName)); err == nil {
+			t.Errorf("%d %q spans recorded for an untraced route", len(found), serverName)
+		}
+		return
+	}
+	if traceID == "" {
+		return
+	}
+
+	// Spans end around the response, or after it for work like the Kafka
+	// consumer's, so wait for each one expected
+	inTrace := testcollector.InTrace(traceID)
+	wait := func(name string, timeout time.Duration) {
+		s.spans.WaitFor(timeout, 1, func(sp testcollector.Span) bool {
+			return inTrace(sp) && sp.Name == name
+		})
+	}
+	for _, name := range append([]string{serverName}, c.spans...) {
+		wait(name, 5*time.Second)
+	}
+	for _, name := range c.async {
+		wait(name, 30*time.Second)
+	}
+
+	spans := s.spans.Find(inTrace)
+	byName := map[string]testcollector.Span{}
+	for _, sp := range spans {
+		if _, seen := byName[sp.Name]; !seen {
+			byName[sp.Name] = sp
+		}
+	}
+
+	server, ok := byName[serverName]
+	if !ok {
+		t.Errorf("no server span %q in trace %s (got %s)", serverName, traceID, names(spans))
+	} else if server.Kind != "server" {
+		t.Errorf("span %q has kind %s, want server", serverName, server.Kind)
+	}
+	for _, name := range append(c.spans, c.async...) {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("no %q span in trace %s (got %s)", name, traceID, names(spans))
+		}
+	}
+	for _, name := range c.failed {
+		if sp, ok := byName[name]; ok && sp.StatusCode != "Error" {
+			t.Errorf("span %q has status %s, want Error", name, sp.StatusCode)
+		}
+	}
+	for _, a := range c.attrs {
+		name := a.span
+		if name == "" {
+			name = serverName
+		}
+		sp, ok := byName[name]
+		if !ok {
+			continue // already reported above
+		}
+		if got := sp.Attr(a.key); got != a.value {
+			t.Errorf("span %q has %s=%q, want %q", name, a.key, got, a.value)
+		}
+	}
+}
+
+func names(spans []testcollector.Span) string {
+	var out []string
+	for _, s := range spans {
+		out = append(out, s.Name)
+	}
+	return "[" + strings.Join(out, ", ") + "]"
+}
+
+func contains(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func snippet(raw []byte) string {
+	if len(raw) > 200 {
+		return string(raw[:200]) + "..."
+	}
+	return string(raw)
+}
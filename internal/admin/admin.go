@@ -0,0 +1,140 @@
+// Package admin serves operational endpoints (metrics, pprof, runtime stats,
+// SDK debug info, recent spans, live config) on a separate listener. The
+// admin router is deliberately not traced so profiling and scraping never
+// show up in the API's traces or service graph.
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SDKInfo is the TraceKit configuration reported by /debug/sdk.
+// It must never include the API key.
+type SDKInfo struct {
+	ServiceName    string  `json:"service_name"`
+	Environment    string  `json:"environment"`
+	Endpoint       string  `json:"endpoint"`
+	UseSSL         bool    `json:"use_ssl"`
+	CodeMonitoring bool    `json:"code_monitoring"`
+	SampleRate     float64 `json:"sample_rate"`
+	ParentBased    bool    `json:"sample_parent_based"`
+	APIKeySet      bool    `json:"api_key_set"`
+}
+
+// Server is the admin HTTP server
+type Server struct {
+	*http.Server
+	mux     *http.ServeMux
+	info    SDKInfo
+	started time.Time
+
+	mu        sync.Mutex
+	endpoints []string
+}
+
+// New creates an admin server listening on addr
+func New(addr string, info SDKInfo) *Server {
+	mux := http.NewServeMux()
+	s := &Server{
+		Server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		mux:     mux,
+		info:    info,
+		started: time.Now(),
+	}
+
+	mux.HandleFunc("/{$}", s.index)
+
+	// net/http/pprof only self-registers on http.DefaultServeMux
+	s.Handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	s.Handle("/debug/runtime", http.HandlerFunc(s.runtimeStats))
+	s.Handle("/debug/sdk", http.HandlerFunc(s.sdkInfo))
+
+	return s
+}
+
+// Handle mounts an extra operational endpoint. It's safe to call after
+// Start, so components can mount theirs as they are created.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endpoints = append(s.endpoints, pattern)
+}
+
+// Start serves the admin endpoints in the background and reports a failure
+// to listen on errs
+func (s *Server) Start(errs chan<- error) {
+	go func() {
+		if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+}
+
+// index lists the mounted endpoints
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	endpoints := slices.Sorted(slices.Values(s.endpoints))
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{
+		"service":   s.info.ServiceName,
+		"endpoints": endpoints,
+	})
+}
+
+// runtimeStats reports goroutines, memory, and GC statistics
+func (s *Server) runtimeStats(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	writeJSON(w, map[string]interface{}{
+		"go_version": runtime.Version(),
+		"goroutines": runtime.NumGoroutine(),
+		"cpus":       runtime.NumCPU(),
+		"uptime":     time.Since(s.started).Round(time.Second).String(),
+		"memory": map[string]uint64{
+			"heap_alloc_bytes":  mem.HeapAlloc,
+			"heap_inuse_bytes":  mem.HeapInuse,
+			"heap_objects":      mem.HeapObjects,
+			"stack_inuse_bytes": mem.StackInuse,
+			"sys_bytes":         mem.Sys,
+		},
+		"gc": map[string]interface{}{
+			"num_gc":          mem.NumGC,
+			"pause_total_ms":  float64(mem.PauseTotalNs) / 1e6,
+			"last_pause_ms":   float64(mem.PauseNs[(mem.NumGC+255)%256]) / 1e6,
+			"next_gc_bytes":   mem.NextGC,
+			"gc_cpu_fraction": mem.GCCPUFraction,
+		},
+	})
+}
+
+// sdkInfo reports the TraceKit SDK configuration
+func (s *Server) sdkInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.info)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
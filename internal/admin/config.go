@@ -0,0 +1,233 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/Tracekit-Dev/test-app/internal/chaos"
+	"github.com/Tracekit-Dev/test-app/internal/export"
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/sampling"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// LiveConfig are the running components /admin/config reads and changes
+type LiveConfig struct {
+	Sampler *sampling.Sampler
+	Chaos   *chaos.Defaults
+	SpanLog *export.SpanLog
+}
+
+// liveState is what /admin/config reports
+type liveState struct {
+	LogLevel   string            `json:"log_level"`
+	DebugSpans bool              `json:"debug_spans"`
+	Chaos      chaosState        `json:"chaos"`
+	Sampling   sampling.Settings `json:"sampling"`
+
+	// SDKRatio caps every sampling ratio until a restart
+	SDKRatio float64 `json:"sdk_ratio"`
+}
+
+// chaosState are the default faults, in the units of the X-Chaos header
+type chaosState struct {
+	LatencyMS int64   `json:"latency_ms"`
+	JitterMS  int64   `json:"jitter_ms"`
+	ErrorRate float64 `json:"error_rate"`
+	Status    int     `json:"status"`
+}
+
+// livePatch is a PATCH body; absent fields are left alone. Chaos replaces
+// the defaults as a whole, so {} turns them off.
+type livePatch struct {
+	LogLevel   *string       `json:"log_level"`
+	DebugSpans *bool         `json:"debug_spans"`
+	Chaos      *chaosState   `json:"chaos"`
+	Sampling   *samplingEdit `json:"sampling"`
+}
+
+// samplingEdit changes the sampler settings; an empty routes object or
+// skip_paths list clears them
+type samplingEdit struct {
+	Ratio       *float64           `json:"ratio"`
+	ParentBased *bool              `json:"parent_based"`
+	Routes      map[string]float64 `json:"routes"`
+	SkipPaths   []string           `json:"skip_paths"`
+}
+
+// change is one setting a PATCH changed
+type change struct {
+	Setting string `json:"setting"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// ConfigHandler serves /admin/config: GET reports the live settings and
+// PATCH changes them without a restart. Both need "Authorization: Bearer
+// <token>"; without a token the endpoint answers 503.
+func ConfigHandler(token string, live LiveConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			writeError(w, http.StatusServiceUnavailable, "ADMIN_TOKEN is not set")
+			return
+		}
+		got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or wrong admin token")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, live.state())
+		case http.MethodPatch:
+			var patch livePatch
+			dec := json.NewDecoder(r.Body)
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&patch); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+				return
+			}
+			changes, capped, err := live.apply(patch)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, map[string]any{"config": live.state(), "changes": changes, "sampling_capped": capped})
+		default:
+			w.Header().Set("Allow", "GET, PATCH")
+			writeError(w, http.StatusMethodNotAllowed, "use GET or PATCH")
+		}
+	})
+}
+
+func (l LiveConfig) state() liveState {
+	fault, status := l.Chaos.Get()
+	return liveState{
+		LogLevel:   logging.Level(),
+		DebugSpans: l.SpanLog.Enabled(),
+		Chaos: chaosState{
+			LatencyMS: fault.Latency.Milliseconds(),
+			JitterMS:  fault.Jitter.Milliseconds(),
+			ErrorRate: fault.ErrorRate,
+			Status:    status,
+		},
+		Sampling: l.Sampler.Settings(),
+		SDKRatio: l.Sampler.SDKRatio(),
+	}
+}
+
+// apply validates patch and then applies it in an "admin.config.update"
+// span with a config.changed event per change. capped reports a sampling
+// ratio lowered to the SDK's. Nothing is applied when it returns an error.
+func (l LiveConfig) apply(patch livePatch) (changes []change, capped bool, err error) {
+	_, span := tracing.Tracer().Start(context.Background(), "admin.config.update", trace.WithNewRoot())
+	defer span.End()
+
+	changes, capped, err = l.applyPatch(patch)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, false, err
+	}
+	if capped {
+		span.AddEvent("sampling.capped", trace.WithAttributes(attribute.Float64("sampling.sdk_ratio", l.Sampler.SDKRatio())))
+		zap.L().Warn("⚠️  A sampling ratio is above the one the SDK started with; it's capped until a restart",
+			zap.Float64("sdk_ratio", l.Sampler.SDKRatio()))
+	}
+	for _, c := range changes {
+		span.AddEvent("config.changed", trace.WithAttributes(
+			attribute.String("config.setting", c.Setting),
+			attribute.String("config.from", c.From),
+			attribute.String("config.to", c.To),
+		))
+		zap.L().Info("🎛️  Live config changed", zap.String("setting", c.Setting), zap.String("from", c.From), zap.String("to", c.To))
+	}
+	span.SetAttributes(attribute.Int("config.changes", len(changes)))
+	span.SetStatus(codes.Ok, "")
+	return changes, capped, nil
+}
+
+func (l LiveConfig) applyPatch(patch livePatch) ([]change, bool, error) {
+	if patch.LogLevel != nil {
+		if _, err := zapcore.ParseLevel(*patch.LogLevel); err != nil {
+			return nil, false, fmt.Errorf("log_level: %w", err)
+		}
+	}
+	settings := l.Sampler.Settings()
+	if s := patch.Sampling; s != nil {
+		if s.Ratio != nil {
+			settings.Ratio, settings.Source = *s.Ratio, "/admin/config"
+		}
+		if s.ParentBased != nil {
+			settings.ParentBased = *s.ParentBased
+		}
+		if s.Routes != nil {
+			settings.Routes = s.Routes
+		}
+		if s.SkipPaths != nil {
+			settings.SkipPaths = s.SkipPaths
+		}
+		if settings.Ratio < 0 || settings.Ratio > 1 {
+			return nil, false, fmt.Errorf("sampling.ratio must be between 0 and 1")
+		}
+		for route, ratio := range settings.Routes {
+			if ratio < 0 || ratio > 1 {
+				return nil, false, fmt.Errorf("sampling.routes[%q] must be between 0 and 1", route)
+			}
+		}
+	}
+
+	// Setting the chaos defaults validates them, so it goes first
+	var changes []change
+	before := l.state()
+	if c := patch.Chaos; c != nil {
+		fault := chaos.Fault{
+			Latency:   time.Duration(c.LatencyMS) * time.Millisecond,
+			Jitter:    time.Duration(c.JitterMS) * time.Millisecond,
+			ErrorRate: c.ErrorRate,
+		}
+		if err := l.Chaos.Set(fault, c.Status); err != nil {
+			return nil, false, fmt.Errorf("chaos: %w", err)
+		}
+		changes = append(changes, change{"chaos", describe(before.Chaos), describe(l.state().Chaos)})
+	}
+	if patch.LogLevel != nil {
+		logging.SetLevel(*patch.LogLevel)
+		changes = append(changes, change{"log_level", before.LogLevel, logging.Level()})
+	}
+	if patch.DebugSpans != nil {
+		l.SpanLog.SetEnabled(*patch.DebugSpans)
+		changes = append(changes, change{"debug_spans", fmt.Sprint(before.DebugSpans), fmt.Sprint(*patch.DebugSpans)})
+	}
+	capped := false
+	if patch.Sampling != nil {
+		capped = l.Sampler.Update(settings)
+		changes = append(changes, change{"sampling", describe(before.Sampling), describe(settings)})
+	}
+	return changes, capped, nil
+}
+
+// describe renders a setting for a change
+func describe(v any) string {
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+)
+
+// transportState is one service's entry in /debug/transport
+type transportState struct {
+	Service               string `json:"service"`
+	TimeoutMS             int64  `json:"timeout_ms"`
+	MaxIdleConns          int    `json:"max_idle_conns"`
+	MaxIdleConnsPerHost   int    `json:"max_idle_conns_per_host"`
+	IdleConnTimeoutMS     int64  `json:"idle_conn_timeout_ms"`
+	DialTimeoutMS         int64  `json:"dial_timeout_ms"`
+	TLSHandshakeTimeoutMS int64  `json:"tls_handshake_timeout_ms"`
+}
+
+// TransportHandler serves /debug/transport: the effective pool and timeout
+// settings of each downstream service's HTTP client, after the service's
+// own settings, the HTTP_CLIENT_* variables, and the defaults are combined
+func TransportHandler(client *clients.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := client.TransportSettings()
+		states := make([]transportState, 0, len(settings))
+		for _, name := range slices.Sorted(maps.Keys(settings)) {
+			s := settings[name]
+			states = append(states, transportState{
+				Service:               name,
+				TimeoutMS:             s.Timeout.Milliseconds(),
+				MaxIdleConns:          s.MaxIdleConns,
+				MaxIdleConnsPerHost:   s.MaxIdleConnsPerHost,
+				IdleConnTimeoutMS:     s.IdleConnTimeout.Milliseconds(),
+				DialTimeoutMS:         s.DialTimeout.Milliseconds(),
+				TLSHandshakeTimeoutMS: s.TLSHandshakeTimeout.Milliseconds(),
+			})
+		}
+		writeJSON(w, map[string]interface{}{"clients": states})
+	})
+}
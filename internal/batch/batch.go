@@ -0,0 +1,170 @@
+// Package batch groups items from many requests and processes them together.
+// The batch span is a new root with a link to every request it serves, which
+// is how one operation shows up in many traces at once.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// ErrStopped is returned by Add once the batcher has shut down
+var ErrStopped = errors.New("batcher stopped")
+
+// Result tells a caller which batch processed its item
+type Result struct {
+	BatchID  string
+	Size     int
+	Position int
+	Waited   time.Duration
+
+	// TraceID is the batch span's trace, for jumping to it from the request
+	TraceID string
+}
+
+// pending is an item waiting for its batch
+type pending struct {
+	item   string
+	origin trace.SpanContext
+	added  time.Time
+	done   chan Result
+}
+
+// Batcher collects items until the batch is full or the window closes
+type Batcher struct {
+	sdk     *tracekit.SDK
+	items   chan pending
+	stopped chan struct{}
+	maxSize int
+	window  time.Duration
+}
+
+// New creates a Batcher that flushes after maxSize items or window,
+// whichever comes first
+func New(sdk *tracekit.SDK, maxSize int, window time.Duration) *Batcher {
+	return &Batcher{
+		sdk:     sdk,
+		items:   make(chan pending),
+		stopped: make(chan struct{}),
+		maxSize: maxSize,
+		window:  window,
+	}
+}
+
+// Add queues item and waits for the batch that processes it. The span in
+// ctx becomes one of the batch span's links.
+func (b *Batcher) Add(ctx context.Context, item string) (Result, error) {
+	p := pending{
+		item:   item,
+		origin: trace.SpanContextFromContext(ctx),
+		added:  time.Now(),
+		done:   make(chan Result, 1),
+	}
+
+	select {
+	case b.items <- p:
+	case <-b.stopped:
+		return Result{}, ErrStopped
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+
+	select {
+	case result := <-p.done:
+		return result, nil
+	case <-ctx.Done():
+		// The batch still processes the item; the caller just stops waiting
+		return Result{}, ctx.Err()
+	}
+}
+
+// Run collects and processes batches until ctx is canceled, then flushes
+// whatever is still waiting
+func (b *Batcher) Run(ctx context.Context) {
+	defer close(b.stopped)
+
+	for {
+		// Wait for the first item before starting the window
+		var batch []pending
+		select {
+		case <-ctx.Done():
+			return
+		case p := <-b.items:
+			batch = append(batch, p)
+		}
+
+		timer := time.NewTimer(b.window)
+	collect:
+		for len(batch) < b.maxSize {
+			select {
+			case p := <-b.items:
+				batch = append(batch, p)
+			case <-timer.C:
+				break collect
+			case <-ctx.Done():
+				break collect
+			}
+		}
+		timer.Stop()
+
+		b.process(batch)
+	}
+}
+
+// process handles one batch in a root span linked to every originating request
+func (b *Batcher) process(batch []pending) {
+	items := make([]string, 0, len(batch))
+	links := make([]trace.Link, 0, len(batch))
+	for _, p := range batch {
+		items = append(items, p.item)
+		if p.origin.IsValid() {
+			links = append(links, trace.Link{
+				SpanContext: p.origin,
+				Attributes:  []attribute.KeyValue{attribute.String("link.type", "batched_request")},
+			})
+		}
+	}
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "batch.process",
+		trace.WithNewRoot(),
+		trace.WithLinks(links...),
+	)
+	defer span.End()
+
+	batchID := fmt.Sprintf("batch-%d", time.Now().UnixNano())
+	b.sdk.AddAttribute(span, "batch.id", batchID)
+	b.sdk.AddIntAttribute(span, "batch.size", int64(len(batch)))
+	b.sdk.AddIntAttribute(span, "batch.max_size", int64(b.maxSize))
+	span.SetAttributes(attribute.StringSlice("batch.items", items))
+	b.sdk.AddIntAttribute(span, "batch.oldest_wait_ms", time.Since(batch[0].added).Milliseconds())
+
+	// Simulated work: a fixed setup cost plus a little per item
+	time.Sleep(50*time.Millisecond + time.Duration(len(batch))*5*time.Millisecond)
+
+	b.sdk.SetSuccess(span)
+	logging.FromContext(ctx).Info("📦 Batch processed",
+		zap.String("batch.id", batchID),
+		zap.Int("batch.size", len(batch)),
+	)
+
+	traceID := span.SpanContext().TraceID().String()
+	for i, p := range batch {
+		p.done <- Result{
+			BatchID:  batchID,
+			Size:     len(batch),
+			Position: i + 1,
+			Waited:   time.Since(p.added),
+			TraceID:  traceID,
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Package cache is a traced cache-aside layer backed by Redis.
+// Every lookup records cache.hit, cache.key, and lookup latency on its span.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Cache stores JSON values in Redis
+type Cache struct {
+	rdb *redis.Client
+	sdk *tracekit.SDK
+	ttl time.Duration
+}
+
+// New connects to Redis at addr. Entries expire after ttl.
+func New(ctx context.Context, sdk *tracekit.SDK, addr string, ttl time.Duration) (*Cache, error) {
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
+		return nil, err
+	}
+	return &Cache{rdb: rdb, sdk: sdk, ttl: ttl}, nil
+}
+
+// Get decodes the value stored at key into dst.
+// It reports whether the key was found; a miss is not an error.
+func (c *Cache) Get(ctx context.Context, key string, dst any) (bool, error) {
+	ctx, span := c.sdk.StartSpan(ctx, "cache.get")
+	defer span.End()
+
+	c.sdk.AddAttribute(span, "cache.system", "redis")
+	c.sdk.AddAttribute(span, "cache.key", key)
+
+	start := time.Now()
+	data, err := c.rdb.Get(ctx, key).Bytes()
+	c.sdk.AddFloatAttribute(span, "cache.lookup_ms", float64(time.Since(start).Microseconds())/1000)
+
+	if errors.Is(err, redis.Nil) {
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		c.sdk.SetSuccess(span)
+		return false, nil
+	}
+	if err != nil {
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		c.sdk.RecordError(span, err)
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, dst); err != nil {
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		c.sdk.RecordError(span, err)
+		return false, err
+	}
+
+	span.SetAttributes(attribute.Bool("cache.hit", true))
+	c.sdk.AddIntAttribute(span, "cache.value_bytes", int64(len(data)))
+	c.sdk.SetSuccess(span)
+	return true, nil
+}
+
+// Set stores value at key as JSON
+func (c *Cache) Set(ctx context.Context, key string, value any) error {
+	ctx, span := c.sdk.StartSpan(ctx, "cache.set")
+	defer span.End()
+
+	c.sdk.AddAttribute(span, "cache.system", "redis")
+	c.sdk.AddAttribute(span, "cache.key", key)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.sdk.RecordError(span, err)
+		return err
+	}
+	c.sdk.AddIntAttribute(span, "cache.value_bytes", int64(len(data)))
+
+	if err := c.rdb.Set(ctx, key, data, c.ttl).Err(); err != nil {
+		c.sdk.RecordError(span, err)
+		return err
+	}
+	c.sdk.SetSuccess(span)
+	return nil
+}
+
+// Delete removes key, so the next Get misses and reloads from the origin
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	ctx, span := c.sdk.StartSpan(ctx, "cache.delete")
+	defer span.End()
+
+	c.sdk.AddAttribute(span, "cache.system", "redis")
+	c.sdk.AddAttribute(span, "cache.key", key)
+
+	if err := c.rdb.Del(ctx, key).Err(); err != nil {
+		c.sdk.RecordError(span, err)
+		return err
+	}
+	c.sdk.SetSuccess(span)
+	return nil
+}
+
+// Close closes the Redis connection
+func (c *Cache) Close() error {
+	return c.rdb.Close()
+}
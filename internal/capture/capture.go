@@ -0,0 +1,193 @@
+// Package capture records request and response bodies on the server span for
+// debugging. Bodies are size-limited and sensitive fields are redacted before
+// anything reaches the span.
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Redacted replaces the value of every redacted field
+const Redacted = "[REDACTED]"
+
+// DefaultRedactFields are redacted when no list is configured
+var DefaultRedactFields = []string{
+	"password", "card_number", "credit_card", "cvv", "ssn",
+	"api_key", "token", "secret", "authorization",
+}
+
+// Capturer records bodies of up to maxBytes, redacting the listed fields
+type Capturer struct {
+	maxBytes int
+	redact   map[string]bool
+}
+
+// New creates a Capturer. Field names are matched case-insensitively at any
+// depth of a JSON document, and as form keys.
+func New(maxBytes int, redactFields []string) *Capturer {
+	redact := make(map[string]bool, len(redactFields))
+	for _, field := range redactFields {
+		redact[strings.ToLower(field)] = true
+	}
+	return &Capturer{maxBytes: maxBytes, redact: redact}
+}
+
+// Middleware records the request and response bodies on the server span.
+// Register it after the SDK middleware.
+func (cp *Capturer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span := trace.SpanFromContext(c.Request.Context())
+		if !span.IsRecording() {
+			c.Next()
+			return
+		}
+
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			// Read one byte past the limit to tell whether the body was cut off,
+			// then hand the handler the full body again
+			head, _ := io.ReadAll(io.LimitReader(c.Request.Body, int64(cp.maxBytes)+1))
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+			cp.record(span, "http.request.body", c.ContentType(), head)
+		}
+
+		w := &teeWriter{ResponseWriter: c.Writer, limit: cp.maxBytes + 1}
+		c.Writer = w
+		c.Next()
+
+		if w.buf.Len() > 0 {
+			cp.record(span, "http.response.body", w.Header().Get("Content-Type"), w.buf.Bytes())
+		}
+	}
+}
+
+// record adds the redacted body and its metadata under prefix
+func (cp *Capturer) record(span trace.Span, prefix, contentType string, body []byte) {
+	truncated := len(body) > cp.maxBytes
+	if truncated {
+		body = body[:cp.maxBytes]
+	}
+	span.SetAttributes(
+		attribute.Bool(prefix+".truncated", truncated),
+		attribute.Int(prefix+".captured_bytes", len(body)),
+	)
+
+	captured, ok := cp.redactBody(contentType, body, truncated)
+	if !ok {
+		span.SetAttributes(attribute.String(prefix, "[omitted: "+omitReason(contentType, truncated)+"]"))
+		return
+	}
+	span.SetAttributes(attribute.String(prefix, captured))
+}
+
+// redactBody returns body with sensitive fields redacted. Bodies that can't
+// be redacted reliably (unknown formats, truncated JSON) are not captured.
+func (cp *Capturer) redactBody(contentType string, body []byte, truncated bool) (string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if truncated {
+			return "", false
+		}
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return "", false
+		}
+		redacted, err := json.Marshal(cp.redactValue(doc))
+		if err != nil {
+			return "", false
+		}
+		return string(redacted), true
+
+	case mediaType == "application/x-www-form-urlencoded":
+		if truncated {
+			return "", false
+		}
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "", false
+		}
+		for key := range values {
+			if cp.redact[strings.ToLower(key)] {
+				values[key] = []string{Redacted}
+			}
+		}
+		return values.Encode(), true
+
+	case strings.HasPrefix(mediaType, "text/"):
+		// Plain text has no fields to redact; a truncated prefix is still useful
+		return string(body), true
+	}
+	return "", false
+}
+
+// redactValue walks a decoded JSON document replacing sensitive fields
+func (cp *Capturer) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if cp.redact[strings.ToLower(key)] {
+				v[key] = Redacted
+			} else {
+				v[key] = cp.redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = cp.redactValue(value)
+		}
+	}
+	return v
+}
+
+func omitReason(contentType string, truncated bool) string {
+	if truncated {
+		return "body exceeds capture limit"
+	}
+	if contentType == "" {
+		return "no content type"
+	}
+	return "unsupported content type " + contentType
+}
+
+// teeWriter copies the first limit bytes of the response into buf
+type teeWriter struct {
+	gin.ResponseWriter
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.tee(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.tee([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *teeWriter) tee(b []byte) {
+	if room := w.limit - w.buf.Len(); room > 0 {
+		if len(b) > room {
+			b = b[:room]
+		}
+		w.buf.Write(b)
+	}
+}
+
+// readCloser pairs the replayed body with the original body's Close
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
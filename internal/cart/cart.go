@@ -0,0 +1,156 @@
+// Package cart keeps shopping carts in an in-memory session store. Every
+// load and save records session.id and cart.id on its span, so one shopper's
+// journey can be followed across the separate traces of each request.
+package cart
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrEmpty is returned when checking out a cart without items
+var ErrEmpty = errors.New("cart is empty")
+
+// Item is one line of a cart
+type Item struct {
+	SKU      string  `json:"sku"`
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
+// Cart is the shopping cart of one session
+type Cart struct {
+	ID        string    `json:"cart_id"`
+	SessionID string    `json:"session_id"`
+	Items     []Item    `json:"items"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Total is the summed price of every item
+func (c *Cart) Total() float64 {
+	var total float64
+	for _, it := range c.Items {
+		total += it.Price * float64(it.Quantity)
+	}
+	return total
+}
+
+// ItemCount is the summed quantity of every item
+func (c *Cart) ItemCount() int {
+	var n int
+	for _, it := range c.Items {
+		n += it.Quantity
+	}
+	return n
+}
+
+// Add puts quantity of sku in the cart, merging with an existing line
+func (c *Cart) Add(item Item) {
+	for i := range c.Items {
+		if c.Items[i].SKU == item.SKU {
+			c.Items[i].Quantity += item.Quantity
+			c.Items[i].Price = item.Price
+			return
+		}
+	}
+	c.Items = append(c.Items, item)
+}
+
+// Store holds one cart per session. Sessions idle for longer than ttl are
+// dropped on the next access.
+type Store struct {
+	sdk *tracekit.SDK
+	ttl time.Duration
+
+	mu    sync.Mutex
+	carts map[string]*Cart
+}
+
+// NewStore creates an empty store
+func NewStore(sdk *tracekit.SDK, ttl time.Duration) *Store {
+	return &Store{sdk: sdk, ttl: ttl, carts: make(map[string]*Cart)}
+}
+
+// NewSessionID returns a random session identifier
+func NewSessionID() string {
+	return "sess-" + randomHex(8)
+}
+
+// Load returns a copy of the session's cart, starting an empty one when the
+// session has none or it has expired
+func (s *Store) Load(ctx context.Context, sessionID string) *Cart {
+	_, span := s.sdk.StartSpan(ctx, "session.load")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "session.store", "memory")
+	s.sdk.AddAttribute(span, "session.id", sessionID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.carts[sessionID]
+	if ok && time.Since(c.UpdatedAt) > s.ttl {
+		delete(s.carts, sessionID)
+		s.sdk.AddEvent(span, "session.expired")
+		ok = false
+	}
+	span.SetAttributes(attribute.Bool("session.found", ok))
+	if !ok {
+		c = &Cart{ID: "cart-" + randomHex(6), SessionID: sessionID, UpdatedAt: time.Now()}
+	}
+
+	s.sdk.AddAttribute(span, "cart.id", c.ID)
+	s.sdk.AddIntAttribute(span, "cart.item_count", int64(c.ItemCount()))
+	s.sdk.SetSuccess(span)
+
+	cp := *c
+	cp.Items = append([]Item(nil), c.Items...)
+	return &cp
+}
+
+// Save stores c as its session's cart
+func (s *Store) Save(ctx context.Context, c *Cart) {
+	_, span := s.sdk.StartSpan(ctx, "session.save")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "session.store", "memory")
+	s.sdk.AddAttribute(span, "session.id", c.SessionID)
+	s.sdk.AddAttribute(span, "cart.id", c.ID)
+	s.sdk.AddIntAttribute(span, "cart.item_count", int64(c.ItemCount()))
+
+	c.UpdatedAt = time.Now()
+
+	s.mu.Lock()
+	s.carts[c.SessionID] = c
+	s.mu.Unlock()
+
+	s.sdk.SetSuccess(span)
+}
+
+// Delete removes the session's cart, so its next Load starts a new one
+func (s *Store) Delete(ctx context.Context, sessionID string) {
+	_, span := s.sdk.StartSpan(ctx, "session.delete")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "session.store", "memory")
+	s.sdk.AddAttribute(span, "session.id", sessionID)
+
+	s.mu.Lock()
+	delete(s.carts, sessionID)
+	s.mu.Unlock()
+
+	s.sdk.SetSuccess(span)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,196 @@
+// Package certs serves the HTTPS listener's certificate and the client
+// certificate of downstream calls, reloading them from disk when the files
+// change so rotated certificates are picked up without a restart. Each
+// reload is traced as a "tls.certificate.reload" root span.
+package certs
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// Reloader holds the current certificate of a cert/key file pair
+type Reloader struct {
+	sdk               *tracekit.SDK
+	certFile, keyFile string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	// seen fingerprints the files last loaded, or last tried
+	seen [sha256.Size]byte
+}
+
+// New loads the certificate in certFile and its key in keyFile
+func New(sdk *tracekit.SDK, certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{sdk: sdk, certFile: certFile, keyFile: keyFile}
+	seen, err := r.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := load(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	r.cert, r.seen = cert, seen
+	return r, nil
+}
+
+// TLSConfig is the server configuration serving the current certificate
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.cert, nil
+		},
+	}
+}
+
+// ClientCertificate presents the current certificate to servers that ask
+// for one, for tls.Config.GetClientCertificate
+func (r *Reloader) ClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Leaf is the current certificate, parsed
+func (r *Reloader) Leaf() *x509.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert.Leaf
+}
+
+// Watch reloads the certificate whenever either file's contents change,
+// checking every interval until ctx is done. Contents rather than
+// modification times are compared, since mv and symlink swaps, as in
+// Kubernetes secret volumes, can keep the old times.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			seen, err := r.fingerprint()
+			r.mu.RLock()
+			changed := err == nil && seen != r.seen
+			r.mu.RUnlock()
+			if changed {
+				r.Reload(ctx)
+			}
+		}
+	}
+}
+
+// Reload reads the files again. A pair that doesn't load, e.g. because only
+// one file has been rotated so far, is rejected and the current certificate
+// kept; it's retried on the next change.
+func (r *Reloader) Reload(ctx context.Context) error {
+	ctx, span := tracing.Tracer().Start(ctx, "tls.certificate.reload", trace.WithNewRoot())
+	defer span.End()
+	r.sdk.AddAttribute(span, "tls.certificate.file", r.certFile)
+	logger := logging.FromContext(ctx)
+
+	cert, previous, err := r.swap()
+	if err != nil {
+		r.sdk.RecordError(span, err)
+		logger.Error("❌ TLS certificate reload failed, keeping the current one", zap.String("cert", r.certFile), zap.Error(err))
+		return err
+	}
+
+	span.SetAttributes(certAttributes(cert.Leaf)...)
+	r.sdk.AddAttribute(span, "tls.certificate.previous_serial", previous.Leaf.SerialNumber.String())
+	r.sdk.SetSuccess(span)
+	logger.Info("🔐 TLS certificate reloaded",
+		zap.String("subject", cert.Leaf.Subject.String()),
+		zap.Time("not_after", cert.Leaf.NotAfter),
+	)
+	return nil
+}
+
+// swap loads the files and replaces the current certificate, returning
+// both. Files that fail to load are remembered as seen too, so they aren't
+// retried until they change again.
+func (r *Reloader) swap() (cert, previous *tls.Certificate, err error) {
+	seen, err := r.fingerprint()
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err = load(r.certFile, r.keyFile)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seen = seen
+	if err != nil {
+		return nil, nil, err
+	}
+	previous, r.cert = r.cert, cert
+	return cert, previous, nil
+}
+
+// fingerprint hashes the contents of the cert and key files
+func (r *Reloader) fingerprint() ([sha256.Size]byte, error) {
+	h := sha256.New()
+	for _, file := range []string{r.certFile, r.keyFile} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+		h.Write(data)
+	}
+	return [sha256.Size]byte(h.Sum(nil)), nil
+}
+
+// LoadCAs reads a PEM bundle of CA certificates to verify peers against
+func LoadCAs(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates in %s", file)
+	}
+	return pool, nil
+}
+
+// load reads a cert/key pair and parses its leaf certificate
+func load(certFile, keyFile string) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, fmt.Errorf("parse TLS certificate: %w", err)
+		}
+	}
+	return &cert, nil
+}
+
+// certAttributes describe a certificate on a span
+func certAttributes(leaf *x509.Certificate) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("tls.certificate.subject", leaf.Subject.String()),
+		attribute.String("tls.certificate.serial", leaf.SerialNumber.String()),
+		attribute.String("tls.certificate.not_after", leaf.NotAfter.UTC().Format(time.RFC3339)),
+	}
+}
@@ -0,0 +1,33 @@
+package certs
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware records how an HTTPS request's connection was negotiated on
+// the server span. Plain HTTP requests are left alone.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if state := c.Request.TLS; state != nil {
+			trace.SpanFromContext(c.Request.Context()).SetAttributes(Attributes(state)...)
+		}
+		c.Next()
+	}
+}
+
+// Attributes describe a negotiated TLS connection on a span
+func Attributes(state *tls.ConnectionState) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("tls.protocol.name", "tls"),
+		attribute.String("tls.protocol.version", strings.TrimPrefix(tls.VersionName(state.Version), "TLS ")),
+		attribute.String("tls.cipher", tls.CipherSuiteName(state.CipherSuite)),
+		attribute.Bool("tls.resumed", state.DidResume),
+		attribute.String("tls.client.server_name", state.ServerName),
+		attribute.String("tls.next_protocol", state.NegotiatedProtocol),
+	}
+}
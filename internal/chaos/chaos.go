@@ -0,0 +1,75 @@
+// Package chaos injects artificial latency and failures so the example can
+// produce slow and failing traces on demand for dashboards and alerts.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+)
+
+// MaxLatency caps injected delays so a typo can't hang a request for hours
+const MaxLatency = 30 * time.Second
+
+// ErrInjected is returned when a fault roll fails the request
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Fault describes what to inject into a request
+type Fault struct {
+	Latency   time.Duration
+	Jitter    time.Duration
+	ErrorRate float64
+}
+
+// Validate reports whether f is within the supported bounds
+func (f Fault) Validate() error {
+	if f.Latency < 0 || f.Jitter < 0 {
+		return errors.New("latency and jitter must not be negative")
+	}
+	if f.Latency+f.Jitter > MaxLatency {
+		return fmt.Errorf("latency plus jitter must not exceed %s", MaxLatency)
+	}
+	if f.ErrorRate < 0 || f.ErrorRate > 1 {
+		return errors.New("error rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// Inject applies f inside a "chaos.inject" span. It returns ErrInjected when
+// the error roll fails, or ctx.Err() if the caller gives up while sleeping.
+func Inject(ctx context.Context, sdk *tracekit.SDK, f Fault) (time.Duration, error) {
+	ctx, span := sdk.StartSpan(ctx, "chaos.inject")
+	defer span.End()
+
+	delay := f.Latency
+	if f.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(f.Jitter) + 1))
+	}
+
+	sdk.AddIntAttribute(span, "chaos.latency_ms", delay.Milliseconds())
+	sdk.AddFloatAttribute(span, "chaos.error_rate", f.ErrorRate)
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			sdk.RecordError(span, ctx.Err())
+			return delay, ctx.Err()
+		}
+	}
+
+	if f.ErrorRate > 0 && rand.Float64() < f.ErrorRate {
+		sdk.AddEvent(span, "chaos.failure_injected")
+		sdk.RecordError(span, ErrInjected)
+		return delay, ErrInjected
+	}
+
+	sdk.SetSuccess(span)
+	return delay, nil
+}
@@ -0,0 +1,50 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Defaults are the faults injected into every request that doesn't send
+// its own Header, so a demo can slow down or fail all traffic for a while.
+// The zero value injects nothing.
+type Defaults struct {
+	mu     sync.RWMutex
+	fault  Fault
+	status int
+}
+
+// Set replaces the default faults; status is the failure code, 500 when
+// zero. A zero fault turns the defaults off.
+func (d *Defaults) Set(f Fault, status int) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if status == 0 {
+		status = 500
+	}
+	if status < 400 || status > 599 {
+		return errors.New("status must be between 400 and 599")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.fault, d.status = f, status
+	return nil
+}
+
+// Get returns the default faults and failure status
+func (d *Defaults) Get() (Fault, int) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.status == 0 {
+		return d.fault, 500
+	}
+	return d.fault, d.status
+}
+
+// spec describes a fault in the Header's syntax, for chaos.spec
+func spec(f Fault, status int) string {
+	return fmt.Sprintf("latency=%d;jitter=%d;error=%g;status=%d",
+		f.Latency.Milliseconds(), f.Jitter.Milliseconds(), f.ErrorRate, status)
+}
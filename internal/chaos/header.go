@@ -0,0 +1,136 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header asks for faults in one request, e.g. "latency=200;error=1"
+const Header = "X-Chaos"
+
+// ParseHeader reads a Header value: semicolon-separated latency and jitter in
+// milliseconds, error as a rate from 0 to 1, and the status to fail with
+// (500 by default)
+func ParseHeader(value string) (Fault, int, error) {
+	var fault Fault
+	status := 500
+
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(part, "=")
+		if !ok {
+			return fault, 0, fmt.Errorf("%s: %q is not key=value", Header, part)
+		}
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case "latency":
+			fault.Latency, err = parseMillis(raw)
+		case "jitter":
+			fault.Jitter, err = parseMillis(raw)
+		case "error":
+			fault.ErrorRate, err = strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		case "status":
+			status, err = strconv.Atoi(strings.TrimSpace(raw))
+			if err == nil && (status < 400 || status > 599) {
+				err = errors.New("must be between 400 and 599")
+			}
+		default:
+			return fault, 0, fmt.Errorf("%s: unknown fault %q", Header, key)
+		}
+		if err != nil {
+			return fault, 0, fmt.Errorf("%s: invalid %s: %w", Header, key, err)
+		}
+	}
+	if err := fault.Validate(); err != nil {
+		return fault, 0, fmt.Errorf("%s: %w", Header, err)
+	}
+	return fault, status, nil
+}
+
+// parseMillis reads a delay in milliseconds. One past MaxLatency is refused
+// before it's converted, where it could overflow into a short or negative
+// Duration.
+func parseMillis(raw string) (time.Duration, error) {
+	ms, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if ms < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	if ms > MaxLatency.Milliseconds() {
+		return 0, fmt.Errorf("must not exceed %d", MaxLatency.Milliseconds())
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Middleware injects the faults a request asks for in its Header, when
+// header is true, so one request can be slowed down or failed without
+// changing any configuration. Requests without the header get the
+// defaults, unless their path is in skipPaths. The server span gets
+// chaos.injected=true, chaos.source (the header or "defaults"), and the
+// faults in chaos.spec; untouched requests pass through. Register it after
+// the SDK middleware.
+func Middleware(sdk *tracekit.SDK, defaults *Defaults, header bool, skipPaths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span := trace.SpanFromContext(c.Request.Context())
+		source, value := Header, ""
+		if header {
+			value = c.GetHeader(Header)
+		}
+
+		var fault Fault
+		var status int
+		if value != "" {
+			var err error
+			fault, status, err = ParseHeader(value)
+			if err != nil {
+				span.SetAttributes(attribute.Bool("chaos.injected", false))
+				c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+				return
+			}
+		} else {
+			fault, status = defaults.Get()
+			if fault == (Fault{}) || slices.Contains(skipPaths, c.Request.URL.Path) {
+				c.Next()
+				return
+			}
+			source, value = "defaults", spec(fault, status)
+		}
+		span.SetAttributes(
+			attribute.Bool("chaos.injected", true),
+			attribute.String("chaos.source", source),
+			attribute.String("chaos.spec", value),
+		)
+
+		delay, err := Inject(c.Request.Context(), sdk, fault)
+		if errors.Is(err, ErrInjected) {
+			span.SetAttributes(attribute.Int("chaos.status", status))
+			c.AbortWithStatusJSON(status, gin.H{
+				"error":      "Injected failure",
+				"source":     source,
+				"latency_ms": delay.Milliseconds(),
+			})
+			return
+		}
+		if err != nil {
+			// The client went away mid-sleep; nobody is left to answer
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
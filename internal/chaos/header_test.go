@@ -0,0 +1,40 @@
+package chaos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tracekit-Dev/test-app/internal/chaos"
+)
+
+func TestParseHeader(t *testing.T) {
+	fault, status, err := chaos.ParseHeader("latency=200; jitter=50;error=0.5;status=503")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := chaos.Fault{Latency: 200 * time.Millisecond, Jitter: 50 * time.Millisecond, ErrorRate: 0.5}
+	if fault != want || status != 503 {
+		t.Errorf("got %+v and status %d, want %+v and 503", fault, status, want)
+	}
+}
+
+func TestParseHeaderRejects(t *testing.T) {
+	for _, value := range []string{
+		// 2^64 ns is 18446744073709.55 ms, so this wraps around to 448µs
+		"latency=18446744073710",
+		"jitter=18446744073710",
+		"latency=9223372036854775807",
+		"latency=-9223372036854775808",
+		"latency=30001",
+		"latency=20000;jitter=20000",
+		"latency=-1",
+		"error=2",
+		"status=200",
+		"latency",
+		"speed=1",
+	} {
+		if fault, _, err := chaos.ParseHeader(value); err == nil {
+			t.Errorf("%q: got %+v, want an error", value, fault)
+		}
+	}
+}
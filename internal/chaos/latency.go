@@ -0,0 +1,74 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// Distributions supported by Latency
+var Distributions = []string{"constant", "uniform", "normal", "lognormal", "exponential", "pareto"}
+
+// z99 is the standard normal quantile at 0.99
+const z99 = 2.326348
+
+// Latency is a delay distribution fitted to a median and a 99th percentile,
+// the two numbers latency dashboards are usually read by
+type Latency struct {
+	Dist string
+	P50  time.Duration
+	P99  time.Duration
+}
+
+// Validate reports whether l names a known distribution with usable
+// percentiles
+func (l Latency) Validate() error {
+	if !slices.Contains(Distributions, l.Dist) {
+		return fmt.Errorf("dist must be one of %v", Distributions)
+	}
+	if l.P50 <= 0 || l.P99 < l.P50 {
+		return errors.New("p50 must be positive and p99 at least p50")
+	}
+	if l.P99 > MaxLatency {
+		return fmt.Errorf("p99 must not exceed %s", MaxLatency)
+	}
+	return nil
+}
+
+// Sample draws one delay, capped at MaxLatency since heavy tails can
+// otherwise draw hours. Call Validate first.
+func (l Latency) Sample() time.Duration {
+	p50, p99 := float64(l.P50), float64(l.P99)
+
+	var d float64
+	switch l.Dist {
+	case "constant":
+		d = p50
+	case "uniform":
+		// p50 is the midpoint and p99 sits 99% of the way across
+		width := (p99 - p50) / 0.49
+		d = p50 - width/2 + rand.Float64()*width
+	case "normal":
+		d = p50 + rand.NormFloat64()*(p99-p50)/z99
+	case "lognormal":
+		sigma := (math.Log(p99) - math.Log(p50)) / z99
+		d = math.Exp(math.Log(p50) + rand.NormFloat64()*sigma)
+	case "exponential":
+		// Shifted so both percentiles fit: p50 = shift + mean*ln2
+		mean := (p99 - p50) / (math.Log(100) - math.Ln2)
+		d = p50 - mean*math.Ln2 + rand.ExpFloat64()*mean
+	case "pareto":
+		if p99 == p50 {
+			d = p50
+			break
+		}
+		// Quantiles are scale*(1-q)^(-1/alpha), so p99/p50 = 50^(1/alpha)
+		alpha := math.Log(50) / math.Log(p99/p50)
+		scale := p50 / math.Pow(2, 1/alpha)
+		d = scale / math.Pow(1-rand.Float64(), 1/alpha)
+	}
+	return time.Duration(min(max(d, 0), float64(MaxLatency)))
+}
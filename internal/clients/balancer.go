@@ -0,0 +1,121 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Load balancing policies a service can pick its replicas with
+const (
+	RoundRobin  = "round_robin"
+	LeastErrors = "least_errors"
+)
+
+// least_errors weighs the last errorWindow calls per replica, as long as
+// they're younger than errorMaxAge, so a replica that recovers gets its
+// traffic back
+const (
+	errorWindow = 20
+	errorMaxAge = 30 * time.Second
+)
+
+// replica is the instance of a service a call was sent to
+type replica struct {
+	url    string
+	index  int
+	errors int
+	of     int
+	policy string
+}
+
+type replicaKey struct{}
+
+// attributes describe the pick on the CLIENT span
+func (r replica) attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("loadbalancer.policy", r.policy),
+		attribute.String("loadbalancer.replica", r.url),
+		attribute.Int("loadbalancer.replica_index", r.index),
+		attribute.Int("loadbalancer.replicas", r.of),
+		attribute.Int("loadbalancer.replica_recent_errors", r.errors),
+	}
+}
+
+// balancers spread each service's calls over its replicas
+type balancers struct {
+	mu sync.Mutex
+	// next rotates the starting replica per service
+	next map[string]int
+	// outcomes are the latest results per replica URL
+	outcomes map[string][]outcome
+}
+
+type outcome struct {
+	at     time.Time
+	failed bool
+}
+
+func newBalancers() *balancers {
+	return &balancers{next: make(map[string]int), outcomes: make(map[string][]outcome)}
+}
+
+// pick chooses the replica for a call to svc. round_robin takes them in
+// turn; least_errors takes the one with the fewest failures among its
+// recent calls, in turn among equals.
+func (b *balancers) pick(svc Service) replica {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := len(svc.Replicas)
+	start := b.next[svc.Name] % n
+	b.next[svc.Name]++
+
+	best := replica{url: svc.Replicas[start], index: start, errors: b.failures(svc.Replicas[start])}
+	if svc.Balance == LeastErrors {
+		for i := 1; i < n; i++ {
+			index := (start + i) % n
+			if errs := b.failures(svc.Replicas[index]); errs < best.errors {
+				best = replica{url: svc.Replicas[index], index: index, errors: errs}
+			}
+		}
+	}
+	best.of, best.policy = n, svc.Balance
+	return best
+}
+
+// report records the outcome of a call to a replica
+func (b *balancers) report(url string, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	outcomes := append(b.outcomes[url], outcome{at: time.Now(), failed: failed})
+	if len(outcomes) > errorWindow {
+		outcomes = outcomes[len(outcomes)-errorWindow:]
+	}
+	b.outcomes[url] = outcomes
+}
+
+// failures counts the failures among a replica's recent calls
+func (b *balancers) failures(url string) int {
+	var n int
+	for _, o := range b.outcomes[url] {
+		if o.failed && time.Since(o.at) < errorMaxAge {
+			n++
+		}
+	}
+	return n
+}
+
+// withReplica hands the pick to the transport, which records it on the
+// CLIENT span
+func withReplica(ctx context.Context, r replica) context.Context {
+	return context.WithValue(ctx, replicaKey{}, r)
+}
+
+func replicaFrom(ctx context.Context) (replica, bool) {
+	r, ok := ctx.Value(replicaKey{}).(replica)
+	return r, ok
+}
@@ -0,0 +1,127 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Breaker settings shared by every downstream service
+const (
+	breakerFailureThreshold = 5
+	breakerOpenTimeout      = 30 * time.Second
+	breakerHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is returned when a call is rejected without being attempted
+// because the service's circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// errServerError marks 5xx responses as failures for the breaker; Get still
+// returns the response to the caller
+var errServerError = errors.New("downstream returned a server error")
+
+// breakers holds one circuit breaker per downstream service
+type breakers struct {
+	mu sync.Mutex
+	m  map[string]*gobreaker.CircuitBreaker[*Response]
+}
+
+func newBreakers() *breakers {
+	return &breakers{m: make(map[string]*gobreaker.CircuitBreaker[*Response])}
+}
+
+// get returns the breaker for svc, creating it on first use
+func (b *breakers) get(svc Service) *gobreaker.CircuitBreaker[*Response] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb, ok := b.m[svc.Name]
+	if !ok {
+		cb = gobreaker.NewCircuitBreaker[*Response](gobreaker.Settings{
+			Name:        svc.Name,
+			MaxRequests: breakerHalfOpenRequests,
+			Timeout:     breakerOpenTimeout,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= breakerFailureThreshold
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				zap.L().Warn("Circuit breaker state changed",
+					zap.String("service", name),
+					zap.String("from", from.String()),
+					zap.String("to", to.String()),
+				)
+			},
+			IsExcluded: excluded,
+		})
+		b.m[svc.Name] = cb
+	}
+	return cb
+}
+
+// excluded reports errors that say nothing about the service: a request we
+// couldn't even build, or one the caller gave up on
+func excluded(err error) bool {
+	var reqErr *RequestError
+	return errors.As(err, &reqErr) || errors.Is(err, context.Canceled)
+}
+
+// states reports the current state of every breaker created so far
+func (b *breakers) states() map[string]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make(map[string]string, len(b.m))
+	for name, cb := range b.m {
+		states[name] = cb.State().String()
+	}
+	return states
+}
+
+// execute runs call through svc's breaker and records the breaker state,
+// any transition it caused, and rejections on the span in ctx
+func (b *breakers) execute(ctx context.Context, svc Service, call func() (*Response, error)) (*Response, error) {
+	cb := b.get(svc)
+	span := trace.SpanFromContext(ctx)
+	before := cb.State()
+
+	resp, err := cb.Execute(func() (*Response, error) {
+		resp, err := call()
+		if err == nil && resp.StatusCode >= 500 {
+			return resp, errServerError
+		}
+		return resp, err
+	})
+
+	after := cb.State()
+	span.SetAttributes(
+		attribute.String("circuit_breaker.name", svc.Name),
+		attribute.String("circuit_breaker.state", after.String()),
+	)
+	if before != after {
+		span.AddEvent("circuit_breaker.state_change", trace.WithAttributes(
+			attribute.String("circuit_breaker.name", svc.Name),
+			attribute.String("circuit_breaker.from", before.String()),
+			attribute.String("circuit_breaker.to", after.String()),
+		))
+	}
+
+	switch {
+	case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests):
+		span.AddEvent("circuit_breaker.rejected", trace.WithAttributes(
+			attribute.String("circuit_breaker.name", svc.Name),
+			attribute.String("circuit_breaker.state", after.String()),
+		))
+		return nil, fmt.Errorf("%s: %w", svc.Name, ErrCircuitOpen)
+	case errors.Is(err, errServerError):
+		return resp, nil
+	}
+	return resp, err
+}
@@ -0,0 +1,258 @@
+// Package clients wraps the instrumented HTTP client used for calls to the
+// other TraceKit test services.
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+
+	"github.com/Tracekit-Dev/test-app/internal/resolver"
+)
+
+// Service is a downstream test app reachable over HTTP
+type Service struct {
+	Name string
+	URL  string
+
+	// Replicas are the base URLs calls are spread over, URL first, and
+	// Balance picks one per call: RoundRobin or LeastErrors. Without
+	// replicas every call goes to URL.
+	Replicas []string
+	Balance  string
+
+	// Route names the service's /api/call-<route> endpoint, and Span the
+	// span that endpoint opens around the call
+	Route string
+	Span  string
+	// Protocol is how the service is called: "http" is HTTP/1.1, or HTTP/2
+	// when an https:// service negotiates it, and "h2c" is HTTP/2 without TLS
+	Protocol string
+	// Language and Description are shown by the mocks and the status page
+	Language    string
+	Description string
+
+	// Timeout bounds a whole call, including reading the body, and
+	// MaxIdleConns the keep-alive pool; zero means the registry defaults
+	Timeout      time.Duration
+	MaxIdleConns int
+}
+
+// Host is the host:port svc is called on, or "" if its URL doesn't parse
+func (s Service) Host() string {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// Services are the downstream test apps used for cross-service communication,
+// as described by the service topology
+type Services struct {
+	// Downstream lists the services in call order
+	Downstream []Service
+
+	// Self is this app, for endpoints that call back into their own API.
+	// It isn't part of All.
+	Self Service
+}
+
+// All lists every downstream service in call order
+func (s Services) All() []Service {
+	return s.Downstream
+}
+
+// Lookup finds the downstream service served at /api/call-<route>
+func (s Services) Lookup(route string) (Service, bool) {
+	for _, svc := range s.Downstream {
+		if svc.Route == route {
+			return svc, true
+		}
+	}
+	return Service{}, false
+}
+
+// NameMappings maps the host:port of each service and its replicas to its
+// name. The SDK uses it to label CLIENT spans so TraceKit can draw the
+// service graph.
+func (s Services) NameMappings() map[string]string {
+	mappings := make(map[string]string)
+	for _, svc := range s.All() {
+		if host := svc.Host(); host != "" {
+			mappings[host] = svc.Name
+		}
+		for _, r := range svc.Replicas {
+			if u, err := url.Parse(r); err == nil && u.Host != "" {
+				mappings[u.Host] = svc.Name
+			}
+		}
+	}
+	return mappings
+}
+
+// Response is a decoded downstream JSON response
+type Response struct {
+	StatusCode int
+	Body       map[string]interface{}
+}
+
+// Client makes traced calls to downstream services, each through its own
+// HTTP client and guarded by its own circuit breaker
+type Client struct {
+	registry  *registry
+	breakers  *breakers
+	balancers *balancers
+
+	mu       sync.RWMutex
+	services Services
+}
+
+// Options tune the transports of every service; the zero value uses the
+// defaults
+type Options struct {
+	// TLS configures HTTPS calls, e.g. with a client certificate for mTLS
+	TLS *tls.Config
+	// Resolver looks up hostnames instead of the dialer, with a span per
+	// lookup
+	Resolver *resolver.Resolver
+	// Transport sizes the pools and bounds connection setup
+	Transport Transport
+}
+
+// New creates a Client with one instrumented HTTP client per service, so
+// every outgoing call produces a CLIENT span and carries the trace context
+func New(sdk *tracekit.SDK, services Services, opts Options) *Client {
+	return &Client{registry: newRegistry(sdk, opts), services: services, breakers: newBreakers(), balancers: newBalancers()}
+}
+
+// Services returns the downstream services this client is configured with
+func (c *Client) Services() Services {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.services
+}
+
+// SetServices replaces the downstream services, e.g. when the topology is
+// reloaded. A service whose timeout, pool size, or protocol changed gets a
+// new HTTP client; circuit breakers carry over by name.
+func (c *Client) SetServices(services Services) {
+	c.mu.Lock()
+	old := c.services
+	c.services = services
+	c.mu.Unlock()
+
+	for _, prev := range old.All() {
+		i := slices.IndexFunc(services.All(), func(svc Service) bool { return svc.Name == prev.Name })
+		if i < 0 || services.All()[i].Timeout != prev.Timeout || services.All()[i].MaxIdleConns != prev.MaxIdleConns || services.All()[i].Protocol != prev.Protocol {
+			c.registry.forget(prev.Name)
+		}
+	}
+}
+
+// HTTP returns the instrumented HTTP client used for svc
+func (c *Client) HTTP(svc Service) *http.Client {
+	return c.registry.client(svc)
+}
+
+// TransportSettings reports the effective settings of each service's HTTP
+// client: the configured services', and those of any other client made so
+// far, e.g. for the external API
+func (c *Client) TransportSettings() map[string]Settings {
+	settings := c.registry.settings()
+	services := c.Services()
+	for _, svc := range append(services.All(), services.Self) {
+		if _, ok := settings[svc.Name]; !ok {
+			settings[svc.Name] = c.registry.opts.Transport.settings(svc)
+		}
+	}
+	return settings
+}
+
+// BreakerStates reports the circuit breaker state of each service called so far
+func (c *Client) BreakerStates() map[string]string {
+	return c.breakers.states()
+}
+
+// Get calls path on svc and decodes the JSON body.
+// A body that isn't JSON is left nil rather than treated as an error.
+// Calls are rejected with ErrCircuitOpen while svc's breaker is open.
+func (c *Client) Get(ctx context.Context, svc Service, path string) (*Response, error) {
+	return c.breakers.execute(ctx, svc, func() (*Response, error) {
+		return c.do(ctx, svc, "GET", path, nil)
+	})
+}
+
+// Post sends body as JSON to path on svc and decodes the JSON response,
+// like Get
+func (c *Client) Post(ctx context.Context, svc Service, path string, body interface{}) (*Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, &RequestError{Err: err}
+	}
+	return c.breakers.execute(ctx, svc, func() (*Response, error) {
+		return c.do(ctx, svc, "POST", path, payload)
+	})
+}
+
+// do sends the request to svc, or to the replica its balancer picks. Server
+// errors and failed calls count against the replica, like they count for
+// the breaker.
+func (c *Client) do(ctx context.Context, svc Service, method, path string, payload []byte) (resp *Response, err error) {
+	base := svc.URL
+	if len(svc.Replicas) > 0 {
+		r := c.balancers.pick(svc)
+		base, ctx = r.url, withReplica(ctx, r)
+		defer func() {
+			if !excluded(err) {
+				c.balancers.report(r.url, err != nil || resp.StatusCode >= 500)
+			}
+		}()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, base+path, reqBody)
+	if err != nil {
+		return nil, &RequestError{Err: err}
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := c.registry.client(svc).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	body, _ := io.ReadAll(httpResp.Body)
+	var decoded map[string]interface{}
+	json.Unmarshal(body, &decoded)
+
+	return &Response{StatusCode: httpResp.StatusCode, Body: decoded}, nil
+}
+
+// RequestError reports that the outgoing request couldn't be built
+type RequestError struct {
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return "failed to create request: " + e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
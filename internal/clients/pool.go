@@ -0,0 +1,46 @@
+package clients
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptrace"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// poolTrace records on the CLIENT span which connection a call got from the
+// keep-alive pool, and how the pool is sized, so connection churn (new
+// connections where idle ones were expected) shows per request
+type poolTrace struct {
+	transport *http.Transport
+	next      http.RoundTripper
+}
+
+func (p poolTrace) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := trace.SpanFromContext(req.Context())
+	span.SetAttributes(
+		attribute.Int("http.client.pool.max_idle_conns", p.transport.MaxIdleConns),
+		attribute.Int("http.client.pool.max_idle_conns_per_host", p.transport.MaxIdleConnsPerHost),
+		attribute.Int("http.client.pool.max_conns_per_host", p.transport.MaxConnsPerHost),
+		attribute.Int64("http.client.pool.idle_conn_timeout_ms", p.transport.IdleConnTimeout.Milliseconds()),
+	)
+
+	ctx := httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			attrs := []attribute.KeyValue{
+				attribute.Bool("http.client.connection.reused", info.Reused),
+				attribute.Bool("http.client.connection.was_idle", info.WasIdle),
+				attribute.Int64("http.client.connection.idle_time_ms", info.IdleTime.Milliseconds()),
+			}
+			if addr, ok := info.Conn.RemoteAddr().(*net.TCPAddr); ok {
+				attrs = append(attrs,
+					attribute.String("network.peer.address", addr.IP.String()),
+					attribute.Int("network.peer.port", addr.Port),
+				)
+			}
+			span.SetAttributes(attrs...)
+		},
+	})
+	return p.next.RoundTrip(req.WithContext(ctx))
+}
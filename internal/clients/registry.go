@@ -0,0 +1,135 @@
+package clients
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/deadline"
+)
+
+// registry holds one instrumented HTTP client per downstream service, so a
+// slow service can't exhaust another's connections and each has a timeout
+// that suits it
+type registry struct {
+	sdk  *tracekit.SDK
+	opts Options
+
+	mu      sync.Mutex
+	clients map[string]*http.Client
+	// created are the services the clients were made for, as they were then
+	created map[string]Service
+}
+
+func newRegistry(sdk *tracekit.SDK, opts Options) *registry {
+	return &registry{sdk: sdk, opts: opts, clients: make(map[string]*http.Client), created: make(map[string]Service)}
+}
+
+// client returns the HTTP client for svc, creating it on first use
+func (r *registry) client(svc Service) *http.Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.clients[svc.Name]; ok {
+		return c
+	}
+
+	s := r.opts.Transport.settings(svc)
+	dialer := &net.Dialer{
+		Timeout:   s.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	dial := dialer.DialContext
+	if r.opts.Resolver != nil {
+		dial = r.opts.Resolver.DialContext(dialer)
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dial,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        s.MaxIdleConns,
+		MaxIdleConnsPerHost: s.MaxIdleConnsPerHost,
+		IdleConnTimeout:     s.IdleConnTimeout,
+		TLSHandshakeTimeout: s.TLSHandshakeTimeout,
+	}
+	if r.opts.TLS != nil {
+		transport.TLSClientConfig = r.opts.TLS.Clone()
+	}
+	if svc.Protocol == "h2c" {
+		// Prior knowledge: the service must accept HTTP/2 without the
+		// HTTP/1.1 upgrade dance
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+
+	// The SDK wraps our transport, so peerService, tlsTrace, poolTrace, and
+	// the deadline transport see the CLIENT span
+	c := r.sdk.HTTPClient(&http.Client{
+		Timeout: s.Timeout,
+		Transport: peerService{name: svc.Name, timeout: s.Timeout, next: tlsTrace{
+			next: poolTrace{transport: transport, next: deadline.Transport(transport)},
+		}},
+	})
+	r.clients[svc.Name] = c
+	r.created[svc.Name] = svc
+	return c
+}
+
+// settings reports the effective settings of every client created so far
+func (r *registry) settings() map[string]Settings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings := make(map[string]Settings, len(r.created))
+	for name, svc := range r.created {
+		settings[name] = r.opts.Transport.settings(svc)
+	}
+	return settings
+}
+
+// forget drops the HTTP client of a service, closing its idle connections,
+// so the next call builds one from the service's current settings
+func (r *registry) forget(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.clients[name]; ok {
+		c.CloseIdleConnections()
+		delete(r.clients, name)
+		delete(r.created, name)
+	}
+}
+
+// peerService tags each CLIENT span with the service it calls, the replica
+// picked, the client's timeout, and the HTTP version the call was answered
+// over, written like the server spans write it ("1.1", "2.0"). The
+// instrumentation records the version of the outgoing request, which is
+// always 1.1.
+type peerService struct {
+	name    string
+	timeout time.Duration
+	next    http.RoundTripper
+}
+
+func (p peerService) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := trace.SpanFromContext(req.Context())
+	span.SetAttributes(
+		attribute.String("peer.service", p.name),
+		attribute.Int64("http.client.timeout_ms", p.timeout.Milliseconds()),
+	)
+	if r, ok := replicaFrom(req.Context()); ok {
+		span.SetAttributes(r.attributes()...)
+	}
+	resp, err := p.next.RoundTrip(req)
+	if err == nil {
+		span.SetAttributes(attribute.String("network.protocol.version", strings.TrimPrefix(resp.Proto, "HTTP/")))
+	}
+	return resp, err
+}
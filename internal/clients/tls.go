@@ -0,0 +1,72 @@
+package clients
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/certs"
+)
+
+// HandshakeError is a failed TLS handshake with a downstream service, e.g.
+// an untrusted server certificate or a client certificate the server
+// rejected. It's set apart from refused connections and timeouts as
+// error.type=tls.handshake_failed on the CLIENT span.
+type HandshakeError struct {
+	Err error
+}
+
+func (e *HandshakeError) Error() string {
+	return "tls handshake failed: " + e.Err.Error()
+}
+
+func (e *HandshakeError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorType is what the HTTP instrumentation records as error.type
+func (e *HandshakeError) ErrorType() string {
+	return "tls.handshake_failed"
+}
+
+// tlsTrace records the TLS side of a call on its CLIENT span: how the
+// connection was negotiated, or why the handshake failed
+type tlsTrace struct {
+	next http.RoundTripper
+}
+
+func (t tlsTrace) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := trace.SpanFromContext(req.Context())
+
+	// The handshake may run on a dial that outlives this call, so its
+	// error is handed over rather than shared
+	handshake := make(chan error, 1)
+	ctx := httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err != nil {
+				select {
+				case handshake <- err:
+				default:
+				}
+			}
+		},
+	})
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		select {
+		case handshakeErr := <-handshake:
+			span.AddEvent("tls.handshake_failed", trace.WithAttributes(attribute.String("tls.error", handshakeErr.Error())))
+			return nil, &HandshakeError{Err: err}
+		default:
+			return nil, err
+		}
+	}
+	if resp.TLS != nil {
+		span.SetAttributes(certs.Attributes(resp.TLS)...)
+	}
+	return resp, nil
+}
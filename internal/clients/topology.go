@@ -0,0 +1,147 @@
+package clients
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// topology is the service topology file, services.yaml. JSON is valid YAML,
+// so the same file can be written as JSON.
+type topology struct {
+	Services []struct {
+		Name         string        `yaml:"name"`
+		Route        string        `yaml:"route"`
+		URL          string        `yaml:"url"`
+		URLs         []string      `yaml:"urls"`
+		Balance      string        `yaml:"balance"`
+		Protocol     string        `yaml:"protocol"`
+		Language     string        `yaml:"language"`
+		Description  string        `yaml:"description"`
+		Span         string        `yaml:"span"`
+		Timeout      time.Duration `yaml:"timeout"`
+		MaxIdleConns int           `yaml:"max_idle_conns"`
+	} `yaml:"services"`
+}
+
+// envRef matches ${VAR} and ${VAR:-default}
+var envRef = regexp.MustCompile(`\$\{(\w+)(?::-([^}]*))?\}`)
+
+// validRoute keeps /api/call-<route> a single, readable path segment
+var validRoute = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// reservedRoutes are the /api/call-<route> paths the app serves itself;
+// gin panics at startup on a second handler for the same path
+var reservedRoutes = map[string]bool{
+	"all":      true,
+	"grpc":     true,
+	"flaky":    true,
+	"legacy":   true,
+	"graphql":  true,
+	"soap":     true,
+	"external": true,
+}
+
+// ParseServices reads a service topology. ${VAR} and ${VAR:-default} are
+// replaced from the environment first, so one file serves local runs and
+// docker-compose alike. Unknown keys are rejected to catch typos.
+func ParseServices(data []byte) (Services, error) {
+	expanded := envRef.ReplaceAllStringFunc(string(data), func(ref string) string {
+		m := envRef.FindStringSubmatch(ref)
+		if v := os.Getenv(m[1]); v != "" {
+			return v
+		}
+		return m[2]
+	})
+
+	var t topology
+	if err := yaml.UnmarshalWithOptions([]byte(expanded), &t, yaml.Strict()); err != nil {
+		return Services{}, fmt.Errorf("parse service topology: %w", err)
+	}
+
+	var services Services
+	names := make(map[string]bool)
+	routes := make(map[string]bool)
+	for i, entry := range t.Services {
+		svc := Service{
+			Name:         entry.Name,
+			Balance:      entry.Balance,
+			Route:        entry.Route,
+			Span:         entry.Span,
+			Protocol:     entry.Protocol,
+			Language:     entry.Language,
+			Description:  entry.Description,
+			Timeout:      entry.Timeout,
+			MaxIdleConns: entry.MaxIdleConns,
+		}
+		if svc.Name == "" {
+			return Services{}, fmt.Errorf("service %d in the topology has no name", i+1)
+		}
+		if names[svc.Name] {
+			return Services{}, fmt.Errorf("service %q is in the topology twice", svc.Name)
+		}
+		names[svc.Name] = true
+
+		if svc.Route == "" {
+			svc.Route = svc.Name
+		}
+		if !validRoute.MatchString(svc.Route) {
+			return Services{}, fmt.Errorf("service %q: route %q must be lowercase letters, digits, and dashes", svc.Name, svc.Route)
+		}
+		if reservedRoutes[svc.Route] {
+			return Services{}, fmt.Errorf("service %q: route %q is reserved for /api/call-%s; set another route", svc.Name, svc.Route, svc.Route)
+		}
+		if routes[svc.Route] {
+			return Services{}, fmt.Errorf("service %q: route %q is already taken", svc.Name, svc.Route)
+		}
+		routes[svc.Route] = true
+
+		// url is a single replica; urls lists several
+		urls := entry.URLs
+		if entry.URL != "" {
+			urls = append([]string{entry.URL}, urls...)
+		}
+		if len(urls) == 0 || (entry.URL != "" && len(entry.URLs) > 0) {
+			return Services{}, fmt.Errorf("service %q needs either url or urls", svc.Name)
+		}
+		if svc.Protocol == "" {
+			svc.Protocol = "http"
+		}
+		if svc.Protocol != "http" && svc.Protocol != "h2c" {
+			return Services{}, fmt.Errorf("service %q: unsupported protocol %q (have http, h2c)", svc.Name, svc.Protocol)
+		}
+		for _, raw := range urls {
+			u, err := url.Parse(raw)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return Services{}, fmt.Errorf("service %q: url %q must be an absolute http(s) URL", svc.Name, raw)
+			}
+			if svc.Protocol == "h2c" && u.Scheme != "http" {
+				return Services{}, fmt.Errorf("service %q: h2c is HTTP/2 without TLS and needs http:// urls", svc.Name)
+			}
+			svc.Replicas = append(svc.Replicas, strings.TrimSuffix(raw, "/"))
+		}
+		svc.URL = svc.Replicas[0]
+		if svc.Balance == "" {
+			svc.Balance = RoundRobin
+		}
+		if svc.Balance != RoundRobin && svc.Balance != LeastErrors {
+			return Services{}, fmt.Errorf("service %q: unsupported balance %q (have %s, %s)", svc.Name, svc.Balance, RoundRobin, LeastErrors)
+		}
+		if svc.Timeout < 0 || svc.MaxIdleConns < 0 {
+			return Services{}, fmt.Errorf("service %q: timeout and max_idle_conns can't be negative", svc.Name)
+		}
+		if svc.Span == "" {
+			svc.Span = "call " + svc.Name
+		}
+		if svc.Description == "" {
+			svc.Description = "Call " + svc.Name
+		}
+		services.Downstream = append(services.Downstream, svc)
+	}
+	return services, nil
+}
@@ -0,0 +1,60 @@
+package clients
+
+import "time"
+
+// Transport sizes the keep-alive pools of the services' HTTP clients and
+// bounds how long setting up a connection may take. Zero fields take the
+// defaults; a service's own max_idle_conns replaces MaxIdleConns.
+type Transport struct {
+	MaxIdleConns int
+	// MaxIdleConnsPerHost matters for services with several replicas;
+	// zero matches MaxIdleConns
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// DialTimeout is capped by the service's timeout
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+}
+
+// Defaults for whatever neither the service nor the Transport sets
+const (
+	defaultTimeout             = 10 * time.Second
+	defaultMaxIdleConns        = 10
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultDialTimeout         = 5 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// Settings are the effective settings of a service's HTTP client
+type Settings struct {
+	Transport
+	Timeout time.Duration
+}
+
+// settings combines svc's own settings with t and the defaults
+func (t Transport) settings(svc Service) Settings {
+	s := Settings{Transport: t, Timeout: svc.Timeout}
+	if s.Timeout <= 0 {
+		s.Timeout = defaultTimeout
+	}
+	if svc.MaxIdleConns > 0 {
+		s.MaxIdleConns = svc.MaxIdleConns
+	}
+	if s.MaxIdleConns <= 0 {
+		s.MaxIdleConns = defaultMaxIdleConns
+	}
+	if s.MaxIdleConnsPerHost <= 0 {
+		s.MaxIdleConnsPerHost = s.MaxIdleConns
+	}
+	if s.IdleConnTimeout <= 0 {
+		s.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	if s.DialTimeout <= 0 {
+		s.DialTimeout = defaultDialTimeout
+	}
+	s.DialTimeout = min(s.DialTimeout, s.Timeout)
+	if s.TLSHandshakeTimeout <= 0 {
+		s.TLSHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+	return s
+}
@@ -0,0 +1,251 @@
+// Package compress gzips responses and records on the server span how much
+// was saved and how long compressing took, so the CPU cost of compression
+// can be weighed against response latency.
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Compressor gzips compressible responses of at least minBytes
+type Compressor struct {
+	level    int
+	minBytes int
+	pool     sync.Pool
+}
+
+// New creates a Compressor. level is a compress/gzip level; responses
+// smaller than minBytes are sent as is, since gzip's overhead outweighs the
+// savings on tiny bodies.
+func New(level, minBytes int) (*Compressor, error) {
+	// Fail at startup rather than on the first request
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return nil, err
+	}
+	cp := &Compressor{level: level, minBytes: minBytes}
+	cp.pool.New = func() any {
+		gz, _ := gzip.NewWriterLevel(io.Discard, level)
+		return gz
+	}
+	return cp, nil
+}
+
+// Middleware compresses responses for clients that accept gzip. Register it
+// after the SDK middleware and before anything that inspects the body.
+func (cp *Compressor) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+		// The server span, before handlers swap in their own child spans
+		span := trace.SpanFromContext(c.Request.Context())
+
+		w := &writer{ResponseWriter: c.Writer, cp: cp}
+		c.Writer = w
+		c.Next()
+		w.finish(span)
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, either
+// by name or through *, with a q-value above 0
+func acceptsGzip(header string) bool {
+	gzipQ, anyQ := -1.0, -1.0
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip", "x-gzip":
+			gzipQ = q
+		case "*":
+			anyQ = q
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return anyQ > 0
+}
+
+// compressible reports whether a response of contentType is worth gzipping.
+// Event streams are left alone so each event reaches the client as sent.
+func compressible(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/javascript",
+		mediaType == "application/xml",
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
+
+// writer buffers the start of the response until it knows whether to
+// compress it, then either gzips or passes everything through
+type writer struct {
+	gin.ResponseWriter
+	cp *Compressor
+
+	buf      []byte
+	decided  bool
+	gz       *gzip.Writer
+	original int
+	wire     int
+	spent    time.Duration
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	if !w.decided {
+		if !w.eligible() {
+			if err := w.decide(false); err != nil {
+				return 0, err
+			}
+		} else {
+			w.buf = append(w.buf, b...)
+			if len(w.buf) < w.cp.minBytes {
+				return len(b), nil
+			}
+			return len(b), w.decide(true)
+		}
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return len(b), w.compress(b)
+}
+
+func (w *writer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow is deferred while buffering, since compressing changes the
+// headers
+func (w *writer) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Flush commits to a decision so streamed responses aren't held back
+func (w *writer) Flush() {
+	if !w.decided {
+		w.decide(w.eligible())
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// eligible reports whether the response, as described by its status and
+// headers so far, may be compressed
+func (w *writer) eligible() bool {
+	status := w.Status()
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	h := w.Header()
+	return h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type"))
+}
+
+// decide fixes the headers and writes out whatever was buffered
+func (w *writer) decide(compress bool) error {
+	w.decided = true
+	buf := w.buf
+	w.buf = nil
+
+	if !compress {
+		if len(buf) == 0 {
+			return nil
+		}
+		_, err := w.ResponseWriter.Write(buf)
+		return err
+	}
+
+	h := w.Header()
+	h.Set("Content-Encoding", "gzip")
+	h.Add("Vary", "Accept-Encoding")
+	h.Del("Content-Length")
+
+	w.gz = w.cp.pool.Get().(*gzip.Writer)
+	w.gz.Reset(countingWriter{w.ResponseWriter, &w.wire})
+	return w.compress(buf)
+}
+
+// compress gzips b, timing the work
+func (w *writer) compress(b []byte) error {
+	start := time.Now()
+	_, err := w.gz.Write(b)
+	w.spent += time.Since(start)
+	w.original += len(b)
+	return err
+}
+
+// finish sends anything still buffered, closes the gzip stream, and records
+// the outcome on span
+func (w *writer) finish(span trace.Span) {
+	if !w.decided {
+		// Smaller than minBytes; not worth compressing
+		w.decide(false)
+	}
+	if w.gz == nil {
+		span.SetAttributes(attribute.Bool("http.response.compressed", false))
+		return
+	}
+
+	start := time.Now()
+	w.gz.Close()
+	w.spent += time.Since(start)
+	w.gz.Reset(io.Discard)
+	w.cp.pool.Put(w.gz)
+	w.gz = nil
+
+	ratio := 0.0
+	if w.wire > 0 {
+		ratio = float64(w.original) / float64(w.wire)
+	}
+	span.SetAttributes(
+		attribute.Bool("http.response.compressed", true),
+		attribute.String("http.response.content_encoding", "gzip"),
+		attribute.Int("http.response.uncompressed_bytes", w.original),
+		attribute.Int("http.response.compressed_bytes", w.wire),
+		attribute.Float64("http.response.compression_ratio", ratio),
+		attribute.Float64("http.response.compression_ms", float64(w.spent.Microseconds())/1000),
+	)
+}
+
+// countingWriter counts the compressed bytes sent to the client
+type countingWriter struct {
+	w io.Writer
+	n *int
+}
+
+func (cw countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	*cw.n += n
+	return n, err
+}
@@ -0,0 +1,643 @@
+// Package config loads the test app configuration from the environment and
+// command-line flags. Flags win over environment variables.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds everything the app needs to start
+type Config struct {
+	APIKey      string
+	ServiceName string
+	Environment string
+	Endpoint    string
+	UseSSL      bool
+
+	// OTLPFallbackEndpoint is a local OTLP/gRPC collector (host:port) that
+	// gets the spans when TraceKit is unreachable at startup
+	OTLPFallbackEndpoint string
+
+	// DebugExport tees every finished span as JSON lines to "stdout" or a
+	// file path
+	DebugExport string
+
+	// SampleRate is the fraction of traces recorded, from 0 to 1, and
+	// SampleSource names the setting it came from
+	SampleRate   float64
+	SampleSource string
+
+	// SampleParentBased follows the caller's sampling decision; when off,
+	// every service decides from the trace ID on its own
+	SampleParentBased bool
+
+	// Propagators are the trace context formats read from and written to
+	// requests, by their OTEL_PROPAGATORS names
+	Propagators []string
+
+	// SampleRoutes override SampleRate by gin route template or, with a
+	// trailing "*", by route prefix
+	SampleRoutes map[string]float64
+
+	// TraceSkipPaths are request paths that never generate spans
+	TraceSkipPaths []string
+
+	// Port is where the traced HTTP API listens
+	Port string
+
+	// H2C lets the HTTP listener accept HTTP/2 without TLS next to HTTP/1.1,
+	// and SelfProtocol is how the app calls its own API: "http" or "h2c"
+	H2C          bool
+	SelfProtocol string
+
+	// GRPCPort is where the example OrderService listens
+	GRPCPort string
+
+	// TLSCertFile and TLSKeyFile enable HTTPS on TLSPort, next to the plain
+	// HTTP listener. The files are checked every TLSReloadInterval and a
+	// rotated certificate is picked up without a restart.
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSPort           string
+	TLSReloadInterval time.Duration
+
+	// DownstreamCertFile and DownstreamKeyFile are the client certificate
+	// presented to HTTPS services that require mTLS, reloaded like the server
+	// certificate. DownstreamCAFile verifies those services instead of the
+	// system roots.
+	DownstreamCertFile string
+	DownstreamKeyFile  string
+	DownstreamCAFile   string
+
+	// HTTPClient* size the keep-alive pools of the downstream HTTP clients
+	// and bound connection setup. A service's max_idle_conns in the topology
+	// wins over HTTPClientMaxIdleConns; HTTPClientMaxIdleConnsPerHost is 0
+	// to match the pool size.
+	HTTPClientMaxIdleConns        int
+	HTTPClientMaxIdleConnsPerHost int
+	HTTPClientIdleConnTimeout     time.Duration
+	HTTPClientDialTimeout         time.Duration
+	HTTPClientTLSHandshakeTimeout time.Duration
+
+	// DNSResolver makes downstream calls resolve hostnames themselves, with
+	// a span per lookup, keeping answers for DNSCacheTTL. DNSServer is asked
+	// instead of the system's resolver if set.
+	DNSResolver bool
+	DNSCacheTTL time.Duration
+	DNSServer   string
+
+	// AdminPort serves pprof and debug endpoints, off the traced router
+	AdminPort string
+
+	// AdminToken guards /admin/config on the admin port; empty turns it off
+	AdminToken string
+
+	// DebugTraceBuffer is how many finished spans /debug/traces keeps on
+	// the admin port; 0 turns it off
+	DebugTraceBuffer int
+
+	// StartupWait is how long to keep probing the downstream services and
+	// the collector at boot before /readyz can pass; 0 skips the wait
+	StartupWait time.Duration
+
+	// ShutdownDelay is how long /readyz reports draining before the servers
+	// stop, giving load balancers time to take the instance out of rotation
+	ShutdownDelay time.Duration
+
+	// LogLevel is the minimum level written by the structured logger
+	LogLevel string
+
+	// Standalone replaces the downstream test services with in-process mocks
+	Standalone bool
+
+	// ServicesFile is the service topology describing the downstream test
+	// services; empty uses the services.yaml built into the binary
+	ServicesFile string
+
+	// FlagsFile defines the feature flags; empty uses the flags.yaml built
+	// into the binary
+	FlagsFile string
+
+	// FlagOverrides force a feature flag to a variant for everyone
+	FlagOverrides map[string]string
+
+	// ExternalAPIURL is the third-party API /api/call-external calls. It
+	// isn't one of the test services, so it isn't mocked in standalone mode.
+	ExternalAPIURL string
+
+	// ProxyTargetURL is the upstream /proxy/*path forwards to; empty
+	// forwards to this app
+	ProxyTargetURL string
+
+	// DatabaseURL is an optional Postgres DSN for /api/users-db
+	DatabaseURL string
+
+	// SQLitePath is the SQLite file backing POST /api/order; empty disables it
+	SQLitePath string
+
+	// SlowQueryThreshold marks DB spans at least this long with db.slow=true
+	SlowQueryThreshold time.Duration
+
+	// MongoURI is an optional MongoDB connection string for /api/documents
+	MongoURI      string
+	MongoDatabase string
+
+	// GeneratedUsers are added to the three demo users so /api/users has
+	// pages to walk through
+	GeneratedUsers int
+
+	// RedisAddr enables the cache-aside layer in front of /api/users
+	RedisAddr string
+	CacheTTL  time.Duration
+
+	// InventoryCacheTTL is how long stock levels stay cached; a store lookup
+	// finds a SKU sold out with probability OutOfStockRate
+	InventoryCacheTTL time.Duration
+	OutOfStockRate    float64
+
+	// SessionTTL is how long an idle /api/cart session keeps its cart
+	SessionTTL time.Duration
+
+	// KafkaBrokers enables /api/publish-order and the order consumer
+	KafkaBrokers []string
+	KafkaTopic   string
+	KafkaGroupID string
+
+	// NATSURL enables /api/check-stock and the NATS stock responder
+	NATSURL     string
+	NATSSubject string
+
+	// RabbitMQURL enables /api/enqueue and the task consumer
+	RabbitMQURL   string
+	RabbitMQQueue string
+
+	// SQSQueueURL enables /api/enqueue-sqs and the SQS poller; SQSEndpoint
+	// points the client at LocalStack or another SQS-compatible service
+	SQSQueueURL string
+	SQSEndpoint string
+
+	// WebhookSecret is the HMAC key webhooks are signed with, inbound and
+	// outbound; empty disables /api/webhooks
+	WebhookSecret string
+
+	// WebhookTargetURL enables outbound webhooks, delivered with up to
+	// WebhookMaxAttempts attempts before they are dead-lettered
+	WebhookTargetURL   string
+	WebhookMaxAttempts int
+
+	// RuntimeSampleInterval is how often runtime stats are sampled; requests
+	// taking SlowRequestThreshold or longer get the latest sample on their span
+	RuntimeSampleInterval time.Duration
+	SlowRequestThreshold  time.Duration
+
+	// WorkerPoolSize and WorkerQueueSize size the background job pool
+	WorkerPoolSize  int
+	WorkerQueueSize int
+
+	// RateLimitRPS and RateLimitBurst size each client's token bucket;
+	// an RPS of 0 turns rate limiting off
+	RateLimitRPS   float64
+	RateLimitBurst int
+	// TrustedProxies are the proxy IPs or CIDRs whose X-Forwarded-For is
+	// believed; with none the client IP is the peer's address
+	TrustedProxies []string
+
+	// CaptureBodies records request and response bodies on server spans,
+	// up to CaptureMaxBytes each, with CaptureRedactFields redacted
+	CaptureBodies       bool
+	CaptureMaxBytes     int
+	CaptureRedactFields []string
+
+	// RouteBudgets are per-route deadlines, keyed by gin route template;
+	// what's left of a budget is forwarded to downstream calls
+	RouteBudgets map[string]time.Duration
+
+	// ChaosHeader lets callers inject faults into single requests with the
+	// X-Chaos header; turn it off anywhere callers aren't trusted
+	ChaosHeader bool
+
+	// CompressResponses gzips compressible responses of at least GzipMinBytes
+	// for clients that accept it, at GzipLevel (1-9)
+	CompressResponses bool
+	GzipLevel         int
+	GzipMinBytes      int
+
+	// ResponseCacheRoutes are the GET routes whose 200 responses are
+	// cached, keyed by gin route template, with their TTLs; an expired
+	// response stands in for a failed refresh for ResponseCacheStale longer
+	ResponseCacheRoutes map[string]time.Duration
+	ResponseCacheStale  time.Duration
+
+	// BatchMaxSize and BatchWindow bound how /api/batch-process groups items
+	BatchMaxSize int
+	BatchWindow  time.Duration
+
+	// SchedulerEnabled turns on the periodic cleanup and report jobs
+	SchedulerEnabled bool
+	CleanupSchedule  string
+	ReportSchedule   string
+	RuntimeSchedule  string
+}
+
+// Load reads the optional .env file, the environment, and then the flags in
+// args (usually os.Args[1:]). It returns flag.ErrHelp for -h, and an error
+// when TRACEKIT_API_KEY is missing.
+func Load(args []string) (*Config, error) {
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		processEnv[key] = true
+	}
+	// Load environment variables from .env file
+	if err := loadDotenv(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	return parse(args)
+}
+
+// parse builds the configuration from the environment and the flags in args
+func parse(args []string) (*Config, error) {
+	cfg := &Config{
+		APIKey:      getEnv("TRACEKIT_API_KEY", ""),
+		ServiceName: getEnv("SERVICE_NAME", "go-test-app"),
+		Environment: getEnv("ENVIRONMENT", "development"),
+		Endpoint:    getEnv("TRACEKIT_ENDPOINT", "localhost:8081"),
+		UseSSL:      getEnv("TRACEKIT_USE_SSL", "false") == "true",
+		Port:        getEnv("PORT", "8082"),
+
+		H2C:          getEnv("H2C_ENABLED", "true") == "true",
+		SelfProtocol: getEnv("SELF_PROTOCOL", "http"),
+
+		Standalone:  getEnv("STANDALONE", "false") == "true",
+		GRPCPort:    getEnv("GRPC_PORT", "9090"),
+		TLSCertFile: getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:  getEnv("TLS_KEY_FILE", ""),
+		TLSPort:     getEnv("TLS_PORT", "8443"),
+
+		DownstreamCertFile: getEnv("DOWNSTREAM_TLS_CERT_FILE", ""),
+		DownstreamKeyFile:  getEnv("DOWNSTREAM_TLS_KEY_FILE", ""),
+		DownstreamCAFile:   getEnv("DOWNSTREAM_TLS_CA_FILE", ""),
+
+		DNSResolver: getEnv("DNS_RESOLVER_ENABLED", "false") == "true",
+		DNSServer:   getEnv("DNS_SERVER", ""),
+
+		AdminPort:  getEnv("ADMIN_PORT", "8092"),
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+		LogLevel:   getEnv("LOG_LEVEL", "info"),
+
+		SampleParentBased: getEnv("TRACEKIT_SAMPLE_PARENT_BASED", "true") == "true",
+
+		DatabaseURL: getEnv("DATABASE_URL", ""),
+		SQLitePath:  getEnv("SQLITE_PATH", "orders.db"),
+		RedisAddr:   getEnv("REDIS_ADDR", ""),
+
+		MongoURI:      getEnv("MONGODB_URI", ""),
+		MongoDatabase: getEnv("MONGODB_DATABASE", "tracekit_example"),
+
+		KafkaBrokers: splitList(getEnv("KAFKA_BROKERS", "")),
+		KafkaTopic:   getEnv("KAFKA_TOPIC", "orders"),
+		KafkaGroupID: getEnv("KAFKA_GROUP_ID", "go-test-app"),
+
+		NATSURL:     getEnv("NATS_URL", ""),
+		NATSSubject: getEnv("NATS_SUBJECT", "inventory.check"),
+
+		RabbitMQURL:   getEnv("RABBITMQ_URL", ""),
+		RabbitMQQueue: getEnv("RABBITMQ_QUEUE", "tasks"),
+
+		SQSQueueURL: getEnv("SQS_QUEUE_URL", ""),
+		SQSEndpoint: getEnv("SQS_ENDPOINT", ""),
+
+		WebhookSecret: getEnv("WEBHOOK_SECRET", ""),
+
+		CaptureBodies:       getEnv("CAPTURE_BODIES", "false") == "true",
+		CaptureRedactFields: splitList(getEnv("CAPTURE_REDACT_FIELDS", "")),
+
+		ChaosHeader: getEnv("CHAOS_HEADER_ENABLED", "true") == "true",
+
+		CompressResponses: getEnv("COMPRESS_RESPONSES", "true") == "true",
+
+		SchedulerEnabled: getEnv("SCHEDULER_ENABLED", "true") == "true",
+		CleanupSchedule:  getEnv("CLEANUP_SCHEDULE", "@every 1m"),
+		ReportSchedule:   getEnv("REPORT_SCHEDULE", "@every 5m"),
+		RuntimeSchedule:  getEnv("RUNTIME_REPORT_SCHEDULE", "@every 30s"),
+	}
+
+	// TRACEKIT_SAMPLE_RATE is the older name of TRACEKIT_SAMPLE_RATIO
+	var err error
+	cfg.SampleRate, cfg.SampleSource = 1.0, "default"
+	for _, key := range []string{"TRACEKIT_SAMPLE_RATE", "TRACEKIT_SAMPLE_RATIO"} {
+		if os.Getenv(key) == "" {
+			continue
+		}
+		if cfg.SampleRate, err = getEnvFloat(key, 1.0); err != nil {
+			return nil, err
+		}
+		cfg.SampleSource = key
+	}
+
+	// Flags override the environment so several instances can run side by side
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	fs.StringVar(&cfg.Port, "port", cfg.Port, "HTTP port (env PORT)")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "minimum log level: debug, info, warn, error (env LOG_LEVEL)")
+	fs.Float64Var(&cfg.SampleRate, "sample-rate", cfg.SampleRate, "fraction of traces to record, 0 to 1 (env TRACEKIT_SAMPLE_RATIO)")
+	fs.StringVar(&cfg.Endpoint, "endpoint", cfg.Endpoint, "TraceKit server endpoint (env TRACEKIT_ENDPOINT)")
+	fs.BoolVar(&cfg.Standalone, "standalone", cfg.Standalone, "serve mock Node/Python/Laravel/PHP services in-process (env STANDALONE)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "sample-rate" {
+			cfg.SampleSource = "--sample-rate"
+		}
+	})
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, errors.New("invalid sample rate: must be between 0 and 1")
+	}
+	if cfg.SampleRoutes, err = getEnvFloatMap("TRACEKIT_SAMPLE_ROUTES", ""); err != nil {
+		return nil, err
+	}
+	cfg.Propagators = splitList(getEnv("OTEL_PROPAGATORS", "tracecontext,baggage"))
+	cfg.TraceSkipPaths = splitList(getEnv("TRACEKIT_SKIP_PATHS", "/health,/livez,/readyz"))
+
+	cfg.OTLPFallbackEndpoint = getEnv("OTLP_FALLBACK_ENDPOINT", "")
+	cfg.DebugExport = getEnv("TRACEKIT_DEBUG_EXPORT", "")
+	if cfg.APIKey == "" {
+		return nil, errors.New("TRACEKIT_API_KEY environment variable is required. Copy .env.example to .env and add your API key.")
+	}
+
+	cfg.ServicesFile = getEnv("SERVICES_FILE", "")
+	cfg.FlagsFile = getEnv("FEATURE_FLAGS_FILE", "")
+	if cfg.FlagOverrides, err = getEnvMap("FEATURE_FLAGS", ""); err != nil {
+		return nil, err
+	}
+	if cfg.ExternalAPIURL, err = getEnvURL("EXTERNAL_API_URL", "https://httpbin.org/get"); err != nil {
+		return nil, err
+	}
+	if cfg.ProxyTargetURL, err = getEnvURL("PROXY_TARGET_URL", ""); err != nil {
+		return nil, err
+	}
+	if cfg.SelfProtocol != "http" && cfg.SelfProtocol != "h2c" {
+		return nil, errors.New("invalid SELF_PROTOCOL: must be http or h2c")
+	}
+	if cfg.SelfProtocol == "h2c" && !cfg.H2C {
+		return nil, errors.New("SELF_PROTOCOL=h2c needs H2C_ENABLED=true")
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if (cfg.DownstreamCertFile == "") != (cfg.DownstreamKeyFile == "") {
+		return nil, errors.New("DOWNSTREAM_TLS_CERT_FILE and DOWNSTREAM_TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSReloadInterval, err = getEnvDuration("TLS_RELOAD_INTERVAL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPClientMaxIdleConns, err = getEnvInt("HTTP_CLIENT_MAX_IDLE_CONNS", 10); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPClientMaxIdleConnsPerHost, err = getEnvNonNegativeInt("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", 0); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPClientIdleConnTimeout, err = getEnvDuration("HTTP_CLIENT_IDLE_CONN_TIMEOUT", 90*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPClientDialTimeout, err = getEnvDuration("HTTP_CLIENT_DIAL_TIMEOUT", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPClientTLSHandshakeTimeout, err = getEnvDuration("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.HTTPClientIdleConnTimeout <= 0 || cfg.HTTPClientDialTimeout <= 0 || cfg.HTTPClientTLSHandshakeTimeout <= 0 {
+		return nil, errors.New("HTTP_CLIENT_IDLE_CONN_TIMEOUT, HTTP_CLIENT_DIAL_TIMEOUT, and HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT must be positive")
+	}
+	if cfg.DNSCacheTTL, err = getEnvDuration("DNS_CACHE_TTL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.DNSServer != "" {
+		if _, _, err := net.SplitHostPort(cfg.DNSServer); err != nil {
+			return nil, errors.New("invalid DNS_SERVER: must be host:port")
+		}
+	}
+	if cfg.StartupWait, err = getEnvDuration("STARTUP_WAIT", 0); err != nil {
+		return nil, err
+	}
+	if cfg.ShutdownDelay, err = getEnvDuration("SHUTDOWN_DELAY", 0); err != nil {
+		return nil, err
+	}
+	if cfg.SlowQueryThreshold, err = getEnvDuration("SLOW_QUERY_THRESHOLD", 500*time.Millisecond); err != nil {
+		return nil, err
+	}
+	if cfg.GeneratedUsers, err = getEnvInt("GENERATED_USERS", 500); err != nil {
+		return nil, err
+	}
+	if cfg.CacheTTL, err = getEnvDuration("CACHE_TTL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.InventoryCacheTTL, err = getEnvDuration("INVENTORY_CACHE_TTL", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.OutOfStockRate, err = getEnvFloat("OUT_OF_STOCK_RATE", 0.05); err != nil {
+		return nil, err
+	}
+	if cfg.OutOfStockRate < 0 || cfg.OutOfStockRate > 1 {
+		return nil, errors.New("invalid OUT_OF_STOCK_RATE: must be between 0 and 1")
+	}
+	if cfg.WebhookTargetURL, err = getEnvURL("WEBHOOK_TARGET_URL", ""); err != nil {
+		return nil, err
+	}
+	if cfg.DebugTraceBuffer, err = getEnvNonNegativeInt("DEBUG_TRACE_BUFFER", 1000); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookMaxAttempts, err = getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5); err != nil {
+		return nil, err
+	}
+	if cfg.RuntimeSampleInterval, err = getEnvDuration("RUNTIME_SAMPLE_INTERVAL", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.SlowRequestThreshold, err = getEnvDuration("SLOW_REQUEST_THRESHOLD", time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.SessionTTL, err = getEnvDuration("SESSION_TTL", 30*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.WorkerPoolSize, err = getEnvInt("WORKER_POOL_SIZE", 4); err != nil {
+		return nil, err
+	}
+	if cfg.WorkerQueueSize, err = getEnvInt("WORKER_QUEUE_SIZE", 100); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimitRPS, err = getEnvFloat("RATE_LIMIT_RPS", 20); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimitBurst, err = getEnvInt("RATE_LIMIT_BURST", 40); err != nil {
+		return nil, err
+	}
+	cfg.TrustedProxies = splitList(getEnv("TRUSTED_PROXIES", ""))
+	if cfg.CaptureMaxBytes, err = getEnvInt("CAPTURE_MAX_BYTES", 4096); err != nil {
+		return nil, err
+	}
+	if cfg.RouteBudgets, err = getEnvDurationMap("ROUTE_BUDGETS", "/api/call-node=3s,/api/call-all=3s,/api/chain=5s"); err != nil {
+		return nil, err
+	}
+	if cfg.GzipLevel, err = getEnvInt("GZIP_LEVEL", 6); err != nil {
+		return nil, err
+	}
+	if cfg.GzipMinBytes, err = getEnvInt("GZIP_MIN_BYTES", 1024); err != nil {
+		return nil, err
+	}
+	if cfg.ResponseCacheRoutes, err = getEnvDurationMap("RESPONSE_CACHE_ROUTES", ""); err != nil {
+		return nil, err
+	}
+	if cfg.ResponseCacheStale, err = getEnvDuration("RESPONSE_CACHE_STALE", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.ResponseCacheStale < 0 {
+		return nil, errors.New("invalid RESPONSE_CACHE_STALE: must not be negative")
+	}
+	if cfg.BatchMaxSize, err = getEnvInt("BATCH_MAX_SIZE", 10); err != nil {
+		return nil, err
+	}
+	if cfg.BatchWindow, err = getEnvDuration("BATCH_WINDOW", 2*time.Second); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// getEnv retrieves an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt retrieves a positive integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s: must be a positive integer", key)
+	}
+	return n, nil
+}
+
+// getEnvNonNegativeInt retrieves an integer environment variable that may be
+// 0, usually to turn something off, or returns a default value
+func getEnvNonNegativeInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: must be a non-negative integer", key)
+	}
+	return n, nil
+}
+
+// getEnvFloat retrieves a floating-point environment variable or returns a default value
+func getEnvFloat(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: must be a number", key)
+	}
+	return f, nil
+}
+
+// getEnvDuration retrieves a duration environment variable such as "30s" or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+// getEnvDurationMap retrieves a comma-separated list of key=duration pairs,
+// e.g. "/api/chain=5s,/api/call-all=3s", or parses defaultValue
+func getEnvDurationMap(key, defaultValue string) (map[string]time.Duration, error) {
+	m := make(map[string]time.Duration)
+	for _, pair := range splitList(getEnv(key, defaultValue)) {
+		k, v, ok := strings.Cut(pair, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if !ok || err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid %s: %q must be key=duration", key, pair)
+		}
+		m[strings.TrimSpace(k)] = d
+	}
+	return m, nil
+}
+
+// getEnvMap parses a comma-separated list of key=value pairs, e.g.
+// "new-order-validation=on,order-confirmation-email=off"
+func getEnvMap(key, defaultValue string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, pair := range splitList(getEnv(key, defaultValue)) {
+		k, v, ok := strings.Cut(pair, "=")
+		if k, v = strings.TrimSpace(k), strings.TrimSpace(v); !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid %s: %q must be key=value", key, pair)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+// getEnvURL retrieves an absolute http(s) URL environment variable or returns a
+// default value. A trailing slash is dropped so paths can be appended.
+func getEnvURL(key, defaultValue string) (string, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid %s: must be an absolute http(s) URL", key)
+	}
+	return strings.TrimSuffix(value, "/"), nil
+}
+
+// getEnvFloatMap parses a comma-separated list of key=ratio pairs, each
+// ratio between 0 and 1
+func getEnvFloatMap(key, defaultValue string) (map[string]float64, error) {
+	m := make(map[string]float64)
+	for _, pair := range splitList(getEnv(key, defaultValue)) {
+		k, v, ok := strings.Cut(pair, "=")
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if !ok || err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("invalid %s: %q must be key=ratio with a ratio between 0 and 1", key, pair)
+		}
+		m[strings.TrimSpace(k)] = f
+	}
+	return m, nil
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
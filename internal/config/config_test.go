@@ -0,0 +1,37 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutOfStockRate(t *testing.T) {
+	t.Setenv("TRACEKIT_API_KEY", "test")
+	for _, tc := range []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"", 0.05, true},
+		{"0", 0, true},
+		{"0.3", 0.3, true},
+		{"1", 1, true},
+		{"-0.1", 0, false},
+		{"1.5", 0, false},
+		{"often", 0, false},
+	} {
+		t.Setenv("OUT_OF_STOCK_RATE", tc.value)
+		cfg, err := parse(nil)
+		if !tc.ok {
+			if err == nil || !strings.Contains(err.Error(), "OUT_OF_STOCK_RATE") {
+				t.Errorf("OUT_OF_STOCK_RATE=%q: got error %v, want one naming OUT_OF_STOCK_RATE", tc.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("OUT_OF_STOCK_RATE=%q: %v", tc.value, err)
+		} else if cfg.OutOfStockRate != tc.want {
+			t.Errorf("OUT_OF_STOCK_RATE=%q: got %v, want %v", tc.value, cfg.OutOfStockRate, tc.want)
+		}
+	}
+}
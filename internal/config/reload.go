@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+var (
+	// processEnv are the variables set before .env was first read. .env
+	// never overrides them, at startup or on reload.
+	processEnv = make(map[string]bool)
+
+	// dotenvMu guards dotenvKeys, the variables last set from .env
+	dotenvMu   sync.Mutex
+	dotenvKeys = make(map[string]bool)
+)
+
+// Reload reads .env again and rebuilds the configuration from the
+// environment and args, for applying changes without a restart. Variables
+// removed from .env since the last read are unset again.
+func Reload(args []string) (*Config, error) {
+	if err := loadDotenv(); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return parse(args)
+}
+
+// loadDotenv sets every variable in .env that the process environment
+// doesn't already set
+func loadDotenv() error {
+	values, err := godotenv.Read()
+	if err != nil {
+		values = nil
+	}
+
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+	for key := range dotenvKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+			delete(dotenvKeys, key)
+		}
+	}
+	for key, value := range values {
+		if processEnv[key] {
+			continue
+		}
+		os.Setenv(key, value)
+		dotenvKeys[key] = true
+	}
+	return err
+}
+
+// Changed lists the fields that differ between two configurations
+func Changed(old, updated *Config) []string {
+	var fields []string
+	a, b := reflect.ValueOf(old).Elem(), reflect.ValueOf(updated).Elem()
+	for i := range a.NumField() {
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			fields = append(fields, a.Type().Field(i).Name)
+		}
+	}
+	return fields
+}
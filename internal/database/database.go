@@ -0,0 +1,158 @@
+// Package database wraps database/sql so every query produces a DB span
+// with the statement, rows returned, and duration.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	// Register the Postgres driver with database/sql
+	_ "github.com/lib/pq"
+)
+
+// DB is a traced *sql.DB
+type DB struct {
+	sql    *sql.DB
+	sdk    *tracekit.SDK
+	system string
+
+	// slowThreshold marks statements that take at least this long as slow
+	slowThreshold time.Duration
+}
+
+// Open connects to Postgres at dsn and verifies the connection. Statements
+// taking slowThreshold or longer get db.slow=true on their span.
+func Open(ctx context.Context, sdk *tracekit.SDK, dsn string, slowThreshold time.Duration) (*DB, error) {
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetMaxOpenConns(10)
+	conn.SetMaxIdleConns(5)
+	conn.SetConnMaxLifetime(30 * time.Minute)
+
+	return connect(ctx, conn, &DB{sql: conn, sdk: sdk, system: "postgresql", slowThreshold: slowThreshold})
+}
+
+// connect pings db and closes conn if the database is unreachable
+func connect(ctx context.Context, conn *sql.DB, db *DB) (*DB, error) {
+	if err := db.Ping(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Ping verifies the connection inside a span
+func (db *DB) Ping(ctx context.Context) error {
+	ctx, span := db.startSpan(ctx, "db.ping", "")
+	defer span.End()
+
+	if err := db.sql.PingContext(ctx); err != nil {
+		db.sdk.RecordError(span, err)
+		return err
+	}
+	db.sdk.SetSuccess(span)
+	return nil
+}
+
+// Query runs a query and calls scan for every row.
+// It returns the number of rows scanned.
+func (db *DB) Query(ctx context.Context, query string, args []any, scan func(*sql.Rows) error) (int, error) {
+	start := time.Now()
+	ctx, span := db.startSpan(ctx, spanName(query), query)
+	defer span.End()
+
+	rows, err := db.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		db.finish(span, start, err)
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			db.finish(span, start, err)
+			return count, err
+		}
+		count++
+	}
+
+	db.sdk.AddIntAttribute(span, "db.rows_returned", int64(count))
+	err = rows.Err()
+	db.finish(span, start, err)
+	return count, err
+}
+
+// Exec runs a statement that returns no rows
+func (db *DB) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	ctx, span := db.startSpan(ctx, spanName(query), query)
+	defer span.End()
+
+	result, err := db.sql.ExecContext(ctx, query, args...)
+	if err == nil {
+		if affected, rerr := result.RowsAffected(); rerr == nil {
+			db.sdk.AddIntAttribute(span, "db.rows_affected", affected)
+		}
+	}
+	db.finish(span, start, err)
+	return result, err
+}
+
+// Close closes the connection pool
+func (db *DB) Close() error {
+	return db.sql.Close()
+}
+
+// startSpan starts a DB span with the standard attributes
+func (db *DB) startSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	ctx, span := db.sdk.StartSpan(ctx, name)
+	db.sdk.AddAttribute(span, "db.system", db.system)
+	if query != "" {
+		db.sdk.AddAttribute(span, "db.statement", query)
+		db.sdk.AddAttribute(span, "db.operation", operation(query))
+	}
+	return ctx, span
+}
+
+// finish records the duration and outcome of a DB span, flagging slow
+// statements so they can be found and alerted on
+func (db *DB) finish(span trace.Span, start time.Time, err error) {
+	duration := time.Since(start)
+	db.sdk.AddFloatAttribute(span, "db.duration_ms", float64(duration.Microseconds())/1000)
+
+	slow := db.slowThreshold > 0 && duration >= db.slowThreshold
+	span.SetAttributes(attribute.Bool("db.slow", slow))
+	if slow {
+		db.sdk.AddIntAttribute(span, "db.slow_threshold_ms", db.slowThreshold.Milliseconds())
+		db.sdk.AddEvent(span, "db.slow_query")
+	}
+	if err != nil {
+		db.sdk.RecordError(span, err)
+		return
+	}
+	db.sdk.SetSuccess(span)
+}
+
+// operation returns the SQL verb of query, e.g. SELECT
+func operation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// spanName names a DB span after its SQL verb, e.g. db.select
+func spanName(query string) string {
+	return "db." + strings.ToLower(operation(query))
+}
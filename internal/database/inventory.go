@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrSKUNotFound is returned by GetStock for unknown SKUs
+var ErrSKUNotFound = errors.New("sku not found")
+
+// MigrateInventory creates the inventory table and stocks it with seed,
+// leaving SKUs that already exist untouched
+func (db *DB) MigrateInventory(ctx context.Context, seed map[string]int) error {
+	if _, err := db.Exec(ctx, `CREATE TABLE IF NOT EXISTS inventory (
+		sku       TEXT PRIMARY KEY,
+		available INTEGER NOT NULL
+	)`); err != nil {
+		return err
+	}
+	for sku, available := range seed {
+		if _, err := db.Exec(ctx, "INSERT INTO inventory (sku, available) VALUES ($1, $2) ON CONFLICT (sku) DO NOTHING", sku, available); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStock returns how many units of sku are available, or ErrSKUNotFound
+func (db *DB) GetStock(ctx context.Context, sku string) (int, error) {
+	var available int
+	n, err := db.Query(ctx, "SELECT available FROM inventory WHERE sku = $1", []any{sku}, func(rows *sql.Rows) error {
+		return rows.Scan(&available)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, ErrSKUNotFound
+	}
+	return available, nil
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrOrderNotFound is returned by GetOrder for unknown ids
+var ErrOrderNotFound = errors.New("order not found")
+
+// Order is a row of the orders table
+type Order struct {
+	ID         string    `json:"order_id"`
+	CustomerID string    `json:"customer_id"`
+	Amount     float64   `json:"amount"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// MigrateOrders creates the orders table
+func (db *DB) MigrateOrders(ctx context.Context) error {
+	_, err := db.Exec(ctx, `CREATE TABLE IF NOT EXISTS orders (
+		id          TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		amount      REAL NOT NULL,
+		status      TEXT NOT NULL,
+		created_at  TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// InsertOrder stores a new order
+func (db *DB) InsertOrder(ctx context.Context, o Order) error {
+	_, err := db.Exec(ctx, "INSERT INTO orders (id, customer_id, amount, status, created_at) VALUES ($1, $2, $3, $4, $5)",
+		o.ID, o.CustomerID, o.Amount, o.Status, o.CreatedAt.UTC())
+	return err
+}
+
+// GetOrder returns the order with the given id, or ErrOrderNotFound
+func (db *DB) GetOrder(ctx context.Context, id string) (Order, error) {
+	var o Order
+	n, err := db.Query(ctx, "SELECT id, customer_id, amount, status, created_at FROM orders WHERE id = $1", []any{id}, func(rows *sql.Rows) error {
+		return rows.Scan(&o.ID, &o.CustomerID, &o.Amount, &o.Status, &o.CreatedAt)
+	})
+	if err != nil {
+		return Order{}, err
+	}
+	if n == 0 {
+		return Order{}, ErrOrderNotFound
+	}
+	return o, nil
+}
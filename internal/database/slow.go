@@ -0,0 +1,21 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Sleep runs a query that takes d on the server, for reproducing slow
+// queries on demand
+func (db *DB) Sleep(ctx context.Context, d time.Duration) error {
+	_, err := db.Query(ctx, "SELECT pg_sleep($1)", []any{d.Seconds()}, func(*sql.Rows) error {
+		return nil
+	})
+	return err
+}
+
+// SlowThreshold is the duration from which statements are flagged as slow
+func (db *DB) SlowThreshold() time.Duration {
+	return db.slowThreshold
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+
+	// Register the SQLite driver with database/sql (requires cgo)
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenSQLite opens the SQLite database file at path, creating it if needed,
+// so the example can persist data without an external database. Use
+// ":memory:" for a database that lives only as long as the process.
+func OpenSQLite(ctx context.Context, sdk *tracekit.SDK, path string, slowThreshold time.Duration) (*DB, error) {
+	conn, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows one writer at a time, and every connection to
+	// :memory: would otherwise get its own empty database
+	conn.SetMaxOpenConns(1)
+
+	return connect(ctx, conn, &DB{sql: conn, sdk: sdk, system: "sqlite", slowThreshold: slowThreshold})
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// User is a row of the users table
+type User struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Migrate creates the users table and seeds the demo users
+func (db *DB) Migrate(ctx context.Context) error {
+	if _, err := db.Exec(ctx, `CREATE TABLE IF NOT EXISTS users (
+		id    SERIAL PRIMARY KEY,
+		name  TEXT NOT NULL,
+		email TEXT NOT NULL UNIQUE
+	)`); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(ctx, `INSERT INTO users (name, email) VALUES
+		('Alice', 'alice@example.com'),
+		('Bob', 'bob@example.com'),
+		('Charlie', 'charlie@example.com')
+		ON CONFLICT (email) DO NOTHING`)
+	return err
+}
+
+// ListUsers returns up to limit users ordered by id
+func (db *DB) ListUsers(ctx context.Context, limit int) ([]User, error) {
+	var users []User
+	_, err := db.Query(ctx, "SELECT id, name, email FROM users ORDER BY id LIMIT $1", []any{limit}, func(rows *sql.Rows) error {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+			return err
+		}
+		users = append(users, u)
+		return nil
+	})
+	return users, err
+}
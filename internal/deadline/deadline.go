@@ -0,0 +1,111 @@
+// Package deadline gives requests a time budget and carries what is left of
+// it to downstream services, so every hop knows how long the caller will
+// still wait.
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header carries the caller's remaining budget in milliseconds
+const Header = "X-Request-Budget-Ms"
+
+// Budgets assigns deadlines to routes
+type Budgets struct {
+	routes map[string]time.Duration
+}
+
+// New creates Budgets from route templates (as registered with gin, e.g.
+// /api/order/:id) to budgets. Routes without a budget only get a deadline
+// when the caller sends one.
+func New(routes map[string]time.Duration) *Budgets {
+	return &Budgets{routes: routes}
+}
+
+// Middleware applies the route's budget, or the caller's remaining budget if
+// that is shorter, as the request context's deadline. Register it after the
+// SDK middleware; the server span records the budget and how much of it the
+// request used.
+func (b *Budgets) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		budget, source := b.routes[c.FullPath()], "route"
+		if inbound, ok := parseHeader(c.GetHeader(Header)); ok && (budget <= 0 || inbound < budget) {
+			budget, source = inbound, "caller"
+		}
+		if budget <= 0 {
+			c.Next()
+			return
+		}
+
+		span := trace.SpanFromContext(c.Request.Context())
+		span.SetAttributes(
+			attribute.Int64("deadline.allotted_ms", budget.Milliseconds()),
+			attribute.String("deadline.source", source),
+		)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), budget)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		start := time.Now()
+		c.Next()
+		consumed := time.Since(start)
+
+		exceeded := consumed >= budget
+		span.SetAttributes(
+			attribute.Int64("deadline.consumed_ms", consumed.Milliseconds()),
+			attribute.Int64("deadline.remaining_ms", max(budget-consumed, 0).Milliseconds()),
+			attribute.Bool("deadline.exceeded", exceeded),
+		)
+		if exceeded {
+			span.AddEvent("deadline.exceeded")
+		}
+	}
+}
+
+// parseHeader reads a positive budget from the Header value
+func parseHeader(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// Transport forwards the remaining budget of each request's context in
+// Header, and records it on the CLIENT span. Requests without a deadline
+// pass through untouched.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return roundTripper{next: next}
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	d, ok := req.Context().Deadline()
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	// At least 1ms, so the downstream still sees a (spent) budget
+	remaining := max(time.Until(d), time.Millisecond)
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, strconv.FormatInt(remaining.Milliseconds(), 10))
+
+	trace.SpanFromContext(req.Context()).SetAttributes(
+		attribute.Int64("deadline.forwarded_ms", remaining.Milliseconds()),
+	)
+	return rt.next.RoundTrip(req)
+}
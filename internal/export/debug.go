@@ -0,0 +1,51 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Debug returns a processor that writes every finished span as one JSON line
+// to stdout, when target is "stdout", or appends it to the file at target.
+// Spans are written synchronously as they end, so the output can be followed
+// live; it is meant for development, not production traffic.
+func Debug(target string) (sdktrace.SpanProcessor, error) {
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if target != "stdout" {
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		w, file = f, f
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return nil, err
+	}
+
+	processor := sdktrace.NewSimpleSpanProcessor(exporter)
+	if file == nil {
+		return processor, nil
+	}
+	return &closingProcessor{SpanProcessor: processor, file: file}, nil
+}
+
+// closingProcessor closes the debug file once its processor has shut down
+type closingProcessor struct {
+	sdktrace.SpanProcessor
+	file *os.File
+}
+
+func (p *closingProcessor) Shutdown(ctx context.Context) error {
+	return errors.Join(p.SpanProcessor.Shutdown(ctx), p.file.Close())
+}
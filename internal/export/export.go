@@ -0,0 +1,45 @@
+// Package export sends spans to destinations besides TraceKit: a local
+// OTLP collector when TraceKit can't be reached, a JSON lines file, or an
+// in-memory buffer for debugging. Each one is a span processor added to the
+// tracer provider the TraceKit SDK installs, so it sees every span the SDK
+// exports.
+package export
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ErrUnsupportedProvider is returned when the global tracer provider is not
+// the OpenTelemetry SDK's, so processors can't be added to it
+var ErrUnsupportedProvider = errors.New("export: global tracer provider does not accept span processors")
+
+// Register adds sp to the global tracer provider. Call it after the SDK is
+// initialized; the SDK's Shutdown flushes and stops sp with its own.
+func Register(sp sdktrace.SpanProcessor) error {
+	tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	if !ok {
+		return ErrUnsupportedProvider
+	}
+	tp.RegisterSpanProcessor(sp)
+	return nil
+}
+
+// OTLP returns a batching processor that exports over OTLP/gRPC to a
+// collector at endpoint (host:port) without TLS, as local collectors
+// usually run. The connection is made lazily, so a collector that starts
+// after the app still receives spans.
+func OTLP(ctx context.Context, endpoint string) (sdktrace.SpanProcessor, error) {
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewBatchSpanProcessor(exporter), nil
+}
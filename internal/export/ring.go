@@ -0,0 +1,173 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Span is the summary of a finished span kept by Ring
+type Span struct {
+	Name         string         `json:"name"`
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Kind         string         `json:"kind"`
+	Start        time.Time      `json:"start"`
+	DurationMS   float64        `json:"duration_ms"`
+	Status       string         `json:"status"`
+	StatusDesc   string         `json:"status_description,omitempty"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Events       []Event        `json:"events,omitempty"`
+
+	// Children is only filled in the tree view of one trace
+	Children []*Span `json:"children,omitempty"`
+}
+
+// Event is a span event
+type Event struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Ring is a span processor that keeps the last few finished spans in memory
+// and serves them as JSON, so span names, attributes, and parent/child
+// structure can be checked right after a request without a backend
+type Ring struct {
+	mu   sync.Mutex
+	buf  []Span
+	next int
+	full bool
+}
+
+// NewRing creates a Ring holding up to size spans
+func NewRing(size int) *Ring {
+	return &Ring{buf: make([]Span, size)}
+}
+
+// OnEnd records s, replacing the oldest span once the ring is full
+func (r *Ring) OnEnd(s sdktrace.ReadOnlySpan) {
+	span := Span{
+		Name:       s.Name(),
+		TraceID:    s.SpanContext().TraceID().String(),
+		SpanID:     s.SpanContext().SpanID().String(),
+		Kind:       s.SpanKind().String(),
+		Start:      s.StartTime(),
+		DurationMS: float64(s.EndTime().Sub(s.StartTime()).Microseconds()) / 1000,
+		Status:     s.Status().Code.String(),
+		StatusDesc: s.Status().Description,
+		Attributes: attrMap(s.Attributes()),
+	}
+	if parent := s.Parent(); parent.IsValid() {
+		span.ParentSpanID = parent.SpanID().String()
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, Event{Name: e.Name, Time: e.Time, Attributes: attrMap(e.Attributes)})
+	}
+
+	r.mu.Lock()
+	r.buf[r.next] = span
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// Spans returns the buffered spans, newest first
+func (r *Ring) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.buf)
+	}
+	out := make([]Span, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.buf[(r.next-i+len(r.buf))%len(r.buf)])
+	}
+	return out
+}
+
+// ServeHTTP lists the buffered spans, newest first. ?trace_id= narrows them
+// to one trace and adds a tree of its spans nested under their parents;
+// ?limit= caps the list.
+func (r *Ring) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	spans := r.Spans()
+
+	traceID := req.URL.Query().Get("trace_id")
+	if traceID != "" {
+		matched := spans[:0]
+		for _, s := range spans {
+			if s.TraceID == traceID {
+				matched = append(matched, s)
+			}
+		}
+		spans = matched
+	}
+	if limit, err := strconv.Atoi(req.URL.Query().Get("limit")); err == nil && limit >= 0 && limit < len(spans) {
+		spans = spans[:limit]
+	}
+
+	resp := map[string]any{"count": len(spans), "spans": spans}
+	if traceID != "" {
+		resp["tree"] = tree(spans)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(resp)
+}
+
+// tree nests the spans of one trace under their parents, in start order.
+// Spans whose parent isn't buffered, like the server span of a request that
+// came in with a traceparent, are roots.
+func tree(spans []Span) []*Span {
+	nodes := make([]*Span, len(spans))
+	byID := make(map[string]*Span, len(spans))
+	for i := range spans {
+		s := spans[i]
+		nodes[i] = &s
+		byID[s.SpanID] = &s
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Start.Before(nodes[j].Start) })
+
+	var roots []*Span
+	for _, node := range nodes {
+		if parent, ok := byID[node.ParentSpanID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+func attrMap(attrs []attribute.KeyValue) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+// OnStart does nothing; spans are recorded once they end
+func (r *Ring) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+// Shutdown does nothing; the buffer lives as long as the process
+func (r *Ring) Shutdown(context.Context) error { return nil }
+
+// ForceFlush does nothing; spans are buffered as they end
+func (r *Ring) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,192 @@
+// Package flags evaluates feature flags defined in a flag file, with
+// overrides from the environment. Every evaluation is a short
+// "feature_flag.evaluate" span recording the flag key, the variant served,
+// and why, so a rollout shows up in the traces of the requests it touched.
+package flags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/goccy/go-yaml"
+)
+
+// Reasons an evaluation served its variant, as named by OpenFeature
+const (
+	// ReasonStatic is a variant forced by an override
+	ReasonStatic = "STATIC"
+	// ReasonDefault is the flag's default variant
+	ReasonDefault = "DEFAULT"
+	// ReasonSplit is a variant picked by the percentage rollout
+	ReasonSplit = "SPLIT"
+	// ReasonError is the caller's fallback, served when the flag is unknown
+	ReasonError = "ERROR"
+)
+
+// Flag is one feature flag
+type Flag struct {
+	Key         string         `json:"key"`
+	Description string         `json:"description,omitempty"`
+	Variants    []string       `json:"variants"`
+	Default     string         `json:"default"`
+	Rollout     map[string]int `json:"rollout,omitempty"`
+}
+
+// flagFile is the flag file, flags.yaml. JSON is valid YAML, so the same
+// file can be written as JSON.
+type flagFile struct {
+	Flags []struct {
+		Key         string         `yaml:"key"`
+		Description string         `yaml:"description"`
+		Variants    []string       `yaml:"variants"`
+		Default     string         `yaml:"default"`
+		Rollout     map[string]int `yaml:"rollout"`
+	} `yaml:"flags"`
+}
+
+// Evaluation is the outcome of evaluating one flag
+type Evaluation struct {
+	Key     string `json:"key"`
+	Variant string `json:"variant"`
+	Reason  string `json:"reason"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Provider evaluates the flags of one flag file
+type Provider struct {
+	sdk       *tracekit.SDK
+	name      string
+	flags     map[string]Flag
+	overrides map[string]string
+}
+
+// Parse reads a flag file. Unknown keys are rejected to catch typos.
+func Parse(data []byte) ([]Flag, error) {
+	var f flagFile
+	if err := yaml.UnmarshalWithOptions(data, &f, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("parse flag file: %w", err)
+	}
+
+	var flags []Flag
+	for i, entry := range f.Flags {
+		flag := Flag(entry)
+		if flag.Key == "" {
+			return nil, fmt.Errorf("flag %d has no key", i+1)
+		}
+		if slices.ContainsFunc(flags, func(other Flag) bool { return other.Key == flag.Key }) {
+			return nil, fmt.Errorf("flag %q is defined twice", flag.Key)
+		}
+		if len(flag.Variants) == 0 {
+			flag.Variants = []string{"off", "on"}
+		}
+		if flag.Default == "" {
+			flag.Default = flag.Variants[0]
+		}
+		if !slices.Contains(flag.Variants, flag.Default) {
+			return nil, fmt.Errorf("flag %q: default %q is not one of its variants", flag.Key, flag.Default)
+		}
+		total := 0
+		for variant, percent := range flag.Rollout {
+			if !slices.Contains(flag.Variants, variant) {
+				return nil, fmt.Errorf("flag %q: rollout variant %q is not one of its variants", flag.Key, variant)
+			}
+			if percent < 0 {
+				return nil, fmt.Errorf("flag %q: rollout percentages can't be negative", flag.Key)
+			}
+			total += percent
+		}
+		if total > 100 {
+			return nil, fmt.Errorf("flag %q: rollout percentages add up to more than 100", flag.Key)
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// New creates a provider named name for flags. overrides force a flag to a
+// variant for everyone, e.g. from FEATURE_FLAGS.
+func New(sdk *tracekit.SDK, name string, flags []Flag, overrides map[string]string) (*Provider, error) {
+	p := &Provider{sdk: sdk, name: name, flags: make(map[string]Flag), overrides: overrides}
+	for _, flag := range flags {
+		p.flags[flag.Key] = flag
+	}
+	for key, variant := range overrides {
+		flag, ok := p.flags[key]
+		if !ok {
+			return nil, fmt.Errorf("override for unknown flag %q", key)
+		}
+		if !slices.Contains(flag.Variants, variant) {
+			return nil, fmt.Errorf("override for flag %q: %q is not one of its variants", key, variant)
+		}
+	}
+	return p, nil
+}
+
+// Flags returns every flag, sorted by key
+func (p *Provider) Flags() []Flag {
+	flags := make([]Flag, 0, len(p.flags))
+	for _, flag := range p.flags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags
+}
+
+// Variant evaluates a flag for targetingKey, e.g. a customer ID, which
+// keeps a rollout sticky: the same key always lands in the same variant.
+// An unknown flag serves fallback.
+func (p *Provider) Variant(ctx context.Context, key, targetingKey, fallback string) Evaluation {
+	_, span := p.sdk.StartSpan(ctx, "feature_flag.evaluate")
+	defer span.End()
+
+	eval := p.evaluate(key, targetingKey, fallback)
+	p.sdk.AddAttribute(span, "feature_flag.key", eval.Key)
+	p.sdk.AddAttribute(span, "feature_flag.variant", eval.Variant)
+	p.sdk.AddAttribute(span, "feature_flag.reason", eval.Reason)
+	p.sdk.AddAttribute(span, "feature_flag.provider_name", p.name)
+	if targetingKey != "" {
+		p.sdk.AddAttribute(span, "feature_flag.context.id", targetingKey)
+	}
+	if eval.Error != "" {
+		p.sdk.AddAttribute(span, "error.type", "flag_not_found")
+		p.sdk.AddEvent(span, "feature_flag.not_found")
+	} else {
+		p.sdk.SetSuccess(span)
+	}
+	return eval
+}
+
+// Enabled reports whether a flag serves "on" for targetingKey
+func (p *Provider) Enabled(ctx context.Context, key, targetingKey string) bool {
+	return p.Variant(ctx, key, targetingKey, "off").Variant == "on"
+}
+
+func (p *Provider) evaluate(key, targetingKey, fallback string) Evaluation {
+	flag, ok := p.flags[key]
+	if !ok {
+		return Evaluation{Key: key, Variant: fallback, Reason: ReasonError, Error: "flag not found"}
+	}
+	if variant, ok := p.overrides[key]; ok {
+		return Evaluation{Key: key, Variant: variant, Reason: ReasonStatic}
+	}
+	if len(flag.Rollout) > 0 && targetingKey != "" {
+		// Walk the variants in order so the buckets don't move between runs
+		bucket, upTo := int(hash(key+"/"+targetingKey)%100), 0
+		for _, variant := range flag.Variants {
+			if upTo += flag.Rollout[variant]; bucket < upTo {
+				return Evaluation{Key: key, Variant: variant, Reason: ReasonSplit}
+			}
+		}
+	}
+	return Evaluation{Key: key, Variant: flag.Default, Reason: ReasonDefault}
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
@@ -0,0 +1,27 @@
+// Package gql serves a small GraphQL API, built with gqlgen from
+// schema.graphqls, where every resolver runs in its own child span, so
+// GraphQL requests can be traced alongside REST ones.
+package gql
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+)
+
+// maxComplexity bounds how much work one query may ask for; every field
+// counts 1, so it's roughly the number of fields selected
+const maxComplexity = 100
+
+// NewHandler returns an HTTP handler for POST /graphql
+func NewHandler(sdk *tracekit.SDK) http.Handler {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(maxComplexity))
+	srv.Use(&tracer{sdk: sdk})
+	return srv
+}
@@ -0,0 +1,29 @@
+package gql
+
+// User is a user of the GraphQL API
+type User struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+// Order is one of a user's orders
+type Order struct {
+	ID     string
+	UserID string
+	Amount float64
+	Status string
+}
+
+var (
+	users = []User{
+		{"1", "Alice", "alice@example.com"},
+		{"2", "Bob", "bob@example.com"},
+		{"3", "Charlie", "charlie@example.com"},
+	}
+	orders = []Order{
+		{"ORD-1001", "1", 129.99, "shipped"},
+		{"ORD-1002", "1", 42.50, "pending"},
+		{"ORD-1003", "2", 310.00, "delivered"},
+	}
+)
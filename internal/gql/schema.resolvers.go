@@ -0,0 +1,77 @@
+package gql
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.86
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*User, error) {
+	time.Sleep(10 * time.Millisecond)
+	for _, u := range users {
+		if u.ID == id {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, limit *int) ([]*User, error) {
+	time.Sleep(15 * time.Millisecond)
+	// The schema defaults limit to 10; an explicit null gets that too
+	n := 10
+	if limit != nil {
+		n = *limit
+	}
+	if n < 0 {
+		return nil, errors.New("limit must not be negative")
+	}
+	n = min(n, len(users))
+
+	out := make([]*User, 0, n)
+	for i := range users[:n] {
+		out = append(out, &users[i])
+	}
+	return out, nil
+}
+
+// Order is the resolver for the order field.
+func (r *queryResolver) Order(ctx context.Context, id string) (*Order, error) {
+	time.Sleep(10 * time.Millisecond)
+	for _, o := range orders {
+		if o.ID == id {
+			return &o, nil
+		}
+	}
+	return nil, nil
+}
+
+// Orders is the resolver for the orders field. It's a nested resolver, so it
+// gets its own span under the parent field.
+func (r *userResolver) Orders(ctx context.Context, obj *User) ([]*Order, error) {
+	time.Sleep(20 * time.Millisecond)
+	// A nil slice would be null, which the schema doesn't allow
+	out := []*Order{}
+	for i, o := range orders {
+		if o.UserID == obj.ID {
+			out = append(out, &orders[i])
+		}
+	}
+	return out, nil
+}
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
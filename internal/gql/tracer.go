@@ -0,0 +1,88 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+)
+
+// tracer is a gqlgen extension that traces operations and resolvers with
+// the TraceKit SDK
+type tracer struct {
+	sdk *tracekit.SDK
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = (*tracer)(nil)
+
+func (t *tracer) ExtensionName() string {
+	return "TraceKitTracer"
+}
+
+func (t *tracer) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse wraps the whole operation in a span
+func (t *tracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	oc := graphql.GetOperationContext(ctx)
+	// The name in the document, which a request needn't repeat in its
+	// operationName
+	operation := oc.OperationName
+	if oc.Operation != nil && oc.Operation.Name != "" {
+		operation = oc.Operation.Name
+	}
+	name := "graphql.query"
+	if operation != "" {
+		name += " " + operation
+	}
+
+	ctx, span := t.sdk.StartSpan(ctx, name)
+	defer span.End()
+	t.sdk.AddAttribute(span, "graphql.operation.name", operation)
+	t.sdk.AddAttribute(span, "graphql.document", oc.RawQuery)
+	t.sdk.AddIntAttribute(span, "graphql.variables.count", int64(len(oc.Variables)))
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		t.sdk.AddIntAttribute(span, "graphql.errors.count", int64(len(resp.Errors)))
+		t.sdk.RecordError(span, resp.Errors[0])
+		return resp
+	}
+	t.sdk.SetSuccess(span)
+	return resp
+}
+
+// InterceptField starts a child span for every resolver call. Fields read
+// straight off a struct are skipped so the waterfall shows real work only.
+func (t *tracer) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if !fc.IsResolver {
+		return next(ctx)
+	}
+
+	label := fc.Object + "." + fc.Field.Name
+	ctx, span := t.sdk.StartSpan(ctx, "graphql.resolve "+label)
+	defer span.End()
+	t.sdk.AddAttribute(span, "graphql.resolver", label)
+	t.sdk.AddAttribute(span, "graphql.type", fc.Object)
+	t.sdk.AddAttribute(span, "graphql.field", fc.Field.Name)
+	// The arguments as sent, with variables filled in and schema defaults
+	// applied
+	for name, value := range fc.Field.ArgumentMap(graphql.GetOperationContext(ctx).Variables) {
+		t.sdk.AddAttribute(span, "graphql.args."+name, fmt.Sprintf("%v", value))
+	}
+
+	res, err := next(ctx)
+	if err != nil {
+		t.sdk.RecordError(span, err)
+		return res, err
+	}
+	t.sdk.SetSuccess(span)
+	return res, nil
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+)
+
+// maxAggregateBudget bounds ?budget_ms on /api/aggregate
+const maxAggregateBudget = 5 * time.Second
+
+// Aggregate queries every downstream service at once under one overall
+// budget (?budget_ms, default 200) and returns whatever arrived in time.
+// Sub-calls cut off by the budget are marked aggregate.abandoned on their
+// spans rather than failing the request.
+func (h *Handlers) Aggregate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "aggregate")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	budgetMs, err := strconv.Atoi(c.DefaultQuery("budget_ms", "200"))
+	budget := time.Duration(budgetMs) * time.Millisecond
+	if err != nil || budget <= 0 || budget > maxAggregateBudget {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("budget_ms must be between 1 and %d", maxAggregateBudget.Milliseconds())})
+		return
+	}
+
+	services := h.client.Services().All()
+	h.sdk.AddIntAttribute(span, "aggregate.budget_ms", int64(budgetMs))
+	h.sdk.AddIntAttribute(span, "fanout.services", int64(len(services)))
+
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	// Every sub-call honors ctx, so none outlives the budget
+	results := make([]map[string]interface{}, len(services))
+	var wg sync.WaitGroup
+	for i, svc := range services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.gatherOne(ctx, svc)
+		}()
+	}
+	wg.Wait()
+
+	var complete, abandoned, failed int
+	responses := gin.H{}
+	var missing []string
+	for i, r := range results {
+		name := services[i].Name
+		switch {
+		case r["abandoned"] == true:
+			abandoned++
+			missing = append(missing, name)
+		case r["error"] != nil:
+			failed++
+			missing = append(missing, name)
+		default:
+			complete++
+			responses[name] = r["response"]
+		}
+	}
+
+	h.sdk.AddIntAttribute(span, "aggregate.complete", int64(complete))
+	h.sdk.AddIntAttribute(span, "aggregate.abandoned", int64(abandoned))
+	h.sdk.AddIntAttribute(span, "aggregate.failed", int64(failed))
+	span.SetAttributes(attribute.Bool("aggregate.partial", complete < len(services)))
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"budget_ms": budgetMs,
+		"complete":  complete,
+		"abandoned": abandoned,
+		"failed":    failed,
+		"partial":   complete < len(services),
+		"responses": responses,
+		"missing":   missing,
+		"calls":     results,
+	})
+}
+
+// gatherOne calls /api/data on svc in a child span, recording whether the
+// call was abandoned because the overall budget ran out
+func (h *Handlers) gatherOne(ctx context.Context, svc clients.Service) map[string]interface{} {
+	ctx, span := h.sdk.StartSpan(ctx, "gather "+svc.Name)
+	defer span.End()
+
+	h.sdk.AddAttribute(span, "target.service", svc.Name)
+
+	start := time.Now()
+	resp, err := h.client.Get(ctx, svc, "/api/data")
+	elapsed := time.Since(start)
+	h.sdk.AddIntAttribute(span, "aggregate.elapsed_ms", elapsed.Milliseconds())
+
+	if errors.Is(err, context.DeadlineExceeded) || (err != nil && ctx.Err() != nil) {
+		span.SetAttributes(attribute.Bool("aggregate.abandoned", true))
+		h.sdk.AddAttribute(span, "aggregate.abandon_reason", "deadline")
+		h.sdk.AddEvent(span, "aggregate.abandoned")
+		return map[string]interface{}{
+			"service":    svc.Name,
+			"abandoned":  true,
+			"elapsed_ms": elapsed.Milliseconds(),
+		}
+	}
+
+	span.SetAttributes(attribute.Bool("aggregate.abandoned", false))
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		return map[string]interface{}{
+			"service": svc.Name,
+			"error":   err.Error(),
+		}
+	}
+
+	h.sdk.AddIntAttribute(span, "response.status", int64(resp.StatusCode))
+	h.sdk.SetSuccess(span)
+	return map[string]interface{}{
+		"service":    svc.Name,
+		"status":     resp.StatusCode,
+		"response":   resp.Body,
+		"elapsed_ms": elapsed.Milliseconds(),
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// Baggage sets baggage entries on the outgoing context, calls a downstream
+// that echoes them back, and returns both sides for comparison
+func (h *Handlers) Baggage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "baggageDemo")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	entries := map[string]string{
+		"tenant.id":    c.DefaultQuery("tenant", "acme"),
+		"feature.flag": c.DefaultQuery("flag", "new-checkout"),
+	}
+
+	// Keep any baggage the caller sent and add ours on top
+	bag := baggage.FromContext(ctx)
+	for key, value := range entries {
+		member, err := baggage.NewMemberRaw(key, value)
+		if err == nil {
+			bag, err = bag.SetMember(member)
+		}
+		if err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(400, gin.H{"error": "invalid baggage entry " + key + ": " + err.Error()})
+			return
+		}
+		h.sdk.AddAttribute(span, "baggage."+key, value)
+	}
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+
+	resp, err := h.client.Get(ctx, h.client.Services().Self, "/api/baggage/echo")
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(downstreamStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	h.sdk.AddEvent(span, "baggage.echoed")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"sent":   baggageMap(bag),
+		"echoed": resp.Body["baggage"],
+		"header": bag.String(),
+	})
+}
+
+// BaggageEcho returns the baggage received with the request, recording each
+// entry on the span since baggage isn't exported on its own
+func (h *Handlers) BaggageEcho(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "baggageEcho")
+	defer span.End()
+
+	bag := baggage.FromContext(ctx)
+	for _, member := range bag.Members() {
+		h.sdk.AddAttribute(span, "baggage."+member.Key(), member.Value())
+	}
+	h.sdk.AddIntAttribute(span, "baggage.members", int64(bag.Len()))
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"service": "go-test-app",
+		"baggage": baggageMap(bag),
+	})
+}
+
+func baggageMap(bag baggage.Baggage) map[string]string {
+	m := make(map[string]string, bag.Len())
+	for _, member := range bag.Members() {
+		m[member.Key()] = member.Value()
+	}
+	return m
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Health is the health check endpoint
+func (h *Handlers) Health(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"status":  "healthy",
+		"service": "go-test-app",
+		"time":    time.Now().Format(time.RFC3339),
+	})
+}
+
+// Internal is called back by Node.js during /api/chain
+func (h *Handlers) Internal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "internalEndpoint")
+	defer span.End()
+
+	time.Sleep(30 * time.Millisecond)
+
+	h.sdk.AddAttribute(span, "called.by", "node-test-app")
+	h.sdk.AddEvent(span, "internal.processed")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"message":   "Internal endpoint response from Go",
+		"service":   "go-test-app",
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+// Data is called by other services for distributed tracing
+func (h *Handlers) Data(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "processData")
+	defer span.End()
+
+	time.Sleep(30 * time.Millisecond)
+
+	h.sdk.AddAttribute(span, "data.source", "go-test-app")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"service":   "go-test-app",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"data": gin.H{
+			"go_version":   "1.21",
+			"random_value": rand.Intn(100),
+		},
+	})
+}
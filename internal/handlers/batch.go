@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/batch"
+)
+
+// batchItemRequest is the optional body of POST /api/batch-process
+type batchItemRequest struct {
+	Item string `json:"item"`
+}
+
+// BatchProcess adds one item to the shared batcher and waits for the batch
+// that processes it. The batch runs in its own trace, linked to this request
+// and to every other request in the same batch.
+func (h *Handlers) BatchProcess(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "batchProcess")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	var req batchItemRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(400, gin.H{"error": "invalid JSON body: " + err.Error()})
+			return
+		}
+	}
+	if req.Item == "" {
+		req.Item = fmt.Sprintf("item-%d", time.Now().UnixNano())
+	}
+	h.sdk.AddAttribute(span, "batch.item", req.Item)
+
+	result, err := h.batcher.Add(ctx, req.Item)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		status := 504
+		if errors.Is(err, batch.ErrStopped) {
+			status = 503
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "batch.id", result.BatchID)
+	h.sdk.AddAttribute(span, "batch.trace_id", result.TraceID)
+	h.sdk.AddIntAttribute(span, "batch.size", int64(result.Size))
+	h.sdk.AddEvent(span, "batch.completed")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"item":           req.Item,
+		"batch_id":       result.BatchID,
+		"batch_size":     result.Size,
+		"position":       result.Position,
+		"waited_ms":      result.Waited.Milliseconds(),
+		"batch_trace_id": result.TraceID,
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxBatchItems and maxBatchConcurrency bound POST /api/batch
+	maxBatchItems       = 1000
+	maxBatchConcurrency = 64
+)
+
+// batchRequest is the optional body of POST /api/batch
+type batchRequest struct {
+	Items []string `json:"items"`
+}
+
+// Batch processes a list of items with at most ?concurrency (default 8) in
+// flight at once. Each item is a child span; the parent records how many
+// were processed and failed. Without a body, ?count items (default 50) are
+// generated. ?failure_rate (default 0.05) makes items fail at random.
+func (h *Handlers) Batch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "batch")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	var req batchRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(400, gin.H{"error": "invalid JSON body: " + err.Error()})
+			return
+		}
+	}
+	if len(req.Items) == 0 {
+		count, err := strconv.Atoi(c.DefaultQuery("count", "50"))
+		if err != nil || count < 1 || count > maxBatchItems {
+			c.JSON(400, gin.H{"error": fmt.Sprintf("count must be between 1 and %d", maxBatchItems)})
+			return
+		}
+		for i := 0; i < count; i++ {
+			req.Items = append(req.Items, fmt.Sprintf("item-%d", i+1))
+		}
+	}
+	if len(req.Items) > maxBatchItems {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("at most %d items per batch", maxBatchItems)})
+		return
+	}
+
+	concurrency, err := strconv.Atoi(c.DefaultQuery("concurrency", "8"))
+	if err != nil || concurrency < 1 || concurrency > maxBatchConcurrency {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("concurrency must be between 1 and %d", maxBatchConcurrency)})
+		return
+	}
+	failureRate, err := strconv.ParseFloat(c.DefaultQuery("failure_rate", "0.05"), 64)
+	if err != nil || failureRate < 0 || failureRate > 1 {
+		c.JSON(400, gin.H{"error": "failure_rate must be between 0 and 1"})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "batch.size", int64(len(req.Items)))
+	h.sdk.AddIntAttribute(span, "batch.concurrency", int64(concurrency))
+
+	start := time.Now()
+
+	// The semaphore caps in-flight items; inFlight tracks the observed peak
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var processed, failed, inFlight, peak atomic.Int64
+	var failedMu sync.Mutex
+	var failedItems []string
+
+	for i, item := range req.Items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				inFlight.Add(-1)
+				<-sem
+				wg.Done()
+			}()
+			n := inFlight.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+
+			if err := h.processBatchItem(ctx, i, item, failureRate); err != nil {
+				failed.Add(1)
+				failedMu.Lock()
+				failedItems = append(failedItems, item)
+				failedMu.Unlock()
+				return
+			}
+			processed.Add(1)
+		}()
+	}
+	wg.Wait()
+
+	duration := time.Since(start)
+	h.sdk.AddIntAttribute(span, "batch.processed", processed.Load())
+	h.sdk.AddIntAttribute(span, "batch.failed", failed.Load())
+	h.sdk.AddIntAttribute(span, "batch.max_in_flight", peak.Load())
+	h.sdk.AddIntAttribute(span, "batch.duration_ms", duration.Milliseconds())
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"size":          len(req.Items),
+		"concurrency":   concurrency,
+		"processed":     processed.Load(),
+		"failed":        failed.Load(),
+		"failed_items":  failedItems,
+		"max_in_flight": peak.Load(),
+		"duration_ms":   duration.Milliseconds(),
+	})
+}
+
+// processBatchItem simulates work on one item in its own child span
+func (h *Handlers) processBatchItem(ctx context.Context, index int, item string, failureRate float64) error {
+	_, span := h.sdk.StartSpan(ctx, "batch.item")
+	defer span.End()
+
+	h.sdk.AddIntAttribute(span, "batch.item.index", int64(index))
+	h.sdk.AddAttribute(span, "batch.item.id", item)
+
+	time.Sleep(time.Duration(10+rand.Intn(40)) * time.Millisecond)
+
+	if rand.Float64() < failureRate {
+		err := fmt.Errorf("processing %s failed", item)
+		h.sdk.RecordError(span, err)
+		return err
+	}
+	h.sdk.SetSuccess(span)
+	return nil
+}
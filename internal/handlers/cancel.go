@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxCancelDemoWork stays under the self client's timeout so the demo is
+// always ended by the deadline or the client, never the HTTP client
+const maxCancelDemoWork = 8 * time.Second
+
+// statusClientClosedRequest is nginx's status for a client that hung up
+const statusClientClosedRequest = 499
+
+// CancelDemo starts slow downstream work (this app's /api/chaos, with
+// work_ms of latency) and lets either the client or an optional deadline
+// cancel it, e.g. /api/cancel-demo?work_ms=5000&timeout_ms=1000. The span
+// records why and after how long the work was cancelled.
+func (h *Handlers) CancelDemo(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "cancelDemo")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	work, err := strconv.Atoi(c.DefaultQuery("work_ms", "5000"))
+	if err != nil || work < 0 || time.Duration(work)*time.Millisecond > maxCancelDemoWork {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("work_ms must be between 0 and %d", maxCancelDemoWork.Milliseconds())})
+		return
+	}
+	timeout, err := strconv.Atoi(c.DefaultQuery("timeout_ms", "0"))
+	if err != nil || timeout < 0 {
+		c.JSON(400, gin.H{"error": "timeout_ms must be a non-negative integer (0 for no deadline)"})
+		return
+	}
+	h.sdk.AddIntAttribute(span, "cancel_demo.work_ms", int64(work))
+	h.sdk.AddIntAttribute(span, "cancel_demo.timeout_ms", int64(timeout))
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+		defer cancel()
+	}
+
+	start := time.Now()
+	_, err = h.client.Get(ctx, h.client.Services().Self, fmt.Sprintf("/api/chaos?latency_ms=%d", work))
+	elapsed := time.Since(start)
+
+	switch {
+	case err == nil:
+		h.sdk.AddIntAttribute(span, "cancel_demo.elapsed_ms", elapsed.Milliseconds())
+		h.sdk.SetSuccess(span)
+		c.JSON(200, gin.H{"completed": true, "elapsed_ms": elapsed.Milliseconds()})
+
+	case errors.Is(err, context.DeadlineExceeded):
+		// Our own deadline: the work was too slow, which is a failure
+		recordCancellation(span, "deadline_exceeded", elapsed)
+		h.sdk.RecordError(span, err)
+		c.JSON(504, gin.H{
+			"completed":  false,
+			"reason":     "deadline exceeded",
+			"elapsed_ms": elapsed.Milliseconds(),
+		})
+
+	case errors.Is(err, context.Canceled):
+		// The client hung up; nobody is waiting for an answer, and it isn't
+		// a server error
+		recordCancellation(span, "client_disconnected", elapsed)
+		c.AbortWithStatus(statusClientClosedRequest)
+
+	default:
+		h.sdk.RecordError(span, err)
+		c.JSON(downstreamStatus(err), gin.H{"error": err.Error()})
+	}
+}
+
+// recordCancellation marks span as cancelled, with the cause and how long
+// the work ran first
+func recordCancellation(span trace.Span, cause string, elapsed time.Duration) {
+	span.SetAttributes(
+		attribute.Bool("context.canceled", true),
+		attribute.String("context.cancel_cause", cause),
+		attribute.Int64("context.elapsed_ms", elapsed.Milliseconds()),
+	)
+	span.AddEvent("context.canceled", trace.WithAttributes(
+		attribute.String("context.cancel_cause", cause),
+	))
+}
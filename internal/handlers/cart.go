@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/cart"
+	"github.com/Tracekit-Dev/test-app/internal/database"
+)
+
+// sessionCookie identifies a shopper across requests; clients without
+// cookies can send the same value in X-Session-ID instead
+const sessionCookie = "session_id"
+
+// ViewCart returns the session's cart
+func (h *Handlers) ViewCart(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "viewCart")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	sessionID := h.cartSession(c, span)
+	ct := h.carts.Load(ctx, sessionID)
+	h.tagCart(span, ct)
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, cartResponse(ct))
+}
+
+// AddToCart adds {"sku", "quantity", "price"} to the session's cart
+func (h *Handlers) AddToCart(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "addToCart")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	var item cart.Item
+	if err := c.ShouldBindJSON(&item); err != nil || item.SKU == "" {
+		c.JSON(400, gin.H{"error": "Invalid item", "message": `Send {"sku": "...", "quantity": 1, "price": 9.99}`})
+		return
+	}
+	if item.Quantity <= 0 {
+		item.Quantity = 1
+	}
+
+	sessionID := h.cartSession(c, span)
+	ct := h.carts.Load(ctx, sessionID)
+	ct.Add(item)
+	h.carts.Save(ctx, ct)
+
+	h.sdk.AddAttribute(span, "cart.item.sku", item.SKU)
+	h.sdk.AddIntAttribute(span, "cart.item.quantity", int64(item.Quantity))
+	h.tagCart(span, ct)
+	h.sdk.AddEvent(span, "cart.item_added")
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, cartResponse(ct))
+}
+
+// CheckoutCart turns the session's cart into an order and empties it
+func (h *Handlers) CheckoutCart(c *gin.Context) {
+	defer h.metrics.trackRequest()()
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "checkoutCart")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	sessionID := h.cartSession(c, span)
+	ct := h.carts.Load(ctx, sessionID)
+	h.tagCart(span, ct)
+
+	if len(ct.Items) == 0 {
+		h.sdk.AddEvent(span, "cart.empty")
+		c.JSON(409, gin.H{"error": cart.ErrEmpty.Error(), "session_id": sessionID, "cart_id": ct.ID})
+		return
+	}
+
+	orderID := fmt.Sprintf("ORD-%d", time.Now().UnixNano())
+	total := ct.Total()
+
+	h.metrics.OrderCounter.Inc()
+	h.metrics.OrderAmountHisto.Record(total)
+
+	h.sdk.AddBusinessAttributes(span, map[string]interface{}{
+		"order.id":     orderID,
+		"order.amount": total,
+		"customer.id":  sessionID,
+	})
+
+	persisted := false
+	if h.orderStore != nil {
+		order := database.Order{
+			ID:         orderID,
+			CustomerID: sessionID,
+			Amount:     total,
+			Status:     "created",
+			CreatedAt:  time.Now(),
+		}
+		if err := h.orderStore.InsertOrder(ctx, order); err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(500, gin.H{"error": "Failed to store order", "message": err.Error()})
+			return
+		}
+		persisted = true
+	}
+
+	h.carts.Delete(ctx, sessionID)
+	h.sdk.AddEvent(span, "cart.checked_out")
+
+	h.sdk.SetSuccess(span)
+	c.JSON(201, gin.H{
+		"order_id":   orderID,
+		"session_id": sessionID,
+		"cart_id":    ct.ID,
+		"items":      ct.Items,
+		"total":      total,
+		"persisted":  persisted,
+	})
+}
+
+// cartSession returns the caller's session ID from the cookie or
+// X-Session-ID, starting a new session when there is neither
+func (h *Handlers) cartSession(c *gin.Context, span trace.Span) string {
+	sessionID, err := c.Cookie(sessionCookie)
+	if err != nil || sessionID == "" {
+		sessionID = c.GetHeader("X-Session-ID")
+	}
+
+	isNew := sessionID == ""
+	if isNew {
+		sessionID = cart.NewSessionID()
+		c.SetCookie(sessionCookie, sessionID, 0, "/api/cart", "", false, true)
+	}
+
+	h.sdk.AddAttribute(span, "session.id", sessionID)
+	span.SetAttributes(attribute.Bool("session.new", isNew))
+	return sessionID
+}
+
+// tagCart records the cart's identity and contents on span
+func (h *Handlers) tagCart(span trace.Span, ct *cart.Cart) {
+	h.sdk.AddAttribute(span, "cart.id", ct.ID)
+	h.sdk.AddIntAttribute(span, "cart.item_count", int64(ct.ItemCount()))
+	h.sdk.AddFloatAttribute(span, "cart.total", ct.Total())
+}
+
+func cartResponse(ct *cart.Cart) gin.H {
+	return gin.H{
+		"session_id": ct.SessionID,
+		"cart_id":    ct.ID,
+		"items":      ct.Items,
+		"item_count": ct.ItemCount(),
+		"total":      ct.Total(),
+	}
+}
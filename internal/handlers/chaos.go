@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/chaos"
+)
+
+// Chaos injects latency and random failures described by the query string,
+// e.g. /api/chaos?latency_ms=200&jitter_ms=100&error_rate=0.3&status=503
+func (h *Handlers) Chaos(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "chaos")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	fault, status, err := parseFault(c)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	delay, err := chaos.Inject(ctx, h.sdk, fault)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		if !errors.Is(err, chaos.ErrInjected) {
+			// The client went away mid-sleep; nobody is left to answer
+			c.Abort()
+			return
+		}
+		h.sdk.AddIntAttribute(span, "http.injected_status", int64(status))
+		c.JSON(status, gin.H{
+			"error":      "Injected failure",
+			"latency_ms": delay.Milliseconds(),
+			"error_rate": fault.ErrorRate,
+		})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"message":    "Survived the chaos",
+		"latency_ms": delay.Milliseconds(),
+		"error_rate": fault.ErrorRate,
+	})
+}
+
+// parseFault reads the chaos parameters and the status code to fail with
+func parseFault(c *gin.Context) (chaos.Fault, int, error) {
+	var fault chaos.Fault
+
+	latency, err := strconv.Atoi(c.DefaultQuery("latency_ms", "0"))
+	if err != nil {
+		return fault, 0, errors.New("latency_ms must be an integer")
+	}
+	jitter, err := strconv.Atoi(c.DefaultQuery("jitter_ms", "0"))
+	if err != nil {
+		return fault, 0, errors.New("jitter_ms must be an integer")
+	}
+	rate, err := strconv.ParseFloat(c.DefaultQuery("error_rate", "0"), 64)
+	if err != nil {
+		return fault, 0, errors.New("error_rate must be a number")
+	}
+	status, err := strconv.Atoi(c.DefaultQuery("status", "500"))
+	if err != nil || status < 400 || status > 599 {
+		return fault, 0, errors.New("status must be an HTTP error code between 400 and 599")
+	}
+
+	fault = chaos.Fault{
+		Latency:   time.Duration(latency) * time.Millisecond,
+		Jitter:    time.Duration(jitter) * time.Millisecond,
+		ErrorRate: rate,
+	}
+	return fault, status, fault.Validate()
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Tracekit-Dev/test-app/internal/respcache"
+	"github.com/Tracekit-Dev/test-app/internal/userstore"
+)
+
+// UserProfile returns one user as JSON with an ETag of the body, answering
+// 304 without a body when If-None-Match already names it, so a client that
+// revalidates sees the span skip the transfer until the user is updated
+func (h *Handlers) UserProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "getUserProfile")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id, ok := h.validateUserID(c)
+	if !ok {
+		return
+	}
+	h.sdk.AddIntAttribute(span, "user.id", int64(id))
+
+	user, err := h.users.Get(ctx, id)
+	if errors.Is(err, userstore.ErrNotFound) {
+		h.sdk.AddEvent(span, "user.not_found")
+		c.JSON(404, gin.H{"error": "User not found", "user_id": id})
+		return
+	}
+
+	body, err := json.Marshal(user)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to encode user", "message": err.Error()})
+		return
+	}
+	etag := etagOf(body)
+	h.sdk.AddAttribute(span, "http.etag", etag)
+	c.Header("ETag", etag)
+
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	span.SetAttributes(attribute.Bool("http.conditional", ifNoneMatch != ""))
+	if respcache.ETagMatches(ifNoneMatch, etag) {
+		span.SetAttributes(attribute.Bool("http.etag_match", true))
+		h.sdk.AddIntAttribute(span, "http.response.skipped_body_bytes", int64(len(body)))
+		h.sdk.SetSuccess(span)
+		c.Status(304)
+		return
+	}
+
+	span.SetAttributes(attribute.Bool("http.etag_match", false))
+	h.sdk.SetSuccess(span)
+	c.Data(200, "application/json; charset=utf-8", body)
+}
+
+// etagOf is a strong ETag of body
+func etagOf(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
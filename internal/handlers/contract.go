@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// contractVersion changes whenever a scenario's span tree changes, so
+// conformance checks can pin the trees they expect
+const contractVersion = 1
+
+// contractSpan is one span of a contract scenario and the spans under it.
+// Nothing in a scenario is random, so every request produces the same tree.
+type contractSpan struct {
+	name   string
+	kind   trace.SpanKind
+	attrs  []attribute.KeyValue
+	events []string
+	// err, when set, is recorded on the span and marks it failed
+	err      string
+	children []contractSpan
+}
+
+// contractScenarios are the span trees /api/contract/:scenario produces, each
+// under a "contract <scenario>" span
+var contractScenarios = map[string][]contractSpan{
+	// One span with an attribute of every type
+	"attributes": {{
+		name: "contract.attributes",
+		attrs: []attribute.KeyValue{
+			attribute.String("contract.string", "tracekit"),
+			attribute.Int64("contract.int", 42),
+			attribute.Float64("contract.float", 3.5),
+			attribute.Bool("contract.bool", true),
+			attribute.StringSlice("contract.strings", []string{"a", "b", "c"}),
+			attribute.Int64Slice("contract.ints", []int64{1, 2, 3}),
+		},
+	}},
+	// Three levels deep
+	"nested": {{
+		name: "contract.level1",
+		children: []contractSpan{{
+			name: "contract.level2",
+			children: []contractSpan{{
+				name:  "contract.level3",
+				attrs: []attribute.KeyValue{attribute.Int64("contract.depth", 3)},
+			}},
+		}},
+	}},
+	// Siblings in a fixed start order
+	"siblings": {
+		{name: "contract.step", attrs: []attribute.KeyValue{attribute.Int64("contract.step", 1)}},
+		{name: "contract.step", attrs: []attribute.KeyValue{attribute.Int64("contract.step", 2)}},
+		{name: "contract.step", attrs: []attribute.KeyValue{attribute.Int64("contract.step", 3)}},
+	},
+	// A failed child under a successful parent
+	"error": {
+		{name: "contract.ok"},
+		{name: "contract.failing", err: "contract: simulated failure", events: []string{"contract.retry_skipped"}},
+	},
+	// Events in order on one span
+	"events": {{
+		name:   "contract.events",
+		events: []string{"contract.started", "contract.checkpoint", "contract.finished"},
+	}},
+	// One span of every non-internal kind
+	"kinds": {
+		{name: "contract.client", kind: trace.SpanKindClient},
+		{name: "contract.producer", kind: trace.SpanKindProducer},
+		{name: "contract.consumer", kind: trace.SpanKindConsumer},
+	},
+}
+
+// Contract produces the fixed span tree of :scenario and answers with the
+// tree it produced, so a conformance check can compare it with what the
+// backend received
+func (h *Handlers) Contract(c *gin.Context) {
+	scenario := c.Param("scenario")
+	spans, ok := contractScenarios[scenario]
+	if !ok {
+		supported := make([]string, 0, len(contractScenarios))
+		for name := range contractScenarios {
+			supported = append(supported, name)
+		}
+		slices.Sort(supported)
+		c.JSON(404, gin.H{"error": "Unknown contract scenario", "supported": supported})
+		return
+	}
+
+	root := contractSpan{
+		name: "contract " + scenario,
+		attrs: []attribute.KeyValue{
+			attribute.String("contract.scenario", scenario),
+			attribute.Int("contract.version", contractVersion),
+		},
+		children: spans,
+	}
+	ctx := h.emitContract(c.Request.Context(), root)
+	sc := trace.SpanContextFromContext(ctx)
+
+	c.JSON(200, gin.H{
+		"scenario":    scenario,
+		"version":     contractVersion,
+		"trace_id":    sc.TraceID().String(),
+		"server_span": c.Request.Method + " " + c.FullPath(),
+		"spans":       contractJSON(root),
+	})
+}
+
+// emitContract produces s and its children one after another, returning the
+// context it was started from so the caller can read the trace ID
+func (h *Handlers) emitContract(ctx context.Context, s contractSpan) context.Context {
+	var span trace.Span
+	if s.kind == trace.SpanKindUnspecified || s.kind == trace.SpanKindInternal {
+		ctx, span = h.sdk.StartSpan(ctx, s.name)
+	} else {
+		ctx, span = tracing.Tracer().Start(ctx, s.name, trace.WithSpanKind(s.kind))
+	}
+	defer span.End()
+
+	span.SetAttributes(s.attrs...)
+	for _, e := range s.events {
+		h.sdk.AddEvent(span, e)
+	}
+	for _, child := range s.children {
+		h.emitContract(ctx, child)
+	}
+
+	if s.err != "" {
+		h.sdk.RecordError(span, errors.New(s.err))
+	} else {
+		h.sdk.SetSuccess(span)
+	}
+	return ctx
+}
+
+// contractJSON describes s the way a conformance check should find it
+func contractJSON(s contractSpan) gin.H {
+	kind := s.kind
+	if kind == trace.SpanKindUnspecified {
+		kind = trace.SpanKindInternal
+	}
+	out := gin.H{"name": s.name, "kind": kind.String(), "status": "ok"}
+	if s.err != "" {
+		out["status"] = "error"
+		out["error"] = s.err
+	}
+	if len(s.attrs) > 0 {
+		attrs := gin.H{}
+		for _, kv := range s.attrs {
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		out["attributes"] = attrs
+	}
+	if len(s.events) > 0 {
+		out["events"] = s.events
+	}
+	if len(s.children) > 0 {
+		children := make([]gin.H, 0, len(s.children))
+		for _, child := range s.children {
+			children = append(children, contractJSON(child))
+		}
+		out["children"] = children
+	}
+	return out
+}
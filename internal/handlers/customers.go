@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"hash/fnv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CustomerOrder returns one order of one customer. It exists to show route
+// template span naming with two path parameters: every call shares the span
+// name "GET /api/customers/:id/orders/:order_id".
+func (h *Handlers) CustomerOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "getCustomerOrder")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	customerID, orderID := c.Param("id"), c.Param("order_id")
+	h.sdk.AddAttribute(span, "customer.id", customerID)
+	h.sdk.AddAttribute(span, "order.id", orderID)
+
+	// Derive a stable total so repeated calls return the same order
+	sum := fnv.New32a()
+	sum.Write([]byte(customerID + "/" + orderID))
+	total := float64(sum.Sum32()%50000) / 100
+	h.sdk.AddFloatAttribute(span, "order.total", total)
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"customer_id": customerID,
+		"order_id":    orderID,
+		"total":       total,
+		"route":       c.FullPath(),
+	})
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/mongodb"
+)
+
+// requireMongo answers 503 when MongoDB isn't configured
+func (h *Handlers) requireMongo(c *gin.Context) bool {
+	if h.mongo == nil {
+		c.JSON(503, gin.H{
+			"error":   "MongoDB not configured",
+			"message": "Set MONGODB_URI to enable this endpoint",
+		})
+		return false
+	}
+	return true
+}
+
+// ListDocuments returns the newest documents; the find command gets a DB span
+func (h *Handlers) ListDocuments(c *gin.Context) {
+	if !h.requireMongo(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "listDocuments")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	h.sdk.AddIntAttribute(span, "query.limit", int64(limit))
+
+	docs, err := h.mongo.ListDocuments(ctx, limit)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to list documents", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "document_count", int64(len(docs)))
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{"documents": docs, "source": "mongodb"})
+}
+
+// CreateDocument inserts a document from the JSON body
+func (h *Handlers) CreateDocument(c *gin.Context) {
+	if !h.requireMongo(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "createDocument")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	var doc mongodb.Document
+	if err := c.ShouldBindJSON(&doc); err != nil || doc.Title == "" {
+		c.JSON(400, gin.H{"error": "title is required"})
+		return
+	}
+
+	doc, err := h.mongo.CreateDocument(ctx, doc)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to create document", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "document.id", doc.ID.Hex())
+	h.sdk.SetSuccess(span)
+	c.JSON(201, doc)
+}
+
+// GetDocument returns one document by ID
+func (h *Handlers) GetDocument(c *gin.Context) {
+	if !h.requireMongo(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "getDocument")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id := c.Param("id")
+	h.sdk.AddAttribute(span, "document.id", id)
+
+	doc, err := h.mongo.GetDocument(ctx, id)
+	if err != nil {
+		h.documentError(c, span, err)
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, doc)
+}
+
+// UpdateDocument changes the fields present in the JSON body
+func (h *Handlers) UpdateDocument(c *gin.Context) {
+	if !h.requireMongo(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "updateDocument")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id := c.Param("id")
+	h.sdk.AddAttribute(span, "document.id", id)
+
+	var update mongodb.DocumentUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(400, gin.H{"error": "invalid JSON body: " + err.Error()})
+		return
+	}
+
+	doc, err := h.mongo.UpdateDocument(ctx, id, update)
+	if err != nil {
+		h.documentError(c, span, err)
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, doc)
+}
+
+// DeleteDocument removes one document by ID
+func (h *Handlers) DeleteDocument(c *gin.Context) {
+	if !h.requireMongo(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "deleteDocument")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id := c.Param("id")
+	h.sdk.AddAttribute(span, "document.id", id)
+
+	if err := h.mongo.DeleteDocument(ctx, id); err != nil {
+		h.documentError(c, span, err)
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.Status(204)
+}
+
+// documentError answers 404 for unknown documents and 500 otherwise
+func (h *Handlers) documentError(c *gin.Context, span trace.Span, err error) {
+	if errors.Is(err, mongodb.ErrNotFound) {
+		h.sdk.AddEvent(span, "document.not_found")
+		c.JSON(404, gin.H{"error": "Document not found"})
+		return
+	}
+	h.sdk.RecordError(span, err)
+	c.JSON(500, gin.H{"error": "MongoDB operation failed", "message": err.Error()})
+}
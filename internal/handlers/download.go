@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxDownloadMB caps /api/download/:size
+	maxDownloadMB = 100
+
+	// downloadChunk is how much is written between flushes
+	downloadChunk = 64 << 10
+)
+
+// Download streams a generated payload of :size megabytes, e.g.
+// /api/download/10, recording bytes written, throughput, and whether the
+// client disconnected before the end
+func (h *Handlers) Download(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "download")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	sizeMB, err := strconv.Atoi(c.Param("size"))
+	if err != nil || sizeMB < 1 || sizeMB > maxDownloadMB {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("size must be between 1 and %d (megabytes)", maxDownloadMB)})
+		return
+	}
+	size := int64(sizeMB) << 20
+	h.sdk.AddIntAttribute(span, "download.size_bytes", size)
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Length", strconv.FormatInt(size, 10))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="payload-%dmb.bin"`, sizeMB))
+	c.Status(200)
+
+	chunk := make([]byte, downloadChunk)
+	for i := range chunk {
+		chunk[i] = byte('a' + i%26)
+	}
+
+	start := time.Now()
+	var written int64
+	disconnected := false
+	nextProgress := size / 4
+
+	for written < size {
+		if ctx.Err() != nil {
+			disconnected = true
+			break
+		}
+		n := min(int64(len(chunk)), size-written)
+		wrote, err := c.Writer.Write(chunk[:n])
+		written += int64(wrote)
+		if err != nil {
+			disconnected = true
+			break
+		}
+		c.Writer.Flush()
+
+		// One event per quarter keeps the span small for large payloads
+		if written >= nextProgress && written < size {
+			span.AddEvent("download.progress", trace.WithAttributes(
+				attribute.Int64("download.bytes_written", written),
+				attribute.Int64("download.percent", written*100/size),
+			))
+			nextProgress += size / 4
+		}
+	}
+
+	elapsed := time.Since(start)
+	h.sdk.AddIntAttribute(span, "download.bytes_written", written)
+	h.sdk.AddIntAttribute(span, "download.duration_ms", elapsed.Milliseconds())
+	if elapsed > 0 {
+		h.sdk.AddFloatAttribute(span, "download.throughput_mbps", float64(written)/(1<<20)/elapsed.Seconds())
+	}
+	span.SetAttributes(attribute.Bool("download.client_disconnected", disconnected))
+
+	if disconnected {
+		// Client went away; not a server error, but worth seeing in the trace
+		h.sdk.AddEvent(span, "download.client_disconnected")
+		return
+	}
+	h.sdk.SetSuccess(span)
+}
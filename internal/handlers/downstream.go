@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+)
+
+// CallService returns the handler of /api/call-<route>, which calls the
+// service at route - tests CLIENT spans. There's one per service in the
+// topology, and each looks its service up per request so a reloaded
+// topology takes effect.
+func (h *Handlers) CallService(route string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		svc, ok := h.client.Services().Lookup(route)
+		if !ok {
+			c.JSON(404, gin.H{"error": "No " + route + " service in the service topology"})
+			return
+		}
+		h.callService(c, svc)
+	}
+}
+
+// Chain calls Node.js which calls back into /api/internal - tests circular calls
+func (h *Handlers) Chain(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "chainCall")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	node, ok := h.client.Services().Lookup("node")
+	if !ok {
+		c.JSON(503, gin.H{"error": "No node service in the service topology"})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "chain.initiator", "go-test-app")
+	h.sdk.AddEvent(span, "chain.started")
+
+	// Call Node.js which will call us back
+	resp, err := h.client.Get(ctx, node, "/api/call-go")
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		if isRequestError(err) {
+			c.JSON(500, gin.H{"error": "Failed to create request"})
+			return
+		}
+		c.JSON(downstreamStatus(err), gin.H{"error": fmt.Sprintf("Chain call failed: %v", err)})
+		return
+	}
+
+	h.sdk.AddEvent(span, "chain.completed")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"message":       "Chain call completed",
+		"node_response": resp.Body,
+	})
+}
+
+// callService calls /api/data on svc inside the span the topology names
+func (h *Handlers) callService(c *gin.Context, svc clients.Service) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, svc.Span)
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	h.sdk.AddAttribute(span, "target.service", svc.Name)
+	h.sdk.AddAttribute(span, "target.protocol", svc.Protocol)
+
+	resp, err := h.client.Get(ctx, svc, "/api/data")
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		logging.FromContext(ctx).Warn("Downstream call failed", zap.String("service", svc.Name), zap.Error(err))
+		message := err.Error()
+		if isRequestError(err) {
+			message = "Failed to create request"
+		}
+		c.JSON(downstreamStatus(err), gin.H{"service": "go-test-app", "called": svc.Name, "error": message})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"service":  "go-test-app",
+		"called":   svc.Name,
+		"response": resp.Body,
+		"status":   resp.StatusCode,
+	})
+}
+
+// CallAll calls every downstream service concurrently, each in its own child
+// span, so the trace shows the CLIENT spans overlapping. A failing service is
+// reported in its own entry and doesn't cancel the others.
+func (h *Handlers) CallAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "callAllServices")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	services := h.client.Services().All()
+	h.sdk.AddIntAttribute(span, "fanout.services", int64(len(services)))
+
+	// Results keep call order regardless of which service answers first
+	chain := make([]map[string]interface{}, len(services))
+
+	var g errgroup.Group
+	for i, svc := range services {
+		g.Go(func() error {
+			chain[i] = h.callOne(ctx, svc)
+			return nil
+		})
+	}
+	g.Wait()
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"service": "go-test-app",
+		"chain":   chain,
+	})
+}
+
+// callOne calls /api/data on svc in a child span and describes the outcome
+func (h *Handlers) callOne(ctx context.Context, svc clients.Service) map[string]interface{} {
+	ctx, span := h.sdk.StartSpan(ctx, "call "+svc.Name)
+	defer span.End()
+
+	h.sdk.AddAttribute(span, "target.service", svc.Name)
+
+	resp, err := h.client.Get(ctx, svc, "/api/data")
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		message := err.Error()
+		if isRequestError(err) {
+			message = "Failed to create request"
+		}
+		return map[string]interface{}{
+			"service": svc.Name,
+			"error":   message,
+		}
+	}
+
+	h.sdk.AddIntAttribute(span, "response.status", int64(resp.StatusCode))
+	h.sdk.SetSuccess(span)
+	return map[string]interface{}{
+		"service":  svc.Name,
+		"status":   resp.StatusCode,
+		"response": resp.Body,
+	}
+}
+
+// Breakers reports the circuit breaker state of each downstream service
+func (h *Handlers) Breakers(c *gin.Context) {
+	c.JSON(200, gin.H{"breakers": h.client.BreakerStates()})
+}
+
+// downstreamStatus picks the response status for a failed downstream call:
+// 503 while the breaker sheds load, 500 otherwise
+func downstreamStatus(err error) int {
+	if errors.Is(err, clients.ErrCircuitOpen) {
+		return 503
+	}
+	return 500
+}
+
+// isRequestError reports whether err came from building the request
+// rather than from the call itself
+func isRequestError(err error) bool {
+	var reqErr *clients.RequestError
+	return errors.As(err, &reqErr)
+}
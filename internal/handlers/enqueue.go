@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/messaging"
+)
+
+// Enqueue publishes a task to RabbitMQ with the trace context in the AMQP
+// headers; the consumer continues the same trace. ?fail_once=true makes the
+// first delivery fail so the redelivery can be seen.
+func (h *Handlers) Enqueue(c *gin.Context) {
+	if h.rabbit == nil {
+		c.JSON(503, gin.H{
+			"error":   "RabbitMQ not configured",
+			"message": "Set RABBITMQ_URL to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "enqueueTask")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	task := messaging.Task{
+		ID:       fmt.Sprintf("task-%d", time.Now().UnixNano()),
+		Type:     c.DefaultQuery("type", "email"),
+		FailOnce: c.Query("fail_once") == "true",
+	}
+	h.sdk.AddAttribute(span, "task.id", task.ID)
+	h.sdk.AddAttribute(span, "task.type", task.Type)
+
+	if err := h.rabbit.Publish(ctx, task); err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{"error": "Failed to enqueue task", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddEvent(span, "task.enqueued")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(202, gin.H{
+		"task_id": task.ID,
+		"type":    task.Type,
+		"status":  "enqueued",
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/messaging"
+)
+
+// EnqueueSQS sends a task to SQS with the trace context in the message
+// attributes; the polling worker continues the same trace
+func (h *Handlers) EnqueueSQS(c *gin.Context) {
+	if h.sqs == nil {
+		c.JSON(503, gin.H{
+			"error":   "SQS not configured",
+			"message": "Set SQS_QUEUE_URL to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "enqueueSQSTask")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	task := messaging.Task{
+		ID:   fmt.Sprintf("task-%d", time.Now().UnixNano()),
+		Type: c.DefaultQuery("type", "thumbnail"),
+	}
+	h.sdk.AddAttribute(span, "task.id", task.ID)
+	h.sdk.AddAttribute(span, "task.type", task.Type)
+
+	if err := h.sqs.Send(ctx, task); err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{"error": "Failed to enqueue task", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddEvent(span, "task.enqueued")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(202, gin.H{
+		"task_id": task.ID,
+		"type":    task.Type,
+		"status":  "enqueued",
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Error classes recorded as error.class, so a backend can tell failures the
+// caller caused from failures this service caused
+const (
+	errorClassClient    = "client_error"
+	errorClassThrottled = "throttled"
+	errorClassServer    = "server_error"
+)
+
+// errorCode is one status served by /api/errors/:code
+type errorCode struct {
+	class   string
+	reason  string
+	message string
+}
+
+// errorCodes are the statuses /api/errors/:code can return
+var errorCodes = map[int]errorCode{
+	400: {errorClassClient, "bad_request", "The request body failed validation"},
+	401: {errorClassClient, "unauthenticated", "Missing or expired credentials"},
+	403: {errorClassClient, "forbidden", "Credentials lack the orders:write scope"},
+	404: {errorClassClient, "not_found", "No such resource"},
+	429: {errorClassThrottled, "rate_limited", "Too many requests, retry later"},
+	503: {errorClassServer, "unavailable", "Dependency unavailable, retry later"},
+}
+
+// retryAfterSeconds is sent with throttled and unavailable responses
+const retryAfterSeconds = 5
+
+// ErrorCode answers with the requested error status and classifies it on the
+// spans. Only server errors mark the span as failed; client errors and
+// throttling are the caller's doing and are recorded as events instead.
+func (h *Handlers) ErrorCode(c *gin.Context) {
+	ctx := c.Request.Context()
+	server := trace.SpanFromContext(ctx)
+
+	ctx, span := h.sdk.StartSpan(ctx, "errorCode")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	code, err := strconv.Atoi(c.Param("code"))
+	ec, ok := errorCodes[code]
+	if err != nil || !ok {
+		supported := make([]int, 0, len(errorCodes))
+		for code := range errorCodes {
+			supported = append(supported, code)
+		}
+		slices.Sort(supported)
+		c.JSON(400, gin.H{"error": "Unsupported error code", "supported": supported})
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("error.class", ec.class),
+		attribute.String("error.reason", ec.reason),
+	}
+	span.SetAttributes(attrs...)
+	server.SetAttributes(attrs...)
+
+	switch ec.class {
+	case errorClassServer:
+		h.sdk.RecordError(span, errors.New(ec.message))
+	case errorClassThrottled:
+		h.sdk.AddEvent(span, "request.throttled")
+	default:
+		h.sdk.AddEvent(span, "request.rejected")
+	}
+
+	switch code {
+	case 401:
+		c.Header("WWW-Authenticate", `Bearer realm="tracekit-example"`)
+	case 429, 503:
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds))
+		h.sdk.AddIntAttribute(span, "retry_after_s", retryAfterSeconds)
+	}
+
+	c.JSON(code, gin.H{
+		"error":   ec.reason,
+		"message": ec.message,
+		"class":   ec.class,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+)
+
+// Error triggers a simulated error
+func (h *Handlers) Error(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "triggerError")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	errorType := "gateway_timeout"
+	retryCount := 3
+	h.sdk.CheckAndCaptureWithContext(ctx, "error-handler", map[string]interface{}{
+		"error_type":  errorType,
+		"retry_count": retryCount,
+		"endpoint":    "/api/error",
+	})
+
+	err := fmt.Errorf("simulated error: payment gateway timeout")
+
+	h.sdk.RecordError(span, err)
+	h.sdk.AddAttribute(span, "error.type", errorType)
+	h.sdk.AddAttribute(span, "retry.count", fmt.Sprintf("%d", retryCount))
+
+	h.sdk.AddEvent(span, "error.occurred")
+	logging.FromContext(ctx).Error("Payment gateway timeout",
+		zap.String("error.type", errorType),
+		zap.Int("retry.count", retryCount),
+		zap.Error(err),
+	)
+
+	c.JSON(500, gin.H{
+		"error":   "Internal Server Error",
+		"message": "Payment gateway timeout",
+	})
+}
+
+// SecurityTest exercises sensitive data detection in snapshots
+func (h *Handlers) SecurityTest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// NOTE: These are FAKE test values for demonstration purposes only
+	testVariables := map[string]interface{}{
+		"password":    "super_secret_password_123",
+		"api_key":     "test_key_abc123_NOT_REAL_demo_only",
+		"user_token":  "test_token_xyz789_fake_for_testing",
+		"credit_card": "4532015112830366",
+		"normal_var":  "This is just normal data",
+	}
+
+	h.sdk.CheckAndCaptureWithContext(ctx, "security-test-with-sensitive-data", testVariables)
+
+	c.JSON(200, gin.H{
+		"message": "Security test completed - check for security events",
+		"note":    "Sensitive data should be redacted in the snapshot",
+	})
+}
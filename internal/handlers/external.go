@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+)
+
+// CallExternal calls a third-party API (EXTERNAL_API_URL) through the
+// instrumented client. The API doesn't report spans, so the trace ends at
+// the CLIENT span; peer.service names it so it still shows in the service
+// graph. Failures, timeouts, and non-JSON bodies are reported, not fatal.
+func (h *Handlers) CallExternal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, h.external.Span)
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	h.sdk.AddAttribute(span, "peer.service", h.external.Name)
+	h.sdk.AddAttribute(span, "server.address", h.external.Host())
+
+	resp, err := h.client.Get(ctx, h.external, "")
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		logging.FromContext(ctx).Warn("External API call failed", zap.String("url", h.external.URL), zap.Error(err))
+		message := err.Error()
+		if isRequestError(err) {
+			message = "Failed to create request"
+		}
+		c.JSON(downstreamStatus(err), gin.H{"called": h.external.URL, "peer_service": h.external.Name, "error": message})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "response.status", int64(resp.StatusCode))
+	if resp.Body == nil {
+		h.sdk.AddEvent(span, "external.non_json_response")
+	}
+	if resp.StatusCode >= 400 {
+		h.sdk.RecordError(span, fmt.Errorf("external API answered %d", resp.StatusCode))
+		c.JSON(502, gin.H{"called": h.external.URL, "peer_service": h.external.Name, "status": resp.StatusCode, "response": resp.Body})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"called":       h.external.URL,
+		"peer_service": h.external.Name,
+		"status":       resp.StatusCode,
+		"response":     resp.Body,
+	})
+}
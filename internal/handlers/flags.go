@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/flags"
+)
+
+// Flags evaluates every feature flag for ?key= (a customer ID), so a
+// rollout can be checked before sending it traffic
+func (h *Handlers) Flags(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "evaluateFlags")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	key := c.DefaultQuery("key", defaultCustomerID)
+	h.sdk.AddAttribute(span, "feature_flag.context.id", key)
+
+	all := h.flags.Flags()
+	evaluations := make([]flags.Evaluation, 0, len(all))
+	for _, flag := range all {
+		evaluations = append(evaluations, h.flags.Variant(ctx, flag.Key, key, flag.Default))
+	}
+	h.sdk.AddIntAttribute(span, "feature_flag.count", int64(len(all)))
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"targeting_key": key,
+		"flags":         all,
+		"evaluations":   evaluations,
+	})
+}
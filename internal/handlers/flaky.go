@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/retry"
+)
+
+// maxFlakyAttempts bounds the attempts a caller can ask for
+const maxFlakyAttempts = 10
+
+// errFlaky is the failure /api/flaky reports for a losing roll
+var errFlaky = errors.New("flaky: request failed its roll")
+
+// Flaky fails error_pct percent of calls, chosen by a roll recorded on the
+// span, e.g. /api/flaky?error_pct=30. Looping over it produces mixed
+// success/failure traffic at a known ratio.
+func (h *Handlers) Flaky(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "flaky")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	errorPct, err := strconv.ParseFloat(c.DefaultQuery("error_pct", "30"), 64)
+	if err != nil || errorPct < 0 || errorPct > 100 {
+		c.JSON(400, gin.H{"error": "error_pct must be between 0 and 100"})
+		return
+	}
+
+	// A roll below error_pct fails, so error_pct=0 never fails and 100 always does
+	roll := rand.Float64() * 100
+	failed := roll < errorPct
+
+	h.sdk.AddFloatAttribute(span, "flaky.error_pct", errorPct)
+	h.sdk.AddFloatAttribute(span, "flaky.roll", roll)
+	span.SetAttributes(attribute.Bool("flaky.failed", failed))
+
+	if failed {
+		h.sdk.AddAttribute(span, "flaky.outcome", "failure")
+		h.sdk.RecordError(span, errFlaky)
+		c.JSON(500, gin.H{"error": errFlaky.Error(), "roll": roll, "error_pct": errorPct})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "flaky.outcome", "success")
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{"message": "Passed the roll", "roll": roll, "error_pct": errorPct})
+}
+
+// CallFlaky calls a downstream that fails part of the time and retries it with
+// jittered exponential backoff, one child span per attempt. The flaky
+// downstream is this app's own /api/chaos endpoint, so each attempt also
+// carries a CLIENT span and the matching SERVER span.
+func (h *Handlers) CallFlaky(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "callFlaky")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	policy := retry.Default
+	attempts, err := strconv.Atoi(c.DefaultQuery("attempts", strconv.Itoa(policy.MaxAttempts)))
+	if err != nil || attempts < 1 || attempts > maxFlakyAttempts {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("attempts must be between 1 and %d", maxFlakyAttempts)})
+		return
+	}
+	policy.MaxAttempts = attempts
+
+	failureRate, err := strconv.ParseFloat(c.DefaultQuery("failure_rate", "0.5"), 64)
+	if err != nil || failureRate < 0 || failureRate > 1 {
+		c.JSON(400, gin.H{"error": "failure_rate must be between 0 and 1"})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "retry.max_attempts", int64(policy.MaxAttempts))
+	h.sdk.AddFloatAttribute(span, "flaky.failure_rate", failureRate)
+
+	path := fmt.Sprintf("/api/chaos?latency_ms=20&jitter_ms=30&status=503&error_rate=%g", failureRate)
+
+	var resp *clients.Response
+	made, err := retry.Do(ctx, h.sdk, "callFlaky", policy, func(ctx context.Context) error {
+		r, err := h.client.Get(ctx, h.client.Services().Self, path)
+		switch {
+		case err != nil && (isRequestError(err) || errors.Is(err, clients.ErrCircuitOpen)):
+			// Retrying can't help until the request or the breaker changes
+			return retry.Permanent(err)
+		case err != nil:
+			return err
+		case r.StatusCode >= 500:
+			return fmt.Errorf("downstream returned %d", r.StatusCode)
+		}
+		resp = r
+		return nil
+	})
+
+	h.sdk.AddIntAttribute(span, "retry.attempts", int64(made))
+
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(downstreamStatus(err), gin.H{
+			"error":    err.Error(),
+			"attempts": made,
+		})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"message":  "Flaky downstream eventually succeeded",
+		"attempts": made,
+		"response": resp.Body,
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// graphqlOperation is the operation /api/call-graphql sends
+const graphqlOperation = "UserWithOrders"
+
+// graphqlQuery fetches a user and their orders. graphqlBrokenQuery asks for
+// a field the schema doesn't have, which fails validation.
+const (
+	graphqlQuery       = `query UserWithOrders($id: ID!) { user(id: $id) { name email orders { id amount status } } }`
+	graphqlBrokenQuery = `query UserWithOrders($id: ID!) { user(id: $id) { name nickname } }`
+)
+
+// graphqlRequest is the JSON body of a GraphQL-over-HTTP request
+type graphqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// CallGraphQL sends a GraphQL query to a downstream GraphQL API, played by
+// this app's own /graphql, and records the operation on its span. GraphQL
+// reports errors in the body of a 200 response, so the body is checked for
+// them too. ?user_id= picks the user; ?broken=true sends an invalid query.
+func (h *Handlers) CallGraphQL(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "callGraphQL")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	req := graphqlRequest{
+		Query:         graphqlQuery,
+		OperationName: graphqlOperation,
+		Variables:     map[string]any{"id": c.DefaultQuery("user_id", "1")},
+	}
+	if c.Query("broken") == "true" {
+		req.Query = graphqlBrokenQuery
+	}
+
+	h.sdk.AddAttribute(span, "graphql.operation.type", "query")
+	h.sdk.AddAttribute(span, "graphql.operation.name", req.OperationName)
+	h.sdk.AddIntAttribute(span, "graphql.variables.count", int64(len(req.Variables)))
+	h.sdk.AddAttribute(span, "graphql.document", req.Query)
+
+	self := h.client.Services().Self
+	resp, err := h.client.Post(ctx, self, "/graphql", req)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(downstreamStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	gqlErrors, _ := resp.Body["errors"].([]any)
+	h.sdk.AddIntAttribute(span, "graphql.errors.count", int64(len(gqlErrors)))
+	if len(gqlErrors) > 0 {
+		for _, e := range gqlErrors {
+			gqlError, _ := e.(map[string]any)
+			message, _ := gqlError["message"].(string)
+			span.AddEvent("graphql.error", trace.WithAttributes(attribute.String("graphql.error.message", message)))
+		}
+		h.sdk.RecordError(span, fmt.Errorf("graphql %s: %d errors", req.OperationName, len(gqlErrors)))
+		c.JSON(502, gin.H{
+			"called":    self.URL + "/graphql",
+			"operation": req.OperationName,
+			"errors":    gqlErrors,
+		})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"called":    self.URL + "/graphql",
+		"operation": req.OperationName,
+		"data":      resp.Body["data"],
+	})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+// CallGRPC calls the OrderService over gRPC - tests gRPC CLIENT/SERVER spans
+func (h *Handlers) CallGRPC(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "callOrderService")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	orderID := c.DefaultQuery("order_id", fmt.Sprintf("ORD-%d", time.Now().Unix()))
+
+	h.sdk.AddAttribute(span, "rpc.system", "grpc")
+	h.sdk.AddAttribute(span, "rpc.service", "OrderService")
+	h.sdk.AddAttribute(span, "order.id", orderID)
+
+	order, err := h.orders.GetOrder(ctx, &pb.GetOrderRequest{OrderId: orderID})
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{
+			"service":   "go-test-app",
+			"called":    "OrderService",
+			"error":     err.Error(),
+			"grpc_code": status.Code(err).String(),
+		})
+		return
+	}
+
+	h.sdk.AddEvent(span, "order.received")
+	body, err := h.protoJSON(ctx, order)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to serialize order", "message": err.Error()})
+		return
+	}
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"service": "go-test-app",
+		"called":  "OrderService",
+		"order":   body,
+	})
+}
+
+// maxStreamOrders bounds ?orders= on /api/grpc-stream
+const maxStreamOrders = 10
+
+// StreamOrders tracks orders over the bidirectional OrderService.TrackOrders
+// stream: it sends ?orders= order IDs (default 3) and collects every status
+// update. The stream is one span on each side, with an event per message.
+func (h *Handlers) StreamOrders(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "streamOrderStatus")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	count, err := strconv.Atoi(c.DefaultQuery("orders", "3"))
+	if err != nil || count < 1 || count > maxStreamOrders {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("orders must be between 1 and %d", maxStreamOrders)})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "rpc.system", "grpc")
+	h.sdk.AddAttribute(span, "rpc.service", "OrderService")
+	h.sdk.AddAttribute(span, "rpc.method", "TrackOrders")
+
+	stream, err := h.orders.TrackOrders(ctx)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{"error": err.Error(), "grpc_code": status.Code(err).String()})
+		return
+	}
+
+	// Send every request while the updates come back
+	sendErr := make(chan error, 1)
+	go func() {
+		defer close(sendErr)
+		for seq := 1; seq <= count; seq++ {
+			orderID := fmt.Sprintf("ORD-%d-%d", time.Now().Unix(), seq)
+			if err := stream.Send(&pb.TrackRequest{OrderId: orderID}); err != nil {
+				sendErr <- err
+				return
+			}
+			span.AddEvent("stream.message.sent", trace.WithAttributes(
+				attribute.Int("stream.seq", seq),
+				attribute.String("order.id", orderID),
+			))
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	updates := []json.RawMessage{}
+	for {
+		update, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(502, gin.H{"error": err.Error(), "grpc_code": status.Code(err).String(), "updates": updates})
+			return
+		}
+		span.AddEvent("stream.message.received", trace.WithAttributes(
+			attribute.Int("stream.seq", int(update.GetSeq())),
+			attribute.String("order.id", update.GetOrderId()),
+			attribute.String("order.status", update.GetStatus()),
+		))
+		// Protobuf JSON without a span per message, which would bury the stream
+		body, err := pb.JSONOptions.Marshal(update)
+		if err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(500, gin.H{"error": "Failed to serialize update", "message": err.Error()})
+			return
+		}
+		updates = append(updates, body)
+	}
+	if err := <-sendErr; err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{"error": err.Error(), "updates": updates})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "stream.messages.sent", int64(count))
+	h.sdk.AddIntAttribute(span, "stream.messages.received", int64(len(updates)))
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"service": "go-test-app",
+		"called":  "OrderService/TrackOrders",
+		"sent":    count,
+		"updates": updates,
+	})
+}
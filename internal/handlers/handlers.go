@@ -0,0 +1,313 @@
+// Package handlers contains the traced Gin endpoints of the test app.
+package handlers
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/batch"
+	"github.com/Tracekit-Dev/test-app/internal/cache"
+	"github.com/Tracekit-Dev/test-app/internal/cart"
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/database"
+	"github.com/Tracekit-Dev/test-app/internal/flags"
+	"github.com/Tracekit-Dev/test-app/internal/health"
+	"github.com/Tracekit-Dev/test-app/internal/inventory"
+	"github.com/Tracekit-Dev/test-app/internal/messaging"
+	"github.com/Tracekit-Dev/test-app/internal/mongodb"
+	"github.com/Tracekit-Dev/test-app/internal/notify"
+	"github.com/Tracekit-Dev/test-app/internal/ordersvc"
+	"github.com/Tracekit-Dev/test-app/internal/orm"
+	"github.com/Tracekit-Dev/test-app/internal/payments"
+	"github.com/Tracekit-Dev/test-app/internal/poll"
+	"github.com/Tracekit-Dev/test-app/internal/recent"
+	"github.com/Tracekit-Dev/test-app/internal/sampling"
+	"github.com/Tracekit-Dev/test-app/internal/userstore"
+	"github.com/Tracekit-Dev/test-app/internal/webhook"
+	"github.com/Tracekit-Dev/test-app/internal/worker"
+)
+
+// Deps are the collaborators injected into the handlers
+type Deps struct {
+	SDK      *tracekit.SDK
+	Client   *clients.Client
+	Orders   *ordersvc.Client
+	Workers  *worker.Pool
+	Batcher  *batch.Batcher
+	Mailer   *notify.Mailer
+	Notifier *notify.Dispatcher
+	GraphQL  http.Handler
+	Gateway  http.Handler
+	SOAP     http.Handler
+	Static   http.Handler
+
+	// DB is optional; /api/users-db returns 503 without it
+	DB *database.DB
+
+	// OrderStore is optional; POST /api/order skips persistence without it
+	OrderStore *database.DB
+
+	// Inventory checks stock for POST /api/order
+	Inventory *inventory.Service
+
+	// ORM is optional; /api/products returns 503 without it
+	ORM *orm.Store
+
+	// Mongo is optional; /api/documents returns 503 without it
+	Mongo *mongodb.Store
+
+	// Cache is optional; /api/users skips the cache without it
+	Cache *cache.Cache
+
+	// Producer is optional; /api/publish-order returns 503 without it
+	Producer *messaging.KafkaProducer
+
+	// NATS is optional; /api/check-stock returns 503 without it
+	NATS *messaging.NATSRPC
+
+	// Rabbit is optional; /api/enqueue returns 503 without it
+	Rabbit *messaging.RabbitMQ
+
+	// SQS is optional; /api/enqueue-sqs returns 503 without it
+	SQS *messaging.SQSQueue
+
+	// Users is the user store behind /api/users and /api/users/:id
+	Users *userstore.Store
+
+	// Carts is the session store behind /api/cart
+	Carts *cart.Store
+
+	// Payments is the payment simulator behind /api/pay
+	Payments *payments.Gateway
+
+	// Polls is the event hub behind /api/poll
+	Polls *poll.Hub
+
+	// Webhooks is optional; /api/outbound-webhooks returns 503 without it
+	Webhooks *webhook.Dispatcher
+
+	// Flags gate order validation and confirmation emails
+	Flags *flags.Provider
+
+	// External is the third-party API behind /api/call-external
+	External clients.Service
+
+	// Proxy is the upstream /proxy/*path forwards to
+	Proxy clients.Service
+
+	// WebhookSecret signs inbound webhooks; /api/webhooks returns 503 without it
+	WebhookSecret string
+
+	// CollectorAddr is the TraceKit endpoint's host:port, probed by /health/deep
+	CollectorAddr string
+
+	// Readiness backs /readyz
+	Readiness *health.Readiness
+
+	// Sampler reports the sampling settings on /api/sampling
+	Sampler *sampling.Sampler
+
+	// Recent feeds the status page's recent requests table
+	Recent *recent.Log
+
+	// ServiceName and Environment head the status page
+	ServiceName string
+	Environment string
+}
+
+// Handlers serves the test app endpoints
+type Handlers struct {
+	sdk        *tracekit.SDK
+	client     *clients.Client
+	orders     *ordersvc.Client
+	workers    *worker.Pool
+	batcher    *batch.Batcher
+	mailer     *notify.Mailer
+	dispatcher *notify.Dispatcher
+	graphql    http.Handler
+	gateway    http.Handler
+	soap       http.Handler
+	static     http.Handler
+	db         *database.DB
+	orderStore *database.DB
+	inventory  *inventory.Service
+	orm        *orm.Store
+	mongo      *mongodb.Store
+	cache      *cache.Cache
+	producer   *messaging.KafkaProducer
+	nats       *messaging.NATSRPC
+	rabbit     *messaging.RabbitMQ
+	sqs        *messaging.SQSQueue
+	webhooks   *webhook.Dispatcher
+	users      *userstore.Store
+	carts      *cart.Store
+	payments   *payments.Gateway
+	polls      *poll.Hub
+	flags      *flags.Provider
+	external   clients.Service
+	upstream   clients.Service
+	proxy      *httputil.ReverseProxy
+	metrics    *Metrics
+
+	// proxyTimeout bounds a proxied exchange, as the upstream's HTTP client
+	// timeout would, since the proxy only borrows that client's transport
+	proxyTimeout time.Duration
+
+	webhookSecret []byte
+	collectorAddr string
+	readiness     *health.Readiness
+	sampler       *sampling.Sampler
+	recent        *recent.Log
+	serviceName   string
+	environment   string
+
+	// router lists the registered routes on the status page
+	router *gin.Engine
+}
+
+// New creates the handlers and their metrics
+func New(deps Deps) *Handlers {
+	return &Handlers{
+		sdk:        deps.SDK,
+		client:     deps.Client,
+		orders:     deps.Orders,
+		workers:    deps.Workers,
+		batcher:    deps.Batcher,
+		mailer:     deps.Mailer,
+		dispatcher: deps.Notifier,
+		graphql:    deps.GraphQL,
+		gateway:    deps.Gateway,
+		soap:       deps.SOAP,
+		static:     deps.Static,
+		db:         deps.DB,
+		orderStore: deps.OrderStore,
+		inventory:  deps.Inventory,
+		orm:        deps.ORM,
+		mongo:      deps.Mongo,
+		cache:      deps.Cache,
+		producer:   deps.Producer,
+		nats:       deps.NATS,
+		rabbit:     deps.Rabbit,
+		sqs:        deps.SQS,
+		webhooks:   deps.Webhooks,
+		users:      deps.Users,
+		carts:      deps.Carts,
+		payments:   deps.Payments,
+		polls:      deps.Polls,
+		flags:      deps.Flags,
+		external:   deps.External,
+		upstream:   deps.Proxy,
+		proxy:      newProxy(deps.SDK, deps.Client, deps.Proxy),
+		metrics:    NewMetrics(deps.SDK),
+
+		proxyTimeout: deps.Client.HTTP(deps.Proxy).Timeout,
+
+		webhookSecret: []byte(deps.WebhookSecret),
+		collectorAddr: deps.CollectorAddr,
+		readiness:     deps.Readiness,
+		sampler:       deps.Sampler,
+		recent:        deps.Recent,
+		serviceName:   deps.ServiceName,
+		environment:   deps.Environment,
+	}
+}
+
+// Register mounts every endpoint on r
+func (h *Handlers) Register(r *gin.Engine) {
+	h.router = r
+
+	r.GET("/", h.Hello)
+	r.GET("/health", h.Health)
+	r.GET("/health/deep", h.DeepHealth)
+	r.GET("/livez", h.Livez)
+	r.GET("/readyz", h.Readyz)
+
+	r.GET("/api/users", h.Users)
+	r.GET("/api/users/:id", h.GetUser)
+	r.GET("/api/users/:id/profile", h.UserProfile)
+	r.PUT("/api/users/:id", h.UpdateUser)
+	r.GET("/api/users-db", h.UsersDB)
+	r.GET("/api/slow-query", h.SlowQuery)
+	r.GET("/api/products", h.ListProducts)
+	r.POST("/api/products", h.CreateProduct)
+	r.GET("/api/products/:id", h.GetProduct)
+	r.PUT("/api/products/:id", h.UpdateProduct)
+	r.GET("/api/documents", h.ListDocuments)
+	r.POST("/api/documents", h.CreateDocument)
+	r.GET("/api/documents/:id", h.GetDocument)
+	r.PUT("/api/documents/:id", h.UpdateDocument)
+	r.DELETE("/api/documents/:id", h.DeleteDocument)
+	r.POST("/api/order", h.CreateOrder)
+	r.POST("/api/orders/bulk", h.BulkCreateOrders)
+	r.GET("/api/order/:id", h.GetOrder)
+	r.GET("/api/customers/:id/orders/:order_id", h.CustomerOrder)
+	r.POST("/api/publish-order", h.PublishOrder)
+	r.GET("/api/cart", h.ViewCart)
+	r.POST("/api/cart/items", h.AddToCart)
+	r.POST("/api/cart/checkout", h.CheckoutCart)
+	r.POST("/api/pay", h.Pay)
+	r.POST("/api/notify", h.Notify)
+	r.POST("/api/webhooks/:provider", h.ReceiveWebhook)
+	r.POST("/api/outbound-webhooks", h.EmitWebhook)
+	r.GET("/api/outbound-webhooks/dead-letters", h.WebhookDeadLetters)
+	r.GET("/api/check-stock", h.CheckStock)
+	r.POST("/api/enqueue", h.Enqueue)
+	r.POST("/api/enqueue-sqs", h.EnqueueSQS)
+	r.POST("/api/jobs", h.SubmitJob)
+	r.POST("/api/batch-process", h.BatchProcess)
+	r.POST("/api/batch", h.Batch)
+	r.GET("/api/stream", h.Stream)
+	r.GET("/api/poll", h.Poll)
+	r.POST("/api/poll", h.PublishPoll)
+	r.POST("/api/upload", h.Upload)
+	r.GET("/api/download/:size", h.Download)
+	r.GET("/api/export/users.csv", h.ExportUsersCSV)
+
+	r.POST("/graphql", gin.WrapH(h.graphql))
+	r.GET("/static/*filepath", gin.WrapH(h.static))
+	r.HEAD("/static/*filepath", gin.WrapH(h.static))
+	r.GET("/api/metrics", h.MetricsInfo)
+	r.GET("/api/sampling", h.Sampling)
+	r.GET("/api/flags", h.Flags)
+
+	// One /api/call-<route> per service in the topology
+	for _, svc := range h.client.Services().All() {
+		r.GET("/api/call-"+svc.Route, h.CallService(svc.Route))
+	}
+	r.GET("/api/chain", h.Chain)
+	r.GET("/api/internal", h.Internal)
+	r.GET("/api/data", h.Data)
+	r.GET("/api/call-all", h.CallAll)
+	r.GET("/api/aggregate", h.Aggregate)
+	r.GET("/api/call-grpc", h.CallGRPC)
+	r.GET("/api/grpc-stream", h.StreamOrders)
+	r.GET("/v1/orders/:order_id", gin.WrapH(h.gateway))
+	r.GET("/api/call-flaky", h.CallFlaky)
+	r.GET("/api/cancel-demo", h.CancelDemo)
+	r.GET("/api/hedged-call", h.HedgedCall)
+	r.GET("/api/breakers", h.Breakers)
+	r.GET("/api/baggage", h.Baggage)
+	r.GET("/api/baggage/echo", h.BaggageEcho)
+	r.GET("/api/debug/propagation", h.DebugPropagation)
+	r.GET("/api/call-legacy", h.CallLegacy)
+	r.GET("/api/call-graphql", h.CallGraphQL)
+	r.GET("/api/call-soap", h.CallSOAP)
+	r.GET("/api/call-external", h.CallExternal)
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+		r.Handle(method, "/proxy/*path", h.Proxy)
+	}
+	r.POST("/soap/orders", gin.WrapH(h.soap))
+	r.GET("/api/legacy/:format", h.LegacyEcho)
+
+	r.GET("/api/error", h.Error)
+	r.GET("/api/errors/:code", h.ErrorCode)
+	r.GET("/api/chaos", h.Chaos)
+	r.GET("/api/flaky", h.Flaky)
+	r.GET("/api/latency", h.Latency)
+	r.GET("/api/contract/:scenario", h.Contract)
+	r.GET("/security-test", h.SecurityTest)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/health"
+)
+
+// deepHealthTimeout bounds each dependency probe
+const deepHealthTimeout = 2 * time.Second
+
+// DeepHealth concurrently probes every downstream service and the TraceKit
+// endpoint, reporting 503 if any of them is down
+func (h *Handlers) DeepHealth(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "deepHealthCheck")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	// Probes bypass the circuit breakers so they report what's really there
+	var probes []health.Probe
+	for _, svc := range h.client.Services().All() {
+		probes = append(probes, health.HTTPProbe(svc.Name, svc.URL+"/api/data", h.client.HTTP(svc)))
+	}
+	probes = append(probes, health.TCPProbe("tracekit", h.collectorAddr))
+
+	results := health.Run(ctx, h.sdk, probes, deepHealthTimeout)
+
+	status, code := "healthy", 200
+	down := 0
+	for _, result := range results {
+		if result.Status == health.StatusDown {
+			down++
+		}
+	}
+	if down > 0 {
+		status, code = "degraded", 503
+	}
+
+	h.sdk.AddIntAttribute(span, "health.dependencies", int64(len(results)))
+	h.sdk.AddIntAttribute(span, "health.down", int64(down))
+	h.sdk.AddAttribute(span, "health.status", status)
+	h.sdk.SetSuccess(span)
+
+	c.JSON(code, gin.H{
+		"status":       status,
+		"service":      "go-test-app",
+		"time":         time.Now().Format(time.RFC3339),
+		"dependencies": results,
+	})
+}
+
+// Livez reports that the process is up. It never checks dependencies, so a
+// slow downstream can't get the pod restarted.
+func (h *Handlers) Livez(c *gin.Context) {
+	c.JSON(200, gin.H{"status": "alive"})
+}
+
+// Readyz reports whether the app should receive traffic: the SDK and config
+// are initialized, the TraceKit exporter is reachable, and it isn't shutting
+// down
+func (h *Handlers) Readyz(c *gin.Context) {
+	ready, checks := h.readiness.Check(c.Request.Context())
+	if !ready {
+		c.JSON(503, gin.H{"status": "not ready", "checks": checks})
+		return
+	}
+	c.JSON(200, gin.H{"status": "ready", "checks": checks})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/hedge"
+)
+
+// maxHedgeDelay stays under the self client's timeout so the hedge is
+// always sent before the primary gives up
+const maxHedgeDelay = 5 * time.Second
+
+// hedgedPath is a downstream with a long tail: a median of 50ms, but one
+// call in a hundred takes a second
+const hedgedPath = "/api/latency?dist=pareto&p50=50&p99=1000"
+
+// HedgedCall calls a downstream with a long latency tail (this app's
+// /api/latency) and, if it hasn't answered after delay_ms (default 100),
+// sends the same request again. Whichever answers first wins and the other
+// is cancelled, e.g. /api/hedged-call?delay_ms=100. Both attempts are child
+// spans of this one, which names the winner.
+func (h *Handlers) HedgedCall(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "hedgedCall")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	delayMS, err := strconv.Atoi(c.DefaultQuery("delay_ms", "100"))
+	if err != nil || delayMS < 0 || time.Duration(delayMS)*time.Millisecond > maxHedgeDelay {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("delay_ms must be between 0 and %d", maxHedgeDelay.Milliseconds())})
+		return
+	}
+	delay := time.Duration(delayMS) * time.Millisecond
+	h.sdk.AddIntAttribute(span, "hedge.delay_ms", int64(delayMS))
+
+	start := time.Now()
+	var responses [len(hedge.Attempts)]*clients.Response
+	made, winner, err := hedge.Do(ctx, h.sdk, "hedgedCall", delay, func(ctx context.Context, attempt int) error {
+		resp, err := h.client.Get(ctx, h.client.Services().Self, hedgedPath)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("downstream returned %d", resp.StatusCode)
+		}
+		responses[attempt] = resp
+		return nil
+	})
+	elapsed := time.Since(start)
+
+	h.sdk.AddIntAttribute(span, "hedge.attempts", int64(made))
+	h.sdk.AddIntAttribute(span, "hedge.elapsed_ms", elapsed.Milliseconds())
+
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(downstreamStatus(err), gin.H{"error": err.Error(), "attempts": made})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "hedge.winner", hedge.Attempts[winner])
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"winner":     hedge.Attempts[winner],
+		"attempts":   made,
+		"delay_ms":   delayMS,
+		"elapsed_ms": elapsed.Milliseconds(),
+		"response":   responses[winner].Body,
+	})
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/worker"
+)
+
+// SubmitJob hands work to the background worker pool. The job runs in its
+// own trace linked back to this request's span.
+func (h *Handlers) SubmitJob(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "submitJob")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	jobType := c.DefaultQuery("type", "report")
+	h.sdk.AddAttribute(span, "job.type", jobType)
+
+	job, err := h.workers.Submit(ctx, jobType)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		status := 400
+		if errors.Is(err, worker.ErrQueueFull) {
+			status = 503
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "job.id", job.ID)
+	h.sdk.AddEvent(span, "job.enqueued")
+	h.sdk.SetSuccess(span)
+
+	c.JSON(202, gin.H{
+		"job_id": job.ID,
+		"type":   job.Type,
+		"status": "queued",
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/chaos"
+)
+
+// Latency sleeps for a delay drawn from a distribution fitted to the given
+// median and 99th percentile, e.g. /api/latency?dist=pareto&p50=40&p99=900.
+// Looping over it fills percentile views with a known shape.
+func (h *Handlers) Latency(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "simulateLatency")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	p50, err1 := strconv.Atoi(c.DefaultQuery("p50", "40"))
+	p99, err2 := strconv.Atoi(c.DefaultQuery("p99", "900"))
+	dist := chaos.Latency{
+		Dist: c.DefaultQuery("dist", "lognormal"),
+		P50:  time.Duration(p50) * time.Millisecond,
+		P99:  time.Duration(p99) * time.Millisecond,
+	}
+	if err1 != nil || err2 != nil {
+		c.JSON(400, gin.H{"error": "p50 and p99 must be integers in milliseconds"})
+		return
+	}
+	if err := dist.Validate(); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	delay := dist.Sample()
+	h.sdk.AddAttribute(span, "latency.dist", dist.Dist)
+	h.sdk.AddIntAttribute(span, "latency.p50_ms", int64(p50))
+	h.sdk.AddIntAttribute(span, "latency.p99_ms", int64(p99))
+	h.sdk.AddFloatAttribute(span, "latency.sampled_ms", float64(delay.Microseconds())/1000)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		// The client went away mid-sleep; nobody is left to answer
+		h.sdk.RecordError(span, ctx.Err())
+		c.Abort()
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"dist":       dist.Dist,
+		"p50_ms":     p50,
+		"p99_ms":     p99,
+		"sampled_ms": float64(delay.Microseconds()) / 1000,
+	})
+}
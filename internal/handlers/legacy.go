@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// legacyFormats are the header formats /api/legacy/:format understands, by
+// their OTEL_PROPAGATORS names
+var legacyFormats = []string{"b3", "jaeger"}
+
+// LegacyEcho plays a downstream service that only understands one header
+// format: it extracts the caller's span from that format alone and reports
+// what it found, ignoring traceparent.
+func (h *Handlers) LegacyEcho(c *gin.Context) {
+	format := c.Param("format")
+	propagator, ok := legacyPropagator(format)
+	if !ok {
+		c.JSON(404, gin.H{"error": "Unknown legacy format " + format})
+		return
+	}
+
+	received := make(map[string]string)
+	for _, field := range propagator.Fields() {
+		if value := c.GetHeader(field); value != "" {
+			received[http.CanonicalHeaderKey(field)] = value
+		}
+	}
+	parent := trace.SpanContextFromContext(propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header)))
+
+	c.JSON(200, gin.H{
+		"format":   format,
+		"received": received,
+		"parent":   spanContextJSON(parent),
+	})
+}
+
+// CallLegacy calls a downstream that only speaks the given header format,
+// e.g. /api/call-legacy?format=jaeger, and reports whether it joined this trace.
+// That only happens when the format is enabled in OTEL_PROPAGATORS, since
+// the instrumented client injects every configured format.
+func (h *Handlers) CallLegacy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "callLegacy")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	format := c.DefaultQuery("format", "b3")
+	if _, ok := legacyPropagator(format); !ok {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("Unknown legacy format %q", format)})
+		return
+	}
+	h.sdk.AddAttribute(span, "legacy.format", format)
+
+	resp, err := h.client.Get(ctx, h.client.Services().Self, "/api/legacy/"+format)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(downstreamStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	traceID := span.SpanContext().TraceID().String()
+	parent, _ := resp.Body["parent"].(map[string]interface{})
+	stitched := parent != nil && parent["trace_id"] == traceID
+	span.SetAttributes(attribute.Bool("legacy.stitched", stitched))
+
+	result := gin.H{
+		"format":     format,
+		"trace_id":   traceID,
+		"stitched":   stitched,
+		"downstream": resp.Body,
+	}
+	if !stitched {
+		h.sdk.AddEvent(span, "legacy.trace_broken")
+		result["hint"] = fmt.Sprintf("Add %s to OTEL_PROPAGATORS so outbound calls carry its headers", format)
+	}
+	h.sdk.SetSuccess(span)
+	c.JSON(200, result)
+}
+
+// legacyPropagator returns the propagator of a legacy format
+func legacyPropagator(format string) (propagation.TextMapPropagator, bool) {
+	if !slices.Contains(legacyFormats, format) {
+		return nil, false
+	}
+	return tracing.Propagator(format)
+}
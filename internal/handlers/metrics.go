@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics are the custom metrics recorded by the handlers
+type Metrics struct {
+	RequestCounter       tracekit.Counter
+	ActiveRequestsGauge  tracekit.Gauge
+	RequestDurationHisto tracekit.Histogram
+	OrderCounter         tracekit.Counter
+	OrderAmountHisto     tracekit.Histogram
+}
+
+// NewMetrics registers the metrics with the SDK
+func NewMetrics(sdk *tracekit.SDK) *Metrics {
+	return &Metrics{
+		RequestCounter:       sdk.Counter("http.requests.total", map[string]string{"service": "go-test-app"}),
+		ActiveRequestsGauge:  sdk.Gauge("http.requests.active", nil),
+		RequestDurationHisto: sdk.Histogram("http.request.duration", map[string]string{"unit": "ms"}),
+		OrderCounter:         sdk.Counter("orders.total", nil),
+		OrderAmountHisto:     sdk.Histogram("order.amount", map[string]string{"currency": "usd"}),
+	}
+}
+
+// trackRequest counts the request and marks it active. The returned func
+// records the duration and must be deferred by the caller.
+func (m *Metrics) trackRequest() func() {
+	start := time.Now()
+	m.ActiveRequestsGauge.Inc()
+	m.RequestCounter.Inc()
+
+	return func() {
+		m.ActiveRequestsGauge.Dec()
+		duration := float64(time.Since(start).Milliseconds())
+		m.RequestDurationHisto.Record(duration)
+	}
+}
+
+// MetricsInfo describes the metrics being collected
+func (h *Handlers) MetricsInfo(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"message": "Metrics are being collected and sent to TraceKit",
+		"metrics": map[string]string{
+			"http.requests.total":   "Counter - Total HTTP requests",
+			"http.requests.active":  "Gauge - Currently active requests",
+			"http.request.duration": "Histogram - Request duration in ms",
+			"orders.total":          "Counter - Total orders created",
+			"order.amount":          "Histogram - Order amounts in USD",
+		},
+		"note": "Metrics are flushed every 10 seconds or when 100 metrics are collected",
+	})
+}
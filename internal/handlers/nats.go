@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/messaging"
+)
+
+// stockRequestTimeout bounds how long /api/check-stock waits for a reply
+const stockRequestTimeout = 2 * time.Second
+
+// CheckStock asks the stock responder over NATS request/reply, e.g.
+// /api/check-stock?sku=SKU-1&quantity=3. The responder's span continues
+// this trace via the message headers.
+func (h *Handlers) CheckStock(c *gin.Context) {
+	if h.nats == nil {
+		c.JSON(503, gin.H{
+			"error":   "NATS not configured",
+			"message": "Set NATS_URL to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "checkStock")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	quantity, err := strconv.Atoi(c.DefaultQuery("quantity", "1"))
+	if err != nil || quantity < 1 {
+		c.JSON(400, gin.H{"error": "quantity must be a positive integer"})
+		return
+	}
+	req := messaging.StockRequest{SKU: c.DefaultQuery("sku", "SKU-1"), Quantity: quantity}
+	h.sdk.AddAttribute(span, "inventory.sku", req.SKU)
+
+	ctx, cancel := context.WithTimeout(ctx, stockRequestTimeout)
+	defer cancel()
+
+	reply, err := h.nats.Request(ctx, req)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{"error": "Stock request failed", "message": err.Error()})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, reply)
+}
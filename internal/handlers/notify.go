@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/notify"
+)
+
+// Notify fans a notification out to SMS, push, and email at once. One
+// channel fails at random unless ?fail names a channel (or "none"), so the
+// trace shows a parent with mixed-success children.
+func (h *Handlers) Notify(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "notify")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	fail := c.Query("fail")
+	if _, ok := notify.Channels[fail]; !ok && fail != "" && fail != "none" {
+		c.JSON(400, gin.H{"error": "Unknown channel", "message": "fail must be sms, push, email, or none"})
+		return
+	}
+
+	userID := c.DefaultQuery("user_id", "user-123")
+	sum := h.dispatcher.Send(ctx, userID, "Your order has shipped", fail)
+
+	h.sdk.AddAttribute(span, "notify.status", sum.Status)
+
+	status := 200
+	switch sum.Status {
+	case notify.StatusPartial:
+		status = 207
+	case notify.StatusFailed:
+		status = 502
+	}
+	h.sdk.SetSuccess(span)
+	c.JSON(status, sum)
+}
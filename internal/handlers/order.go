@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/database"
+	"github.com/Tracekit-Dev/test-app/internal/inventory"
+	"github.com/Tracekit-Dev/test-app/internal/notify"
+)
+
+// defaultCustomerID places orders that don't name a customer
+const defaultCustomerID = "cust-123"
+
+// Limits checked by the v2 order validation
+const (
+	maxOrderQuantity = 10
+	maxOrderAmount   = 10000
+)
+
+// CreateOrder demonstrates business attributes and metrics. With ?saga=true
+// it runs the order saga across the Node and Python services instead.
+// ?customer_id= picks the customer, which decides the feature flag rollouts,
+// and ?amount= the order amount, which is random without it.
+func (h *Handlers) CreateOrder(c *gin.Context) {
+	defer h.metrics.trackRequest()()
+
+	if c.Query("saga") == "true" {
+		h.createOrderSaga(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "createOrder")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	orderID := fmt.Sprintf("ORD-%d", time.Now().UnixNano())
+	amount := rand.Float64() * 1000
+	if raw, ok := c.GetQuery("amount"); ok {
+		var err error
+		if amount, err = strconv.ParseFloat(raw, 64); err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+			c.JSON(400, gin.H{"error": "amount must be a number"})
+			return
+		}
+	}
+	customerID := c.DefaultQuery("customer_id", defaultCustomerID)
+
+	// Track order metrics
+	h.metrics.OrderCounter.Inc()
+	h.metrics.OrderAmountHisto.Record(amount)
+
+	h.sdk.AddBusinessAttributes(span, map[string]interface{}{
+		"order.id":     orderID,
+		"order.amount": amount,
+		"customer.id":  customerID,
+	})
+
+	h.sdk.AddEvent(span, "order.created")
+
+	sku := fmt.Sprintf("SKU-%d", 1+rand.Intn(len(inventory.Seed)))
+	quantity := 1 + rand.Intn(3)
+	stock, err := h.inventory.Check(ctx, sku, quantity)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Inventory check failed", "message": err.Error()})
+		return
+	}
+	if !stock.InStock {
+		h.sdk.AddEvent(span, "order.out_of_stock")
+		c.JSON(409, gin.H{"error": "Out of stock", "order_id": orderID, "inventory": stock})
+		return
+	}
+
+	// The v2 validation is rolled out per customer
+	validation := "legacy"
+	if h.flags.Enabled(ctx, "new-order-validation", customerID) {
+		validation = "v2"
+		if err := h.validateOrder(ctx, sku, quantity, amount); err != nil {
+			h.sdk.AddAttribute(span, "order.validation", validation)
+			h.sdk.AddEvent(span, "order.rejected")
+			c.JSON(422, gin.H{"error": "Order rejected", "message": err.Error(), "order_id": orderID})
+			return
+		}
+	} else {
+		time.Sleep(100 * time.Millisecond)
+	}
+	h.sdk.AddAttribute(span, "order.validation", validation)
+	h.sdk.AddEvent(span, "order.validated")
+	time.Sleep(50 * time.Millisecond)
+	h.sdk.AddEvent(span, "order.processed")
+
+	// Persist the order and read it back when the SQLite store is enabled
+	persisted := false
+	if h.orderStore != nil {
+		order := database.Order{
+			ID:         orderID,
+			CustomerID: customerID,
+			Amount:     amount,
+			Status:     "created",
+			CreatedAt:  time.Now(),
+		}
+		if err := h.orderStore.InsertOrder(ctx, order); err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(500, gin.H{"error": "Failed to store order", "message": err.Error()})
+			return
+		}
+		if _, err := h.orderStore.GetOrder(ctx, orderID); err != nil {
+			h.sdk.RecordError(span, err)
+			c.JSON(500, gin.H{"error": "Failed to read back order", "message": err.Error()})
+			return
+		}
+		h.sdk.AddEvent(span, "order.persisted")
+		persisted = true
+	}
+
+	// The confirmation email is sent later, in its own trace
+	emailQueued := false
+	if h.flags.Enabled(ctx, "order-confirmation-email", customerID) {
+		err = h.mailer.Enqueue(ctx, notify.Email{
+			To:       "customer@example.com",
+			Template: "order_confirmation",
+			OrderID:  orderID,
+		})
+		if err != nil {
+			h.sdk.AddEvent(span, "email.queue_full")
+		} else {
+			emailQueued = true
+		}
+	}
+
+	h.sdk.SetSuccess(span)
+
+	c.JSON(201, gin.H{
+		"order_id":     orderID,
+		"amount":       amount,
+		"status":       "created",
+		"customer_id":  customerID,
+		"validation":   validation,
+		"persisted":    persisted,
+		"inventory":    stock,
+		"email_queued": emailQueued,
+	})
+}
+
+// validateOrder is the v2 validation behind the new-order-validation flag:
+// explicit checks, each recorded as an order.check event, instead of the
+// legacy path's fixed delay
+func (h *Handlers) validateOrder(ctx context.Context, sku string, quantity int, amount float64) error {
+	_, span := h.sdk.StartSpan(ctx, "order.validate")
+	defer span.End()
+
+	checks := []struct {
+		name   string
+		passed bool
+	}{
+		{"sku", strings.HasPrefix(sku, "SKU-")},
+		{"quantity", quantity >= 1 && quantity <= maxOrderQuantity},
+		{"amount", amount >= 0 && amount <= maxOrderAmount},
+	}
+	time.Sleep(30 * time.Millisecond)
+	for _, check := range checks {
+		span.AddEvent("order.check", trace.WithAttributes(
+			attribute.String("order.check.name", check.name),
+			attribute.Bool("order.check.passed", check.passed),
+		))
+		if !check.passed {
+			err := fmt.Errorf("order failed the %s check", check.name)
+			h.sdk.RecordError(span, err)
+			return err
+		}
+	}
+	h.sdk.AddIntAttribute(span, "order.validate.checks", int64(len(checks)))
+	h.sdk.SetSuccess(span)
+	return nil
+}
+
+// GetOrder reads an order persisted by CreateOrder from the SQLite store
+func (h *Handlers) GetOrder(c *gin.Context) {
+	if h.orderStore == nil {
+		c.JSON(503, gin.H{
+			"error":   "Order store not configured",
+			"message": "Set SQLITE_PATH to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "getOrder")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id := c.Param("id")
+	h.sdk.AddAttribute(span, "order.id", id)
+
+	order, err := h.orderStore.GetOrder(ctx, id)
+	if errors.Is(err, database.ErrOrderNotFound) {
+		h.sdk.AddEvent(span, "order.not_found")
+		c.JSON(404, gin.H{"error": "Order not found"})
+		return
+	}
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to read order", "message": err.Error()})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, order)
+}
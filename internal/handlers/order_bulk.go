@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/database"
+	"github.com/Tracekit-Dev/test-app/internal/inventory"
+)
+
+const (
+	// maxBulkOrders bounds POST /api/orders/bulk
+	maxBulkOrders = 1000
+
+	// maxBulkBytes bounds the body, which is read whole before the orders
+	// are counted
+	maxBulkBytes = 1 << 20
+
+	// bulkConcurrency is how many orders of a bulk request are placed at once
+	bulkConcurrency = 8
+)
+
+// bulkOrder is one order of a POST /api/orders/bulk body
+type bulkOrder struct {
+	CustomerID string  `json:"customer_id"`
+	SKU        string  `json:"sku"`
+	Quantity   int     `json:"quantity"`
+	Amount     float64 `json:"amount"`
+}
+
+// bulkResult is the outcome of one order, at the order's index in the request
+type bulkResult struct {
+	Index   int    `json:"index"`
+	Status  int    `json:"status"`
+	OrderID string `json:"order_id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkCreateOrders places up to maxBulkOrders orders from {"orders": [...]},
+// each in its own child span. One order failing doesn't fail the others: the
+// response lists a status per order and is 201 when all were created, 207
+// Multi-Status otherwise. The parent span counts successes and failures.
+func (h *Handlers) BulkCreateOrders(c *gin.Context) {
+	defer h.metrics.trackRequest()()
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "bulkCreateOrders")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBulkBytes)
+	var req struct {
+		Orders []bulkOrder `json:"orders"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sdk.RecordError(span, err)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(413, gin.H{"error": "Body too large", "max_bytes": maxBulkBytes})
+			return
+		}
+		c.JSON(400, gin.H{"error": "invalid JSON body: " + err.Error()})
+		return
+	}
+	if len(req.Orders) == 0 || len(req.Orders) > maxBulkOrders {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("orders must hold between 1 and %d orders", maxBulkOrders)})
+		return
+	}
+	h.sdk.AddIntAttribute(span, "bulk.size", int64(len(req.Orders)))
+	h.sdk.AddIntAttribute(span, "bulk.concurrency", bulkConcurrency)
+
+	start := time.Now()
+	batchID := fmt.Sprintf("ORD-%d", start.UnixNano())
+	h.sdk.AddAttribute(span, "bulk.id", batchID)
+	results := make([]bulkResult, len(req.Orders))
+	sem := make(chan struct{}, bulkConcurrency)
+	var wg sync.WaitGroup
+	for i, order := range req.Orders {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = h.placeBulkOrder(ctx, batchID, i, order)
+		}()
+	}
+	wg.Wait()
+
+	var succeeded, failed int
+	for _, r := range results {
+		if r.Status == 201 {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	h.sdk.AddIntAttribute(span, "bulk.succeeded", int64(succeeded))
+	h.sdk.AddIntAttribute(span, "bulk.failed", int64(failed))
+	h.sdk.AddIntAttribute(span, "bulk.duration_ms", time.Since(start).Milliseconds())
+	if failed > 0 {
+		h.sdk.AddEvent(span, "bulk.partial_failure")
+	}
+	h.sdk.SetSuccess(span)
+
+	status := 201
+	if failed > 0 {
+		status = 207
+	}
+	c.JSON(status, gin.H{
+		"total":     len(results),
+		"succeeded": succeeded,
+		"failed":    failed,
+		"results":   results,
+	})
+}
+
+// placeBulkOrder validates, stock-checks, and stores one order of a bulk
+// request in a child span, answering with the status it would have had alone.
+// Its ID is the request's batchID with the order's position appended.
+func (h *Handlers) placeBulkOrder(ctx context.Context, batchID string, index int, order bulkOrder) bulkResult {
+	ctx, span := h.sdk.StartSpan(ctx, "bulkCreateOrders.item")
+	defer span.End()
+
+	h.sdk.AddIntAttribute(span, "bulk.item.index", int64(index))
+	h.sdk.AddAttribute(span, "order.sku", order.SKU)
+	h.sdk.AddIntAttribute(span, "order.quantity", int64(order.Quantity))
+
+	fail := func(status int, err error) bulkResult {
+		h.sdk.AddIntAttribute(span, "bulk.item.status", int64(status))
+		h.sdk.RecordError(span, err)
+		return bulkResult{Index: index, Status: status, Error: err.Error()}
+	}
+
+	if order.SKU == "" {
+		return fail(422, errors.New("sku is required"))
+	}
+	if order.Quantity < 1 || order.Quantity > maxOrderQuantity {
+		return fail(422, fmt.Errorf("quantity must be between 1 and %d", maxOrderQuantity))
+	}
+	if order.Amount < 0 || order.Amount > maxOrderAmount {
+		return fail(422, fmt.Errorf("amount must be between 0 and %d", maxOrderAmount))
+	}
+
+	stock, err := h.inventory.Check(ctx, order.SKU, order.Quantity)
+	if errors.Is(err, inventory.ErrUnknownSKU) {
+		return fail(422, err)
+	}
+	if err != nil {
+		return fail(500, err)
+	}
+	if !stock.InStock {
+		return fail(409, fmt.Errorf("only %d of %s in stock", stock.Available, order.SKU))
+	}
+
+	customerID := order.CustomerID
+	if customerID == "" {
+		customerID = defaultCustomerID
+	}
+	orderID := fmt.Sprintf("%s-%d", batchID, index+1)
+	h.sdk.AddBusinessAttributes(span, map[string]interface{}{
+		"order.id":     orderID,
+		"order.amount": order.Amount,
+		"customer.id":  customerID,
+	})
+
+	if h.orderStore != nil {
+		err := h.orderStore.InsertOrder(ctx, database.Order{
+			ID:         orderID,
+			CustomerID: customerID,
+			Amount:     order.Amount,
+			Status:     "created",
+			CreatedAt:  time.Now(),
+		})
+		if err != nil {
+			return fail(500, err)
+		}
+	}
+
+	h.metrics.OrderCounter.Inc()
+	h.metrics.OrderAmountHisto.Record(order.Amount)
+	h.sdk.AddIntAttribute(span, "bulk.item.status", 201)
+	h.sdk.SetSuccess(span)
+	return bulkResult{Index: index, Status: 201, OrderID: orderID}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/database"
+	"github.com/Tracekit-Dev/test-app/internal/saga"
+)
+
+// createOrderSaga backs POST /api/order?saga=true. It reserves inventory on
+// the Node service, charges payment on the Python service, and confirms the
+// order locally; ?fail=reserve|payment|confirm makes that step fail so the
+// compensations (release, refund) show up in the trace.
+func (h *Handlers) createOrderSaga(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "createOrderSaga")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	services := h.client.Services()
+	node, hasNode := services.Lookup("node")
+	python, hasPython := services.Lookup("python")
+	if !hasNode || !hasPython {
+		c.JSON(503, gin.H{"error": "The order saga needs node and python services in the service topology"})
+		return
+	}
+
+	orderID := fmt.Sprintf("ORD-%d", time.Now().UnixNano())
+	amount := rand.Float64() * 1000
+	fail := c.Query("fail")
+
+	h.metrics.OrderCounter.Inc()
+	h.metrics.OrderAmountHisto.Record(amount)
+
+	h.sdk.AddBusinessAttributes(span, map[string]interface{}{
+		"order.id":     orderID,
+		"order.amount": amount,
+		"customer.id":  "cust-123",
+	})
+	if fail != "" {
+		h.sdk.AddAttribute(span, "saga.inject_failure", fail)
+	}
+
+	body := func(step string) gin.H {
+		return gin.H{"order_id": orderID, "amount": amount, "fail": fail == step}
+	}
+
+	persisted := false
+	steps := []saga.Step{
+		{
+			Name:       "reserve_inventory",
+			Action:     h.sagaCall(node, "/api/inventory/reserve", body("reserve")),
+			Compensate: h.sagaCall(node, "/api/inventory/release", body("")),
+		},
+		{
+			Name:       "charge_payment",
+			Action:     h.sagaCall(python, "/api/payments/charge", body("payment")),
+			Compensate: h.sagaCall(python, "/api/payments/refund", body("")),
+		},
+		{
+			Name: "confirm_order",
+			Action: func(ctx context.Context) error {
+				if fail == "confirm" {
+					return errors.New("order confirmation rejected")
+				}
+				if h.orderStore == nil {
+					return nil
+				}
+				err := h.orderStore.InsertOrder(ctx, database.Order{
+					ID:         orderID,
+					CustomerID: "cust-123",
+					Amount:     amount,
+					Status:     "confirmed",
+					CreatedAt:  time.Now(),
+				})
+				persisted = err == nil
+				return err
+			},
+		},
+	}
+
+	err := saga.Run(ctx, h.sdk, "create_order", steps)
+
+	var sagaErr *saga.Error
+	if errors.As(err, &sagaErr) {
+		h.sdk.RecordError(span, err)
+		c.JSON(409, gin.H{
+			"order_id":    orderID,
+			"status":      sagaErr.Status,
+			"failed_step": sagaErr.Step,
+			"error":       sagaErr.Err.Error(),
+		})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(201, gin.H{
+		"order_id":  orderID,
+		"amount":    amount,
+		"status":    "confirmed",
+		"persisted": persisted,
+		"saga":      saga.StatusCompleted,
+	})
+}
+
+// sagaCall returns a saga action that POSTs body to path on svc and fails
+// on any non-2xx response
+func (h *Handlers) sagaCall(svc clients.Service, path string, body gin.H) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		resp, err := h.client.Post(ctx, svc, path, body)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("%s %s returned %d", svc.Name, path, resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Tracekit-Dev/test-app/internal/payments"
+)
+
+// Pay charges {"amount", "currency", "card"} through the payment simulator.
+// Retries that repeat the Idempotency-Key header get the original payment
+// back instead of a second charge.
+func (h *Handlers) Pay(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "pay")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	var charge payments.Charge
+	if err := c.ShouldBindJSON(&charge); err != nil || charge.Amount <= 0 {
+		c.JSON(400, gin.H{"error": "Invalid charge", "message": `Send {"amount": 42.5, "currency": "usd"}`})
+		return
+	}
+	if charge.Currency == "" {
+		charge.Currency = "usd"
+	}
+
+	key := c.GetHeader("Idempotency-Key")
+	if key != "" {
+		h.sdk.AddAttribute(span, "idempotency.key", key)
+	}
+
+	payment, replay, err := h.payments.Charge(ctx, key, charge)
+	span.SetAttributes(attribute.Bool("idempotent.replay", replay))
+	if replay {
+		c.Header("Idempotent-Replayed", "true")
+	}
+
+	switch {
+	case errors.Is(err, payments.ErrKeyReused):
+		h.sdk.RecordError(span, err)
+		c.JSON(422, gin.H{"error": err.Error(), "idempotency_key": key})
+		return
+	case errors.Is(err, payments.ErrDeclined):
+		h.sdk.AddEvent(span, "payment.declined")
+		c.JSON(402, gin.H{"error": err.Error(), "idempotent_replay": replay})
+		return
+	case err != nil:
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Payment failed", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "payment.id", payment.ID)
+	h.sdk.SetSuccess(span)
+
+	status := 201
+	if replay {
+		status = 200
+	}
+	c.JSON(status, gin.H{"payment": payment, "idempotent_replay": replay})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/poll"
+)
+
+const (
+	// maxPollWait caps how long /api/poll holds a connection open
+	maxPollWait = 30 * time.Second
+
+	// defaultPollTopic is the topic of polls and publishes that don't name one
+	defaultPollTopic = "events"
+
+	// maxPollEventBytes bounds a published event; the hub keeps every one
+	maxPollEventBytes = 64 << 10
+)
+
+// Poll is a long poll: it answers at once with the events of ?topic= after
+// ?since=, or holds the connection open for up to ?timeout_ms= (default and
+// at most 30s) until one is published. Without ?since= only new events count.
+// The wait is its own poll.wait span, bracketed by events, so a long poll's
+// duration reads as idle waiting rather than a slow handler.
+func (h *Handlers) Poll(c *gin.Context) {
+	// The server span carries the marker too, for latency views that only
+	// see server spans
+	serverSpan := trace.SpanFromContext(c.Request.Context())
+	serverSpan.SetAttributes(attribute.Bool("http.long_poll", true))
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "longPoll")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	topic := c.DefaultQuery("topic", defaultPollTopic)
+	timeoutMS, err := strconv.Atoi(c.DefaultQuery("timeout_ms", strconv.Itoa(int(maxPollWait.Milliseconds()))))
+	if err != nil || timeoutMS < 1 || timeoutMS > int(maxPollWait.Milliseconds()) {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("timeout_ms must be between 1 and %d", maxPollWait.Milliseconds())})
+		return
+	}
+	since := h.polls.Cursor(topic)
+	if raw, ok := c.GetQuery("since"); ok {
+		if since, err = strconv.ParseInt(raw, 10, 64); err != nil || since < 0 {
+			c.JSON(400, gin.H{"error": "since must be a non-negative integer"})
+			return
+		}
+	}
+	h.sdk.AddAttribute(span, "poll.topic", topic)
+	h.sdk.AddIntAttribute(span, "poll.since", since)
+	h.sdk.AddIntAttribute(span, "poll.timeout_ms", int64(timeoutMS))
+
+	start := time.Now()
+	span.AddEvent("poll.wait_start", trace.WithAttributes(attribute.Int64("poll.since", since)))
+	events, cursor, err := h.waitForEvents(ctx, topic, since, time.Duration(timeoutMS)*time.Millisecond)
+	waited := time.Since(start)
+
+	outcome := "event"
+	switch {
+	case err == nil && events[0].PublishedAt.Before(start):
+		// Already there when the poll came in
+		outcome = "ready"
+	case errors.Is(err, context.DeadlineExceeded):
+		outcome = "timeout"
+	case errors.Is(err, poll.ErrClosed):
+		outcome = "shutdown"
+	case err != nil:
+		outcome = "client_gone"
+	}
+	h.sdk.AddAttribute(span, "poll.outcome", outcome)
+	h.sdk.AddIntAttribute(span, "poll.wait_ms", waited.Milliseconds())
+	h.sdk.AddIntAttribute(span, "poll.events", int64(len(events)))
+	h.sdk.AddIntAttribute(span, "poll.cursor", cursor)
+	serverSpan.SetAttributes(attribute.Int64("poll.wait_ms", waited.Milliseconds()))
+
+	switch outcome {
+	case "event", "ready":
+		span.AddEvent("poll.event_arrived", trace.WithAttributes(
+			attribute.Int("poll.events", len(events)),
+			attribute.Int64("poll.wait_ms", waited.Milliseconds()),
+		))
+	case "timeout":
+		span.AddEvent("poll.timeout", trace.WithAttributes(attribute.Int64("poll.wait_ms", waited.Milliseconds())))
+	case "shutdown":
+		h.sdk.AddEvent(span, "poll.shutdown")
+		c.JSON(503, gin.H{"error": "Server shutting down", "topic": topic, "cursor": cursor})
+		return
+	default:
+		// Nobody is left to answer
+		h.sdk.AddEvent(span, "poll.client_gone")
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	if events == nil {
+		events = []poll.Event{}
+	}
+	c.JSON(200, gin.H{
+		"topic":     topic,
+		"events":    events,
+		"cursor":    cursor,
+		"timed_out": outcome == "timeout",
+	})
+}
+
+// waitForEvents waits for events after since in a poll.wait span covering
+// only the idle time. However the wait ends, it isn't a failure.
+func (h *Handlers) waitForEvents(ctx context.Context, topic string, since int64, timeout time.Duration) ([]poll.Event, int64, error) {
+	ctx, span := h.sdk.StartSpan(ctx, "poll.wait")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	events, cursor, err := h.polls.Wait(ctx, topic, since)
+	span.SetAttributes(attribute.Bool("poll.timed_out", errors.Is(err, context.DeadlineExceeded)))
+	h.sdk.SetSuccess(span)
+	return events, cursor, err
+}
+
+// PublishPoll publishes the JSON body, if any, as an event of ?topic=,
+// answering the long polls waiting on it
+func (h *Handlers) PublishPoll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "publishPollEvent")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	topic := c.DefaultQuery("topic", defaultPollTopic)
+	h.sdk.AddAttribute(span, "poll.topic", topic)
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPollEventBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(413, gin.H{"error": "Event too large", "max_bytes": maxPollEventBytes})
+			return
+		}
+		h.sdk.RecordError(span, err)
+		c.JSON(400, gin.H{"error": "Failed to read body", "message": err.Error()})
+		return
+	}
+	if len(body) > 0 && !json.Valid(body) {
+		c.JSON(400, gin.H{"error": "Body must be JSON"})
+		return
+	}
+
+	event, err := h.polls.Publish(topic, body)
+	if errors.Is(err, poll.ErrTooManyTopics) {
+		h.sdk.RecordError(span, err)
+		c.JSON(503, gin.H{"error": "Too many poll topics", "topic": topic})
+		return
+	}
+	h.sdk.AddIntAttribute(span, "poll.seq", event.Seq)
+	h.sdk.SetSuccess(span)
+	c.JSON(201, event)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/orm"
+)
+
+// requireORM answers 503 when the GORM store isn't configured
+func (h *Handlers) requireORM(c *gin.Context) bool {
+	if h.orm == nil {
+		c.JSON(503, gin.H{
+			"error":   "Database not configured",
+			"message": "Set DATABASE_URL to enable this endpoint",
+		})
+		return false
+	}
+	return true
+}
+
+// ListProducts returns products through GORM; the query gets a gorm.query span
+func (h *Handlers) ListProducts(c *gin.Context) {
+	if !h.requireORM(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "listProducts")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	h.sdk.AddIntAttribute(span, "query.limit", int64(limit))
+
+	products, err := h.orm.ListProducts(ctx, limit)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to list products", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "product_count", int64(len(products)))
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{"products": products, "source": "gorm"})
+}
+
+// CreateProduct inserts a product from the JSON body
+func (h *Handlers) CreateProduct(c *gin.Context) {
+	if !h.requireORM(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "createProduct")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	var product orm.Product
+	if err := c.ShouldBindJSON(&product); err != nil || product.SKU == "" || product.Name == "" {
+		c.JSON(400, gin.H{"error": "sku and name are required"})
+		return
+	}
+
+	product, err := h.orm.CreateProduct(ctx, product)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to create product", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "product.id", int64(product.ID))
+	h.sdk.SetSuccess(span)
+	c.JSON(201, product)
+}
+
+// GetProduct returns one product by ID
+func (h *Handlers) GetProduct(c *gin.Context) {
+	if !h.requireORM(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "getProduct")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id, ok := productID(c)
+	if !ok {
+		return
+	}
+	h.sdk.AddIntAttribute(span, "product.id", int64(id))
+
+	product, err := h.orm.GetProduct(ctx, id)
+	if err != nil {
+		h.productError(c, span, err)
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, product)
+}
+
+// UpdateProduct changes the fields present in the JSON body
+func (h *Handlers) UpdateProduct(c *gin.Context) {
+	if !h.requireORM(c) {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "updateProduct")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id, ok := productID(c)
+	if !ok {
+		return
+	}
+	h.sdk.AddIntAttribute(span, "product.id", int64(id))
+
+	var update orm.ProductUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(400, gin.H{"error": "invalid JSON body: " + err.Error()})
+		return
+	}
+
+	product, err := h.orm.UpdateProduct(ctx, id, update)
+	if err != nil {
+		h.productError(c, span, err)
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, product)
+}
+
+// productID parses the :id parameter, answering 400 when it isn't a number
+func productID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "id must be a positive integer"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// productError answers 404 for unknown products and 500 otherwise
+func (h *Handlers) productError(c *gin.Context, span trace.Span, err error) {
+	if errors.Is(err, orm.ErrNotFound) {
+		h.sdk.AddEvent(span, "product.not_found")
+		c.JSON(404, gin.H{"error": "Product not found"})
+		return
+	}
+	h.sdk.RecordError(span, err)
+	c.JSON(500, gin.H{"error": "Product operation failed", "message": err.Error()})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/deadline"
+	"github.com/Tracekit-Dev/test-app/internal/requestid"
+	"github.com/Tracekit-Dev/test-app/internal/sampling"
+)
+
+// DebugPropagation shows every step of context propagation for this request:
+// the trace headers that arrived, the remote parent and server span the SDK
+// derived from them, and the headers an outbound call from here would carry.
+// Comparing its output across services pins down which hop drops the trace.
+func (h *Handlers) DebugPropagation(c *gin.Context) {
+	ctx := c.Request.Context()
+	propagator := otel.GetTextMapPropagator()
+
+	// Headers this app reads: everything the propagator knows plus our own
+	fields := append(propagator.Fields(), requestid.Header, deadline.Header)
+	received := make(map[string]string)
+	for _, field := range fields {
+		if value := c.GetHeader(field); value != "" {
+			received[http.CanonicalHeaderKey(field)] = value
+		}
+	}
+
+	// Extracting again yields the remote parent the server span was started from
+	remote := trace.SpanContextFromContext(propagator.Extract(ctx, propagation.HeaderCarrier(c.Request.Header)))
+	server := trace.SpanContextFromContext(ctx)
+
+	ctx, span := h.sdk.StartSpan(ctx, "debugPropagation")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	outbound := propagation.HeaderCarrier(http.Header{})
+	propagator.Inject(ctx, outbound)
+	injected := make(map[string]string, len(outbound))
+	for key := range http.Header(outbound) {
+		injected[key] = outbound.Get(key)
+	}
+
+	slices.Sort(fields)
+	h.sdk.AddIntAttribute(span, "propagation.received_headers", int64(len(received)))
+	h.sdk.AddIntAttribute(span, "propagation.injected_headers", int64(len(injected)))
+	h.sdk.SetSuccess(span)
+
+	c.JSON(200, gin.H{
+		"received": received,
+		"derived": gin.H{
+			"remote_parent": spanContextJSON(remote),
+			"server_span":   spanContextJSON(server),
+			"handler_span":  spanContextJSON(span.SpanContext()),
+			"sampling":      sampling.DecisionFrom(c),
+		},
+		"outbound":     injected,
+		"headers_read": slices.Compact(fields),
+	})
+}
+
+// spanContextJSON describes sc, or returns nil when it is invalid
+func spanContextJSON(sc trace.SpanContext) gin.H {
+	if !sc.IsValid() {
+		return nil
+	}
+	return gin.H{
+		"trace_id":   sc.TraceID().String(),
+		"span_id":    sc.SpanID().String(),
+		"sampled":    sc.IsSampled(),
+		"remote":     sc.IsRemote(),
+		"tracestate": sc.TraceState().String(),
+	}
+}
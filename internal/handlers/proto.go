@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+// writeProto answers with msg as JSON, or as binary protobuf when the
+// client accepts application/x-protobuf, serialized in its own span
+func (h *Handlers) writeProto(c *gin.Context, status int, msg proto.Message) {
+	format := pb.FormatJSON
+	if c.NegotiateFormat(gin.MIMEJSON, pb.ContentType(pb.FormatProtobuf)) == pb.ContentType(pb.FormatProtobuf) {
+		format = pb.FormatProtobuf
+	}
+	data, err := pb.Marshal(c.Request.Context(), h.sdk, format, msg)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to serialize response", "message": err.Error()})
+		return
+	}
+	c.Data(status, pb.ContentType(format), data)
+}
+
+// protoJSON renders msg for embedding in a JSON response, serialized in its
+// own span
+func (h *Handlers) protoJSON(ctx context.Context, msg proto.Message) (json.RawMessage, error) {
+	return pb.Marshal(ctx, h.sdk, pb.FormatJSON, msg)
+}
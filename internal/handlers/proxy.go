@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/requestid"
+)
+
+// newProxy builds the reverse proxy behind /proxy/*path. It forwards
+// through target's instrumented transport, so every proxied request gets a
+// CLIENT span and carries the trace context upstream.
+func newProxy(sdk *tracekit.SDK, client *clients.Client, target clients.Service) *httputil.ReverseProxy {
+	upstream, _ := url.Parse(target.URL)
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Path = strings.TrimPrefix(pr.In.URL.Path, "/proxy")
+			pr.Out.URL.RawPath = ""
+			pr.SetURL(upstream)
+			pr.SetXForwarded()
+			// The upstream logs the same request ID as the proxy
+			if id := requestid.FromContext(pr.In.Context()); id != "" {
+				pr.Out.Header.Set(requestid.Header, id)
+			}
+		},
+		Transport: client.HTTP(target).Transport,
+		// The proxy's own middleware already set these on the response
+		ModifyResponse: func(resp *http.Response) error {
+			resp.Header.Del(requestid.Header)
+			resp.Header.Del(requestid.TraceHeader)
+			return nil
+		},
+		// The outgoing request keeps the handler's context, so the span
+		// here is the proxy span rather than the CLIENT span
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			sdk.RecordError(trace.SpanFromContext(r.Context()), err)
+			status, message := 502, "Upstream unreachable"
+			if errors.Is(err, context.DeadlineExceeded) {
+				status, message = 504, "Upstream timed out"
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(gin.H{"error": message, "message": err.Error()})
+		},
+	}
+}
+
+// Proxy forwards any request under /proxy/ to the upstream with the prefix
+// stripped, e.g. /proxy/api/users to /api/users, the way an API gateway
+// would. The upstream is this app unless PROXY_TARGET_URL is set. The whole
+// exchange, body included, gets the upstream client's timeout.
+func (h *Handlers) Proxy(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "proxy")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, h.proxyTimeout)
+	defer cancel()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	h.sdk.AddAttribute(span, "proxy.upstream", h.upstream.Name)
+	h.sdk.AddAttribute(span, "proxy.upstream_url", h.upstream.URL)
+	h.sdk.AddAttribute(span, "proxy.path", c.Param("path"))
+	h.sdk.AddIntAttribute(span, "proxy.timeout_ms", h.proxyTimeout.Milliseconds())
+
+	h.proxy.ServeHTTP(c.Writer, c.Request)
+
+	status := c.Writer.Status()
+	h.sdk.AddIntAttribute(span, "response.status", int64(status))
+	if status < 500 {
+		h.sdk.SetSuccess(span)
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+// PublishOrder publishes an order event to Kafka with the trace context in
+// the message headers; the consumer continues the same trace
+func (h *Handlers) PublishOrder(c *gin.Context) {
+	if h.producer == nil {
+		c.JSON(503, gin.H{
+			"error":   "Kafka not configured",
+			"message": "Set KAFKA_BROKERS to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "publishOrder")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	order := &pb.Order{
+		OrderId:    fmt.Sprintf("ORD-%d", time.Now().UnixNano()),
+		CustomerId: defaultCustomerID,
+		Amount:     rand.Float64() * 1000,
+		Status:     "published",
+		CreatedAt:  timestamppb.Now(),
+	}
+	h.sdk.AddAttribute(span, "order.id", order.GetOrderId())
+
+	if err := h.producer.Publish(ctx, order); err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{"error": "Failed to publish order", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddEvent(span, "order.published")
+	h.sdk.SetSuccess(span)
+
+	h.writeProto(c, 202, order)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/sampling"
+)
+
+// Sampling reports the effective sampling settings and how they applied to
+// this request
+func (h *Handlers) Sampling(c *gin.Context) {
+	sc := trace.SpanContextFromContext(c.Request.Context())
+
+	c.JSON(200, gin.H{
+		"settings": h.sampler.Settings(),
+		"request": gin.H{
+			"trace_id": sc.TraceID().String(),
+			"sampled":  sc.IsSampled(),
+			"decision": sampling.DecisionFrom(c),
+		},
+	})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSlowQuery bounds how long /api/slow-query may hold a connection
+const maxSlowQuery = 10 * time.Second
+
+// SlowQuery runs a deliberately slow query (pg_sleep) so the DB span is
+// tagged db.slow=true, e.g. /api/slow-query?ms=1500
+func (h *Handlers) SlowQuery(c *gin.Context) {
+	if h.db == nil {
+		c.JSON(503, gin.H{
+			"error":   "Database not configured",
+			"message": "Set DATABASE_URL to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "slowQuery")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	ms, err := strconv.Atoi(c.DefaultQuery("ms", "1500"))
+	if err != nil || ms < 0 || time.Duration(ms)*time.Millisecond > maxSlowQuery {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("ms must be between 0 and %d", maxSlowQuery.Milliseconds())})
+		return
+	}
+	sleep := time.Duration(ms) * time.Millisecond
+	h.sdk.AddIntAttribute(span, "query.sleep_ms", int64(ms))
+
+	start := time.Now()
+	if err := h.db.Sleep(ctx, sleep); err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Slow query failed", "message": err.Error()})
+		return
+	}
+	took := time.Since(start)
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"query":             "SELECT pg_sleep($1)",
+		"requested_ms":      ms,
+		"duration_ms":       took.Milliseconds(),
+		"slow_threshold_ms": h.db.SlowThreshold().Milliseconds(),
+		"slow":              took >= h.db.SlowThreshold(),
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/soap"
+)
+
+// CallSOAP looks up an order in a legacy SOAP service, played by this app's
+// own /soap/orders: it builds the XML envelope, posts it, and parses the
+// answer, each in a child span. ?order_id= picks the order; an unknown one
+// comes back as a SOAP fault.
+func (h *Handlers) CallSOAP(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "callSOAP")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	orderID := c.DefaultQuery("order_id", "ORD-1001")
+	h.sdk.AddAttribute(span, "soap.action", soap.ActionGetOrderStatus)
+	h.sdk.AddAttribute(span, "soap.operation", "GetOrderStatus")
+	h.sdk.AddAttribute(span, "order.id", orderID)
+
+	self := h.client.Services().Self
+	url := self.URL + "/soap/orders"
+	var resp soap.GetOrderStatusResponse
+	err := soap.Call(ctx, h.sdk, h.client.HTTP(self), url, soap.ActionGetOrderStatus, soap.GetOrderStatus{OrderID: orderID}, &resp)
+
+	var fault *soap.Fault
+	if errors.As(err, &fault) {
+		h.sdk.AddAttribute(span, "soap.fault.code", fault.Code)
+		h.sdk.AddAttribute(span, "soap.fault.string", fault.String)
+		h.sdk.RecordError(span, err)
+		c.JSON(502, gin.H{
+			"called": url,
+			"fault":  gin.H{"code": fault.Code, "string": fault.String},
+		})
+		return
+	}
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(downstreamStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"called": url,
+		"order":  resp,
+	})
+}
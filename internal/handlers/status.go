@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/recent"
+)
+
+//go:embed templates/status.html
+var statusHTML string
+
+var statusTemplate = template.Must(template.New("status.html").Parse(statusHTML))
+
+// endpointDoc describes a route on the status page. example, when set, is
+// the path and query used in the curl command.
+type endpointDoc struct {
+	description string
+	example     string
+}
+
+// endpointDocs are keyed by "METHOD /path" as registered with gin
+var endpointDocs = map[string]endpointDoc{
+	"GET /":                                   {"This page (JSON hello unless the client asks for HTML)", ""},
+	"GET /health":                             {"Health check", ""},
+	"GET /health/deep":                        {"Probe downstream services and TraceKit (span per probe)", ""},
+	"GET /livez":                              {"Liveness probe (process up)", ""},
+	"GET /readyz":                             {"Readiness probe (SDK, config, exporter)", ""},
+	"GET /api/users":                          {"Fetch a page of users (with custom span)", "/api/users?page=2&per_page=5"},
+	"GET /api/users/:id":                      {"Fetch one user (7, 007, and usr_7 all work)", "/api/users/usr_002"},
+	"GET /api/users/:id/profile":              {"Fetch one user with an ETag (304 on If-None-Match)", "/api/users/2/profile"},
+	"PUT /api/users/:id":                      {"Update a user's name or email", ""},
+	"GET /api/users-db":                       {"Fetch users from Postgres (DB spans)", "/api/users-db?limit=2"},
+	"GET /api/slow-query":                     {"Deliberately slow query (db.slow=true)", "/api/slow-query?ms=1500"},
+	"GET /api/products":                       {"GORM-backed products (span per ORM operation)", ""},
+	"POST /api/products":                      {"Create a product", ""},
+	"GET /api/products/:id":                   {"Fetch one product", "/api/products/1"},
+	"PUT /api/products/:id":                   {"Update a product", "/api/products/1"},
+	"GET /api/documents":                      {"MongoDB documents (span per command)", ""},
+	"POST /api/documents":                     {"Create a document", ""},
+	"GET /api/documents/:id":                  {"Fetch one document", ""},
+	"PUT /api/documents/:id":                  {"Update a document", ""},
+	"DELETE /api/documents/:id":               {"Delete a document", ""},
+	"POST /api/order":                         {"Create order (inventory check, SQLite persistence); ?saga=true runs the order saga", ""},
+	"POST /api/orders/bulk":                   {"Create up to 1000 orders; 207 with a status per order when some fail", ""},
+	"GET /api/order/:id":                      {"Read back a persisted order (SQLite)", ""},
+	"GET /api/customers/:id/orders/:order_id": {"Route-template span naming with two path params", "/api/customers/42/orders/1001"},
+	"POST /api/publish-order":                 {"Publish order to Kafka (PRODUCER/CONSUMER spans)", ""},
+	"GET /api/cart":                           {"View the session's cart (session and cart IDs on spans)", ""},
+	"POST /api/cart/items":                    {"Add an item to the session's cart", ""},
+	"POST /api/cart/checkout":                 {"Check out the session's cart as an order", ""},
+	"POST /api/pay":                           {"Charge the payment simulator (honors Idempotency-Key)", ""},
+	"POST /api/notify":                        {"Fan a notification out to SMS, push, and email (one fails)", ""},
+	"POST /api/webhooks/:provider":            {"Receive an HMAC-signed webhook", ""},
+	"POST /api/outbound-webhooks":             {"Emit an outbound webhook (retries, dead-lettering)", "/api/outbound-webhooks?type=order.created"},
+	"GET /api/outbound-webhooks/dead-letters": {"Outbound webhooks that ran out of attempts", ""},
+	"GET /api/check-stock":                    {"NATS request/reply (context in message headers)", "/api/check-stock?sku=SKU-1&quantity=3"},
+	"POST /api/enqueue":                       {"Publish a task to RabbitMQ (PRODUCER/CONSUMER spans)", "/api/enqueue?type=email&fail_once=true"},
+	"POST /api/enqueue-sqs":                   {"Send a task to SQS (context in message attributes)", "/api/enqueue-sqs?type=thumbnail"},
+	"POST /api/jobs":                          {"Queue a background job (linked root span)", "/api/jobs?type=report"},
+	"POST /api/batch-process":                 {"Add an item to a shared batch (linked batch span)", ""},
+	"POST /api/batch":                         {"Process many items with bounded concurrency, a child span each", "/api/batch?count=100&concurrency=10"},
+	"GET /api/stream":                         {"Server-Sent Events stream (span event per chunk)", "/api/stream?events=5&interval=200ms"},
+	"GET /api/poll":                           {"Long poll for events on a topic, up to 30s", "/api/poll?timeout_ms=5000"},
+	"POST /api/poll":                          {"Publish an event to the long polls of a topic", ""},
+	"POST /api/upload":                        {"Multipart upload (file count, bytes, content types)", ""},
+	"GET /api/download/:size":                 {"Stream N megabytes (throughput, disconnects)", "/api/download/10"},
+	"GET /static/*filepath":                   {"Embedded CSS/JS/SVG assets (span per file)", "/static/style.css"},
+	"HEAD /static/*filepath":                  {"Asset headers only", "/static/logo.svg"},
+	"GET /api/export/users.csv":               {"Stream a large CSV export (progress event per chunk)", "/api/export/users.csv?rows=100000"},
+	"POST /graphql":                           {"GraphQL API (span per resolver)", ""},
+	"GET /api/metrics":                        {"Custom metrics info", ""},
+	"GET /api/sampling":                       {"Effective sampling settings and this request's decision", ""},
+	"GET /api/flags":                          {"Evaluate every feature flag for ?key=", ""},
+	"GET /api/chain":                          {"Chain call: Go -> Node -> Go", ""},
+	"GET /api/internal":                       {"Internal endpoint (called by Node)", ""},
+	"GET /api/data":                           {"Data endpoint (called by other services)", ""},
+	"GET /api/call-all":                       {"Call every downstream service in parallel", ""},
+	"GET /api/aggregate":                      {"Scatter-gather with a 200ms budget; late calls are abandoned", "/api/aggregate?budget_ms=200"},
+	"GET /api/call-grpc":                      {"Call OrderService over gRPC", "/api/call-grpc?order_id=ORD-42"},
+	"GET /api/grpc-stream":                    {"Track orders over a bidirectional gRPC stream", ""},
+	"GET /v1/orders/:order_id":                {"OrderService.GetOrder through grpc-gateway", "/v1/orders/ORD-42"},
+	"GET /api/call-flaky":                     {"Retry a flaky downstream (span per attempt)", ""},
+	"GET /api/cancel-demo":                    {"Cancel slow downstream work on disconnect or deadline", "/api/cancel-demo?work_ms=5000&timeout_ms=1000"},
+	"GET /api/hedged-call":                    {"Hedge a slow downstream call; the first answer wins", "/api/hedged-call?delay_ms=100"},
+	"GET /api/breakers":                       {"Circuit breaker state per downstream service", ""},
+	"GET /api/baggage":                        {"Propagate baggage to a downstream and echo it back", "/api/baggage?tenant=acme&flag=new-checkout"},
+	"GET /api/baggage/echo":                   {"Echo the baggage this request carried", ""},
+	"GET /api/debug/propagation":              {"Show received, derived, and outbound trace context", ""},
+	"GET /api/call-legacy":                    {"Call a B3- or Jaeger-only downstream", "/api/call-legacy?format=jaeger"},
+	"GET /api/call-graphql":                   {"Send a GraphQL query to a downstream GraphQL API", "/api/call-graphql?user_id=2"},
+	"GET /api/call-soap":                      {"Look up an order in a legacy SOAP service", "/api/call-soap?order_id=ORD-1003"},
+	"POST /soap/orders":                       {"Mock legacy SOAP 1.1 order status service", ""},
+	"GET /api/call-external":                  {"Call a third-party API (peer.service=external-api)", ""},
+	"GET /proxy/*path":                        {"Reverse proxy to the upstream (also POST, PUT, PATCH, DELETE)", "/proxy/api/users"},
+	"GET /api/legacy/:format":                 {"Downstream that reads one legacy header format", ""},
+	"GET /api/error":                          {"Trigger an error (for testing)", ""},
+	"GET /api/errors/:code":                   {"Return 400/401/403/404/429/503 with classified spans", "/api/errors/429"},
+	"GET /api/chaos":                          {"Inject latency and random failures", "/api/chaos?latency_ms=200&error_rate=0.3"},
+	"GET /api/flaky":                          {"Fail a percentage of calls, roll on the span", "/api/flaky?error_pct=30"},
+	"GET /api/latency":                        {"Sleep per a fitted latency distribution", "/api/latency?dist=pareto&p50=40&p99=900"},
+	"GET /api/contract/:scenario":             {"Produce a fixed span tree for conformance checks", "/api/contract/nested"},
+	"GET /security-test":                      {"Security scanning test", ""},
+}
+
+// routeInfo is one row of the status page's endpoint table
+type routeInfo struct {
+	Method      string
+	Path        string
+	Description string
+	Curl        string
+}
+
+// Hello answers browsers with the HTML status page and everything else
+// (curl, scripts) with a JSON hello
+func (h *Handlers) Hello(c *gin.Context) {
+	if strings.Contains(c.GetHeader("Accept"), "text/html") {
+		h.StatusPage(c)
+		return
+	}
+	c.JSON(200, gin.H{
+		"message": "Hello from Go Test App! 👋",
+		"service": "go-test-app",
+	})
+}
+
+// docFor describes a route, including the /api/call-<route> endpoints
+// generated from the service topology
+func (h *Handlers) docFor(method, path string) endpointDoc {
+	if doc, ok := endpointDocs[method+" "+path]; ok {
+		return doc
+	}
+	if route, ok := strings.CutPrefix(path, "/api/call-"); ok && method == "GET" {
+		if svc, ok := h.client.Services().Lookup(route); ok {
+			return endpointDoc{description: svc.Description}
+		}
+	}
+	return endpointDoc{}
+}
+
+// StatusPage renders every route with an example curl command and the trace
+// IDs of the last few requests. Rendering gets a span of its own.
+func (h *Handlers) StatusPage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "statusPage")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	baseURL := "http://" + c.Request.Host
+	var routes []routeInfo
+	if h.router != nil {
+		for _, r := range h.router.Routes() {
+			doc := h.docFor(r.Method, r.Path)
+			routes = append(routes, routeInfo{
+				Method:      r.Method,
+				Path:        r.Path,
+				Description: doc.description,
+				Curl:        curlCommand(baseURL, r.Method, r.Path, doc.example),
+			})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	var requests []recent.Request
+	if h.recent != nil {
+		requests = h.recent.List()
+	}
+	h.sdk.AddIntAttribute(span, "status_page.routes", int64(len(routes)))
+	h.sdk.AddIntAttribute(span, "status_page.recent_requests", int64(len(requests)))
+
+	// Render into a buffer inside its own span so template time is visible
+	_, render := h.sdk.StartSpan(ctx, "render status.html")
+	h.sdk.AddAttribute(render, "template.name", "status.html")
+	start := time.Now()
+	var page bytes.Buffer
+	err := statusTemplate.Execute(&page, gin.H{
+		"Service":     h.serviceName,
+		"Environment": h.environment,
+		"Now":         time.Now(),
+		"Routes":      routes,
+		"Recent":      requests,
+	})
+	h.sdk.AddFloatAttribute(render, "template.render_ms", float64(time.Since(start).Microseconds())/1000)
+	if err != nil {
+		h.sdk.RecordError(render, err)
+		render.End()
+		h.sdk.RecordError(span, err)
+		c.String(500, "failed to render status page: %v", err)
+		return
+	}
+	h.sdk.AddIntAttribute(render, "template.bytes", int64(page.Len()))
+	h.sdk.SetSuccess(render)
+	render.End()
+
+	h.sdk.SetSuccess(span)
+	c.Data(200, "text/html; charset=utf-8", page.Bytes())
+}
+
+// curlCommand builds an example command for a route, filling path
+// parameters with placeholders when there's no documented example
+func curlCommand(baseURL, method, path, example string) string {
+	if example == "" {
+		segments := strings.Split(path, "/")
+		for i, s := range segments {
+			if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+				segments[i] = "<" + s[1:] + ">"
+			}
+		}
+		example = strings.Join(segments, "/")
+	}
+
+	cmd := "curl "
+	if method != "GET" {
+		cmd += "-X " + method + " "
+	}
+	if strings.Contains(example, "?") {
+		return cmd + `"` + baseURL + example + `"`
+	}
+	return cmd + baseURL + example
+}
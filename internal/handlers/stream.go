@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStreamEvents caps ?events= so one request can't hold a connection forever
+const maxStreamEvents = 100
+
+// Stream sends Server-Sent Events over several seconds. The span stays open
+// for the whole stream, gets one event per chunk, and finishes with the
+// total bytes written and the stream duration.
+func (h *Handlers) Stream(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "streamEvents")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	count, err := strconv.Atoi(c.DefaultQuery("events", "10"))
+	if err != nil || count < 1 || count > maxStreamEvents {
+		count = 10
+	}
+	interval, err := time.ParseDuration(c.DefaultQuery("interval", "500ms"))
+	if err != nil || interval <= 0 || interval > 5*time.Second {
+		interval = 500 * time.Millisecond
+	}
+
+	h.sdk.AddIntAttribute(span, "stream.events_requested", int64(count))
+	h.sdk.AddIntAttribute(span, "stream.interval_ms", interval.Milliseconds())
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	start := time.Now()
+	sent := 0
+	totalBytes := 0
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+loop:
+	for sent < count {
+		data := fmt.Sprintf(`{"seq":%d,"service":"go-test-app","time":"%s"}`, sent+1, time.Now().Format(time.RFC3339Nano))
+		chunk := fmt.Sprintf("id: %d\nevent: tick\ndata: %s\n\n", sent+1, data)
+
+		if _, err := c.Writer.WriteString(chunk); err != nil {
+			h.sdk.AddEvent(span, "stream.write_failed")
+			break
+		}
+		c.Writer.Flush()
+
+		sent++
+		totalBytes += len(chunk)
+		span.AddEvent("stream.chunk", trace.WithAttributes(
+			attribute.Int("stream.seq", sent),
+			attribute.Int("stream.chunk_bytes", len(chunk)),
+		))
+
+		if sent == count {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			// Client went away; stop writing but keep the span accurate
+			h.sdk.AddEvent(span, "stream.client_disconnected")
+			break loop
+		case <-ticker.C:
+		}
+	}
+
+	h.sdk.AddIntAttribute(span, "stream.events_sent", int64(sent))
+	h.sdk.AddIntAttribute(span, "stream.total_bytes", int64(totalBytes))
+	h.sdk.AddIntAttribute(span, "stream.duration_ms", time.Since(start).Milliseconds())
+	h.sdk.SetSuccess(span)
+}
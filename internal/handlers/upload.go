@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxUploadBytes bounds the whole multipart body accepted by /api/upload
+const maxUploadBytes = 32 << 20
+
+// Upload accepts multipart files and records how many there were, their
+// total size and content types, and how long parsing took. Storage is
+// simulated in a child span.
+//
+//	curl -F file=@README.md -F file=@go.mod http://localhost:8082/api/upload
+func (h *Handlers) Upload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "upload")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBytes)
+
+	start := time.Now()
+	form, err := c.MultipartForm()
+	h.sdk.AddFloatAttribute(span, "upload.parse_duration_ms", float64(time.Since(start).Microseconds())/1000)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(413, gin.H{"error": "Upload too large", "max_bytes": maxUploadBytes})
+			return
+		}
+		c.JSON(400, gin.H{"error": "Expected a multipart/form-data body", "message": err.Error()})
+		return
+	}
+	defer form.RemoveAll()
+
+	type uploaded struct {
+		Name        string `json:"name"`
+		Size        int64  `json:"size"`
+		ContentType string `json:"content_type"`
+	}
+	var files []uploaded
+	var totalBytes int64
+	contentTypes := map[string]bool{}
+	for _, headers := range form.File {
+		for _, fh := range headers {
+			contentType := fh.Header.Get("Content-Type")
+			files = append(files, uploaded{Name: fh.Filename, Size: fh.Size, ContentType: contentType})
+			totalBytes += fh.Size
+			contentTypes[contentType] = true
+		}
+	}
+	if len(files) == 0 {
+		c.JSON(400, gin.H{"error": "No files in the upload"})
+		return
+	}
+
+	types := make([]string, 0, len(contentTypes))
+	for t := range contentTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	h.sdk.AddIntAttribute(span, "upload.file_count", int64(len(files)))
+	h.sdk.AddIntAttribute(span, "upload.total_bytes", totalBytes)
+	span.SetAttributes(attribute.StringSlice("upload.content_types", types))
+
+	// Simulate writing to object storage, reading each file once
+	_, store := h.sdk.StartSpan(ctx, "storage.write")
+	storeStart := time.Now()
+	var written int64
+	for _, headers := range form.File {
+		for _, fh := range headers {
+			f, err := fh.Open()
+			if err != nil {
+				h.sdk.RecordError(store, err)
+				store.End()
+				h.sdk.RecordError(span, err)
+				c.JSON(500, gin.H{"error": "Failed to read upload", "message": err.Error()})
+				return
+			}
+			n, _ := io.Copy(io.Discard, f)
+			f.Close()
+			written += n
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	h.sdk.AddIntAttribute(store, "storage.bytes_written", written)
+	h.sdk.AddFloatAttribute(store, "storage.duration_ms", float64(time.Since(storeStart).Microseconds())/1000)
+	h.sdk.SetSuccess(store)
+	store.End()
+
+	h.sdk.SetSuccess(span)
+	c.JSON(201, gin.H{
+		"files":       files,
+		"file_count":  len(files),
+		"total_bytes": totalBytes,
+	})
+}
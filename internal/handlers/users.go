@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+	"github.com/Tracekit-Dev/test-app/internal/userstore"
+)
+
+// usersCacheKey is the cache-aside key for the /api/users payload
+const usersCacheKey = "users:all"
+
+// Page sizes of /api/users
+const (
+	defaultUsersPerPage = 20
+	maxUsersPerPage     = 100
+)
+
+// Users fetches a page of users (?page=&per_page=) with a custom span and
+// metrics. When Redis is configured the full list is served cache-aside and
+// paged from there.
+func (h *Handlers) Users(c *gin.Context) {
+	defer h.metrics.trackRequest()()
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "fetchUsers")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	h.sdk.AddAttribute(span, "endpoint", "/api/users")
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(400, gin.H{"error": "page must be a positive integer"})
+		return
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(defaultUsersPerPage)))
+	if err != nil || perPage < 1 || perPage > maxUsersPerPage {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("per_page must be between 1 and %d", maxUsersPerPage)})
+		return
+	}
+	h.sdk.AddIntAttribute(span, "pagination.page", int64(page))
+	h.sdk.AddIntAttribute(span, "pagination.per_page", int64(perPage))
+
+	var users []userstore.User
+	source := "origin"
+
+	if h.cache != nil {
+		hit, err := h.cache.Get(ctx, usersCacheKey, &users)
+		if err != nil {
+			// A broken cache shouldn't fail the request, fall through to the origin
+			h.sdk.AddEvent(span, "cache.error")
+		}
+		span.SetAttributes(attribute.Bool("cache.hit", hit))
+		h.sdk.AddAttribute(span, "cache.key", usersCacheKey)
+		if hit {
+			source = "cache"
+		}
+	}
+
+	if source == "origin" {
+		users = h.loadUsers(ctx)
+		if h.cache != nil {
+			if err := h.cache.Set(ctx, usersCacheKey, users); err != nil {
+				h.sdk.AddEvent(span, "cache.error")
+			}
+		}
+	}
+
+	total := len(users)
+	totalPages := (total + perPage - 1) / perPage
+	if page > totalPages {
+		// Past the end is an empty page rather than an error
+		users = users[:0]
+	} else {
+		start := (page - 1) * perPage
+		users = users[start:min(start+perPage, total)]
+	}
+
+	h.sdk.AddIntAttribute(span, "pagination.total", int64(total))
+	h.sdk.AddIntAttribute(span, "pagination.returned_count", int64(len(users)))
+	h.sdk.AddIntAttribute(span, "user_count", int64(len(users)))
+	h.sdk.AddAttribute(span, "users.source", source)
+	h.sdk.AddEvent(span, "users.fetched")
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"users":       users,
+		"source":      source,
+		"page":        page,
+		"per_page":    perPage,
+		"total":       total,
+		"total_pages": totalPages,
+	})
+}
+
+// loadUsers simulates the slow origin behind the cache
+func (h *Handlers) loadUsers(ctx context.Context) []userstore.User {
+	time.Sleep(50 * time.Millisecond)
+
+	return h.users.List(ctx)
+}
+
+// GetUser returns one user. The id accepts "7", "007", and "usr_7" alike.
+func (h *Handlers) GetUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "getUser")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id, ok := h.validateUserID(c)
+	if !ok {
+		return
+	}
+	h.sdk.AddIntAttribute(span, "user.id", int64(id))
+
+	user, err := h.users.Get(ctx, id)
+	if errors.Is(err, userstore.ErrNotFound) {
+		h.sdk.AddEvent(span, "user.not_found")
+		c.JSON(404, gin.H{"error": "User not found", "user_id": id})
+		return
+	}
+
+	h.sdk.SetSuccess(span)
+	h.writeProto(c, 200, &pb.User{Id: int32(user.ID), Name: user.Name, Email: user.Email})
+}
+
+// UpdateUser changes a user's name or email from the JSON body
+func (h *Handlers) UpdateUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "updateUser")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	id, ok := h.validateUserID(c)
+	if !ok {
+		return
+	}
+	h.sdk.AddIntAttribute(span, "user.id", int64(id))
+
+	var upd userstore.Update
+	if err := c.ShouldBindJSON(&upd); err != nil || (upd.Name == nil && upd.Email == nil) {
+		c.JSON(400, gin.H{"error": "Body must set name or email"})
+		return
+	}
+	if upd.Name != nil && strings.TrimSpace(*upd.Name) == "" {
+		c.JSON(400, gin.H{"error": "name must not be empty"})
+		return
+	}
+	if upd.Email != nil {
+		if _, err := mail.ParseAddress(*upd.Email); err != nil {
+			c.JSON(400, gin.H{"error": "email is not a valid address"})
+			return
+		}
+	}
+
+	user, err := h.users.Update(ctx, id, upd)
+	if errors.Is(err, userstore.ErrNotFound) {
+		h.sdk.AddEvent(span, "user.not_found")
+		c.JSON(404, gin.H{"error": "User not found", "user_id": id})
+		return
+	}
+
+	// The cached /api/users list still has the old values
+	if h.cache != nil {
+		if err := h.cache.Delete(ctx, usersCacheKey); err != nil {
+			h.sdk.AddEvent(span, "cache.error")
+		}
+	}
+
+	h.sdk.SetSuccess(span)
+	c.JSON(200, user)
+}
+
+// validateUserID normalizes the :id parameter in its own span and answers
+// 400 when it isn't a user ID
+func (h *Handlers) validateUserID(c *gin.Context) (int, bool) {
+	_, span := h.sdk.StartSpan(c.Request.Context(), "validateUserID")
+	defer span.End()
+
+	raw := c.Param("id")
+	h.sdk.AddAttribute(span, "user.id.raw", raw)
+
+	id, err := userstore.NormalizeID(raw)
+	span.SetAttributes(attribute.Bool("user.id.valid", err == nil))
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(400, gin.H{"error": "Invalid user id", "message": err.Error(), "user_id": raw})
+		return 0, false
+	}
+
+	normalized := strconv.Itoa(id)
+	h.sdk.AddAttribute(span, "user.id", normalized)
+	span.SetAttributes(attribute.Bool("user.id.normalized", normalized != raw))
+	h.sdk.SetSuccess(span)
+	return id, true
+}
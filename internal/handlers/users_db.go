@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsersDB fetches users from Postgres; every query produces a DB span
+func (h *Handlers) UsersDB(c *gin.Context) {
+	defer h.metrics.trackRequest()()
+
+	if h.db == nil {
+		c.JSON(503, gin.H{
+			"error":   "Database not configured",
+			"message": "Set DATABASE_URL to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "fetchUsersFromDB")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	h.sdk.AddIntAttribute(span, "query.limit", int64(limit))
+
+	users, err := h.db.ListUsers(ctx, limit)
+	if err != nil {
+		h.sdk.RecordError(span, err)
+		c.JSON(500, gin.H{"error": "Failed to query users", "message": err.Error()})
+		return
+	}
+
+	h.sdk.AddIntAttribute(span, "user_count", int64(len(users)))
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{"users": users, "source": "postgres"})
+}
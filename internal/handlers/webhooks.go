@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Tracekit-Dev/test-app/internal/webhook"
+)
+
+// maxWebhookBytes bounds an inbound webhook payload
+const maxWebhookBytes = 1 << 20
+
+// ReceiveWebhook accepts a webhook from :provider. The payload must be signed
+// with WEBHOOK_SECRET in X-Webhook-Signature; verification and processing
+// are separate child spans.
+func (h *Handlers) ReceiveWebhook(c *gin.Context) {
+	if len(h.webhookSecret) == 0 {
+		c.JSON(503, gin.H{
+			"error":   "Webhooks not configured",
+			"message": "Set WEBHOOK_SECRET to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "receiveWebhook")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	provider := c.Param("provider")
+	h.sdk.AddAttribute(span, "webhook.provider", provider)
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(413, gin.H{"error": "Payload too large", "max_bytes": maxWebhookBytes})
+			return
+		}
+		h.sdk.RecordError(span, err)
+		c.JSON(400, gin.H{"error": "Failed to read payload", "message": err.Error()})
+		return
+	}
+	h.sdk.AddIntAttribute(span, "webhook.payload_bytes", int64(len(body)))
+
+	// Verify before looking at the payload at all
+	signature := c.GetHeader(webhook.SignatureHeader)
+	_, verify := h.sdk.StartSpan(ctx, "webhook.verify")
+	h.sdk.AddAttribute(verify, "webhook.signature_algorithm", "hmac-sha256")
+	verify.SetAttributes(attribute.Bool("webhook.signature_present", signature != ""))
+	err = webhook.Verify(h.webhookSecret, body, signature)
+	verify.SetAttributes(attribute.Bool("webhook.verified", err == nil))
+	if err != nil {
+		h.sdk.AddAttribute(verify, "webhook.verification_failure", webhook.FailureReason(err))
+		h.sdk.AddEvent(verify, "webhook.rejected")
+	} else {
+		h.sdk.SetSuccess(verify)
+	}
+	verify.End()
+
+	span.SetAttributes(attribute.Bool("webhook.verified", err == nil))
+	if err != nil {
+		h.sdk.AddAttribute(span, "webhook.verification_failure", webhook.FailureReason(err))
+		c.JSON(401, gin.H{"error": "Invalid signature", "message": err.Error()})
+		return
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+
+	_, process := h.sdk.StartSpan(ctx, "webhook.process")
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.sdk.RecordError(process, err)
+		process.End()
+		c.JSON(400, gin.H{"error": "Invalid JSON payload", "message": err.Error()})
+		return
+	}
+	h.sdk.AddAttribute(process, "webhook.provider", provider)
+	h.sdk.AddAttribute(process, "webhook.event_id", event.ID)
+	h.sdk.AddAttribute(process, "webhook.event_type", event.Type)
+	time.Sleep(20 * time.Millisecond)
+	h.sdk.SetSuccess(process)
+	process.End()
+
+	h.sdk.AddAttribute(span, "webhook.event_type", event.Type)
+	h.sdk.SetSuccess(span)
+	c.JSON(200, gin.H{
+		"received":   true,
+		"provider":   provider,
+		"event_id":   event.ID,
+		"event_type": event.Type,
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tracekit-Dev/test-app/internal/webhook"
+)
+
+// EmitWebhook queues an outbound webhook of ?type for delivery to
+// WEBHOOK_TARGET_URL. Delivery happens in the background; each attempt is
+// its own trace linked to this request.
+func (h *Handlers) EmitWebhook(c *gin.Context) {
+	if h.webhooks == nil {
+		c.JSON(503, gin.H{
+			"error":   "Outbound webhooks not configured",
+			"message": "Set WEBHOOK_TARGET_URL to enable this endpoint",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	ctx, span := h.sdk.StartSpan(ctx, "emitWebhook")
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+
+	eventType := c.DefaultQuery("type", "order.created")
+	ev, err := h.webhooks.Emit(ctx, eventType, gin.H{"order_id": "ORD-12345", "amount": 99.99})
+	if errors.Is(err, webhook.ErrQueueFull) {
+		h.sdk.AddEvent(span, "webhook.queue_full")
+		c.JSON(503, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.sdk.AddAttribute(span, "webhook.event_id", ev.ID)
+	h.sdk.AddAttribute(span, "webhook.event_type", ev.Type)
+	h.sdk.SetSuccess(span)
+
+	c.JSON(202, gin.H{"event_id": ev.ID, "type": ev.Type, "status": "queued"})
+}
+
+// WebhookDeadLetters lists outbound webhooks that ran out of attempts
+func (h *Handlers) WebhookDeadLetters(c *gin.Context) {
+	if h.webhooks == nil {
+		c.JSON(503, gin.H{
+			"error":   "Outbound webhooks not configured",
+			"message": "Set WEBHOOK_TARGET_URL to enable this endpoint",
+		})
+		return
+	}
+	c.JSON(200, gin.H{"dead_letters": h.webhooks.DeadLetters()})
+}
@@ -0,0 +1,150 @@
+// Package health probes the app's dependencies concurrently, each probe in
+// its own span, and reports per-dependency status and latency.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+)
+
+// Status values reported per dependency and overall
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Probe checks one dependency
+type Probe struct {
+	Name   string
+	Kind   string
+	Target string
+	Check  func(ctx context.Context) error
+}
+
+// Result is the outcome of one probe
+type Result struct {
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	Target    string  `json:"target"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Run executes every probe concurrently, giving each up to timeout, and
+// returns the results in probe order
+func Run(ctx context.Context, sdk *tracekit.SDK, probes []Probe, timeout time.Duration) []Result {
+	results := make([]Result, len(probes))
+
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, sdk, probe, timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// run executes one probe inside a "health.probe <name>" span
+func run(ctx context.Context, sdk *tracekit.SDK, probe Probe, timeout time.Duration) Result {
+	ctx, span := sdk.StartSpan(ctx, "health.probe "+probe.Name)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	sdk.AddAttribute(span, "health.dependency", probe.Name)
+	sdk.AddAttribute(span, "health.kind", probe.Kind)
+	sdk.AddAttribute(span, "health.target", probe.Target)
+
+	start := time.Now()
+	err := probe.Check(ctx)
+	latency := float64(time.Since(start).Microseconds()) / 1000
+
+	sdk.AddFloatAttribute(span, "health.latency_ms", latency)
+
+	result := Result{
+		Name:      probe.Name,
+		Kind:      probe.Kind,
+		Target:    probe.Target,
+		Status:    StatusUp,
+		LatencyMS: latency,
+	}
+	if err != nil {
+		sdk.RecordError(span, err)
+		result.Status = StatusDown
+		result.Error = err.Error()
+	} else {
+		sdk.SetSuccess(span)
+	}
+	sdk.AddAttribute(span, "health.status", result.Status)
+
+	return result
+}
+
+// HTTPProbe is up when GET url answers with a non-5xx status
+func HTTPProbe(name, url string, client *http.Client) Probe {
+	return Probe{
+		Name:   name,
+		Kind:   "http",
+		Target: url,
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= 500 {
+				return fmt.Errorf("unhealthy status %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
+
+// TCPProbe is up when a TCP connection to addr can be opened
+func TCPProbe(name, addr string) Probe {
+	return Probe{
+		Name:   name,
+		Kind:   "tcp",
+		Target: addr,
+		Check: func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// EndpointAddr turns a TraceKit endpoint such as "api.tracekit.dev" or
+// "localhost:8081" into a host:port to dial, using the default port for the
+// scheme when none is given
+func EndpointAddr(endpoint string, useSSL bool) string {
+	endpoint = strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if _, _, err := net.SplitHostPort(endpoint); err == nil {
+		return endpoint
+	}
+	if useSSL {
+		return net.JoinHostPort(endpoint, "443")
+	}
+	return net.JoinHostPort(endpoint, "80")
+}
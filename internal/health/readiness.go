@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// exporterTimeout bounds the exporter check on each readiness request
+const exporterTimeout = time.Second
+
+// Readiness tracks whether the app should receive traffic: every startup
+// condition has been met, the exporter is reachable, and the app isn't
+// shutting down
+type Readiness struct {
+	exporter Probe
+
+	mu         sync.Mutex
+	conditions []string
+	met        map[string]bool
+	draining   bool
+}
+
+// NewReadiness creates a Readiness that waits for the named conditions and
+// checks the exporter with the given probe
+func NewReadiness(exporter Probe, conditions ...string) *Readiness {
+	return &Readiness{
+		exporter:   exporter,
+		conditions: conditions,
+		met:        make(map[string]bool, len(conditions)),
+	}
+}
+
+// MarkReady records that a startup condition has been met
+func (r *Readiness) MarkReady(condition string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.met[condition] = true
+}
+
+// Drain marks the app as shutting down so load balancers stop sending traffic
+func (r *Readiness) Drain() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.draining = true
+}
+
+// Check reports whether the app is ready, along with the state of each check
+func (r *Readiness) Check(ctx context.Context) (bool, map[string]string) {
+	r.mu.Lock()
+	checks := make(map[string]string, len(r.conditions)+2)
+	ready := !r.draining
+	for _, condition := range r.conditions {
+		if r.met[condition] {
+			checks[condition] = StatusUp
+		} else {
+			checks[condition] = "pending"
+			ready = false
+		}
+	}
+	if r.draining {
+		checks["shutdown"] = "draining"
+	}
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, exporterTimeout)
+	defer cancel()
+	if err := r.exporter.Check(ctx); err != nil {
+		checks["exporter"] = StatusDown
+		ready = false
+	} else {
+		checks["exporter"] = StatusUp
+	}
+
+	return ready, checks
+}
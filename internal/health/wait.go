@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+
+	"github.com/Tracekit-Dev/test-app/internal/retry"
+)
+
+// waitBackoff spaces out the probes of a dependency that isn't up yet
+var waitBackoff = retry.Policy{
+	BaseDelay: 250 * time.Millisecond,
+	MaxDelay:  5 * time.Second,
+}
+
+// waitProbeTimeout bounds each probe while waiting
+const waitProbeTimeout = 2 * time.Second
+
+// WaitResult is the outcome of waiting for one dependency
+type WaitResult struct {
+	Result
+	Attempts int `json:"attempts"`
+}
+
+// Wait probes every dependency until it's up or timeout passes, retrying
+// with jittered backoff, and reports whether all of them came up. It runs
+// in a "startup.wait" span with a "startup.wait <name>" child per
+// dependency, and every probe gets its own span under that.
+func Wait(ctx context.Context, sdk *tracekit.SDK, probes []Probe, timeout time.Duration) (bool, []WaitResult) {
+	ctx, span := sdk.StartSpan(ctx, "startup.wait")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	sdk.AddIntAttribute(span, "startup.wait.timeout_ms", timeout.Milliseconds())
+	sdk.AddIntAttribute(span, "startup.wait.dependencies", int64(len(probes)))
+
+	start := time.Now()
+	results := make([]WaitResult, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = waitOne(ctx, sdk, probe)
+		}()
+	}
+	wg.Wait()
+
+	down := 0
+	for _, r := range results {
+		if r.Status == StatusDown {
+			down++
+		}
+	}
+	sdk.AddIntAttribute(span, "startup.wait.elapsed_ms", time.Since(start).Milliseconds())
+	sdk.AddIntAttribute(span, "startup.wait.down", int64(down))
+	if down > 0 {
+		sdk.AddEvent(span, "startup.wait.timeout")
+	} else {
+		sdk.SetSuccess(span)
+	}
+	return down == 0, results
+}
+
+// waitOne probes one dependency until it's up or ctx is done
+func waitOne(ctx context.Context, sdk *tracekit.SDK, probe Probe) WaitResult {
+	ctx, span := sdk.StartSpan(ctx, "startup.wait "+probe.Name)
+	defer span.End()
+
+	sdk.AddAttribute(span, "health.dependency", probe.Name)
+	sdk.AddAttribute(span, "health.target", probe.Target)
+
+	var result WaitResult
+	for attempt := 1; ; attempt++ {
+		result = WaitResult{Result: run(ctx, sdk, probe, waitProbeTimeout), Attempts: attempt}
+		if result.Status == StatusUp || !sleep(ctx, max(waitBackoff.Backoff(attempt), waitBackoff.BaseDelay)) {
+			break
+		}
+	}
+
+	sdk.AddIntAttribute(span, "startup.wait.attempts", int64(result.Attempts))
+	sdk.AddAttribute(span, "health.status", result.Status)
+	if result.Status == StatusUp {
+		sdk.SetSuccess(span)
+	} else {
+		sdk.AddEvent(span, "startup.wait.gave_up")
+	}
+	return result
+}
+
+// sleep waits for d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
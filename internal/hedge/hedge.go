@@ -0,0 +1,103 @@
+// Package hedge cuts tail latency by sending a backup request when the first
+// one is slow and keeping whichever answers first. Every request runs in its
+// own span, so the race shows up as sibling spans in the trace waterfall.
+package hedge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attempts, as recorded in hedge.attempt and hedge.winner
+var Attempts = [2]string{"primary", "hedge"}
+
+// errLost cancels the attempt that didn't answer first
+var errLost = errors.New("hedge: another attempt answered first")
+
+type result struct {
+	attempt int
+	err     error
+}
+
+// Do calls fn with attempt 0 and, if that hasn't returned after delay, calls
+// it again with attempt 1. The first call to succeed wins and the other one
+// is cancelled; a call that fails leaves the race to the other. Each call
+// runs in a span named name+".attempt" with hedge.attempt, hedge.delay_ms,
+// and hedge.outcome attributes. It returns the number of calls made, the
+// winning attempt (-1 if none succeeded), and the last error.
+//
+// Do returns once the loser has stopped, so fn may write to per-attempt
+// state without further locking.
+func Do(ctx context.Context, sdk *tracekit.SDK, name string, delay time.Duration, fn func(ctx context.Context, attempt int) error) (made, winner int, err error) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make(chan result, len(Attempts))
+	launch := func(attempt int, delay time.Duration) {
+		made++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- result{attempt: attempt, err: runAttempt(ctx, sdk, name, attempt, delay, fn)}
+		}()
+	}
+
+	launch(0, 0)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for pending := 1; pending > 0; {
+		select {
+		case <-timer.C:
+			launch(1, delay)
+			pending++
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				cancel(errLost)
+				return made, r.attempt, nil
+			}
+			err = r.err
+			if made == 1 {
+				// The primary failed before the hedge was due; a hedge
+				// isn't a retry
+				return made, -1, err
+			}
+		}
+	}
+	return made, -1, err
+}
+
+// runAttempt runs a single call inside its own span. A call cancelled
+// because the other one won isn't a failure.
+func runAttempt(ctx context.Context, sdk *tracekit.SDK, name string, attempt int, delay time.Duration, fn func(ctx context.Context, attempt int) error) error {
+	ctx, span := sdk.StartSpan(ctx, name+".attempt")
+	defer span.End()
+
+	sdk.AddAttribute(span, "hedge.attempt", Attempts[attempt])
+	sdk.AddIntAttribute(span, "hedge.delay_ms", delay.Milliseconds())
+
+	err := fn(ctx, attempt)
+	switch {
+	case err == nil:
+		sdk.AddAttribute(span, "hedge.outcome", "succeeded")
+		sdk.SetSuccess(span)
+	case errors.Is(context.Cause(ctx), errLost):
+		sdk.AddAttribute(span, "hedge.outcome", "cancelled")
+		span.AddEvent("hedge.cancelled", trace.WithAttributes(
+			attribute.String("hedge.reason", "other attempt answered first"),
+		))
+	default:
+		sdk.AddAttribute(span, "hedge.outcome", "failed")
+		sdk.RecordError(span, err)
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+// Package inventory answers stock checks from an in-memory cache in front of
+// the inventory table. The cache lookup, the store lookup, and the check as a
+// whole are separate spans, so cache and database latency can be compared in
+// a single trace.
+package inventory
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/Tracekit-Dev/test-app/internal/database"
+)
+
+// ErrUnknownSKU is returned when checking a SKU the store doesn't carry
+var ErrUnknownSKU = errors.New("unknown sku")
+
+// Seed is the stock the inventory table starts with
+var Seed = map[string]int{
+	"SKU-1": 120,
+	"SKU-2": 45,
+	"SKU-3": 12,
+	"SKU-4": 3,
+	"SKU-5": 80,
+}
+
+// Result is the outcome of a stock check
+type Result struct {
+	SKU       string `json:"sku"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+	InStock   bool   `json:"in_stock"`
+
+	// Source is "cache" or "db"
+	Source string `json:"source"`
+}
+
+type cached struct {
+	available int
+	expires   time.Time
+}
+
+// Service checks stock levels
+type Service struct {
+	sdk *tracekit.SDK
+	db  *database.DB
+	ttl time.Duration
+
+	// outOfStockRate is the chance a store lookup finds the SKU sold out
+	outOfStockRate float64
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+// New creates an inventory service. Without db, store lookups are simulated
+// from Seed. Cached stock levels expire after ttl.
+func New(sdk *tracekit.SDK, db *database.DB, ttl time.Duration, outOfStockRate float64) *Service {
+	return &Service{sdk: sdk, db: db, ttl: ttl, outOfStockRate: outOfStockRate, cache: make(map[string]cached)}
+}
+
+// Check reports whether quantity units of sku are available
+func (s *Service) Check(ctx context.Context, sku string, quantity int) (Result, error) {
+	ctx, span := s.sdk.StartSpan(ctx, "inventory.check")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "inventory.sku", sku)
+	s.sdk.AddIntAttribute(span, "inventory.requested", int64(quantity))
+
+	res := Result{SKU: sku, Requested: quantity, Source: "cache"}
+	available, hit := s.cacheGet(ctx, sku)
+	if !hit {
+		var err error
+		if available, err = s.storeGet(ctx, sku); err != nil {
+			s.sdk.RecordError(span, err)
+			return res, err
+		}
+		res.Source = "db"
+		s.cacheSet(ctx, sku, available)
+	}
+
+	res.Available = available
+	res.InStock = available >= quantity
+
+	s.sdk.AddAttribute(span, "inventory.source", res.Source)
+	s.sdk.AddIntAttribute(span, "inventory.available", int64(available))
+	span.SetAttributes(
+		attribute.Bool("inventory.in_stock", res.InStock),
+		attribute.Bool("inventory.out_of_stock", available == 0),
+	)
+	if !res.InStock {
+		s.sdk.AddEvent(span, "inventory.insufficient_stock")
+	}
+	s.sdk.SetSuccess(span)
+	return res, nil
+}
+
+// cacheGet looks sku up in the in-memory cache
+func (s *Service) cacheGet(ctx context.Context, sku string) (int, bool) {
+	_, span := s.sdk.StartSpan(ctx, "inventory.cache.get")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "cache.system", "memory")
+	s.sdk.AddAttribute(span, "cache.key", "inventory:"+sku)
+
+	start := time.Now()
+	s.mu.Lock()
+	c, ok := s.cache[sku]
+	s.mu.Unlock()
+	hit := ok && time.Now().Before(c.expires)
+	s.sdk.AddFloatAttribute(span, "cache.lookup_ms", float64(time.Since(start).Microseconds())/1000)
+
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
+	s.sdk.SetSuccess(span)
+	return c.available, hit
+}
+
+// cacheSet caches sku's stock level for ttl
+func (s *Service) cacheSet(ctx context.Context, sku string, available int) {
+	_, span := s.sdk.StartSpan(ctx, "inventory.cache.set")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "cache.system", "memory")
+	s.sdk.AddAttribute(span, "cache.key", "inventory:"+sku)
+
+	s.mu.Lock()
+	s.cache[sku] = cached{available: available, expires: time.Now().Add(s.ttl)}
+	s.mu.Unlock()
+
+	s.sdk.SetSuccess(span)
+}
+
+// storeGet reads sku's stock level from the inventory table. Now and then
+// the SKU turns out to be sold out, as happens during a rush.
+func (s *Service) storeGet(ctx context.Context, sku string) (int, error) {
+	ctx, span := s.sdk.StartSpan(ctx, "inventory.db.get")
+	defer span.End()
+
+	start := time.Now()
+	var available int
+	var err error
+	if s.db != nil {
+		s.sdk.AddAttribute(span, "inventory.store", "sqlite")
+		available, err = s.db.GetStock(ctx, sku)
+		if errors.Is(err, database.ErrSKUNotFound) {
+			err = ErrUnknownSKU
+		}
+	} else {
+		s.sdk.AddAttribute(span, "inventory.store", "simulated")
+		time.Sleep(time.Duration(5+rand.Intn(15)) * time.Millisecond)
+		var ok bool
+		if available, ok = Seed[sku]; !ok {
+			err = ErrUnknownSKU
+		}
+	}
+	s.sdk.AddFloatAttribute(span, "inventory.lookup_ms", float64(time.Since(start).Microseconds())/1000)
+
+	if err != nil {
+		s.sdk.RecordError(span, err)
+		return 0, err
+	}
+
+	if rand.Float64() < s.outOfStockRate {
+		available = 0
+		s.sdk.AddEvent(span, "inventory.sold_out")
+	}
+	s.sdk.AddIntAttribute(span, "inventory.available", int64(available))
+	s.sdk.SetSuccess(span)
+	return available, nil
+}
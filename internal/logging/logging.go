@@ -0,0 +1,121 @@
+// Package logging configures the app's structured logger and correlates
+// log lines with the active trace so they can be joined in TraceKit.
+package logging
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/Tracekit-Dev/test-app/internal/requestid"
+)
+
+// minLevel is the running logger's minimum level, changed by SetLevel
+var minLevel = zap.NewAtomicLevel()
+
+// Setup builds the process-wide logger and installs it as zap's global
+// logger. Development gets a readable console encoder; everything else
+// logs JSON.
+func Setup(level, environment string) (*zap.Logger, error) {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := zap.NewProductionConfig()
+	if environment == "development" {
+		cfg = zap.NewDevelopmentConfig()
+	}
+	minLevel.SetLevel(lvl)
+	cfg.Level = minLevel
+	cfg.EncoderConfig.TimeKey = "time"
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	logger, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	zap.ReplaceGlobals(logger)
+	// Route the standard library logger through zap as well
+	zap.RedirectStdLog(logger)
+
+	return logger, nil
+}
+
+// SetLevel changes the minimum level of the logger built by Setup
+func SetLevel(name string) error {
+	lvl, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	minLevel.SetLevel(lvl)
+	return nil
+}
+
+// Level returns the logger's current minimum level
+func Level() string {
+	return minLevel.String()
+}
+
+// TraceFields returns trace_id and span_id fields for the span in ctx,
+// or nil when ctx carries no valid span
+func TraceFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	}
+}
+
+// FromContext returns the global logger annotated with the trace and span
+// IDs from ctx
+func FromContext(ctx context.Context) *zap.Logger {
+	return zap.L().With(TraceFields(ctx)...)
+}
+
+// GinMiddleware writes one access log line per request. Register it after
+// the SDK middleware so the server span is already in the request context.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("route", route),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+		}
+		if id := requestid.FromContext(c.Request.Context()); id != "" {
+			fields = append(fields, zap.String("request_id", id))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		logger := FromContext(c.Request.Context())
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			logger.Error("request", fields...)
+		case status >= 400:
+			logger.Warn("request", fields...)
+		default:
+			logger.Info("request", fields...)
+		}
+	}
+}
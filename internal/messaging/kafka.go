@@ -0,0 +1,200 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// KafkaProducer publishes order events with the trace context in the headers
+type KafkaProducer struct {
+	w   *kafka.Writer
+	sdk *tracekit.SDK
+}
+
+// NewKafkaProducer creates a producer for topic
+func NewKafkaProducer(sdk *tracekit.SDK, brokers []string, topic string) *KafkaProducer {
+	return &KafkaProducer{
+		w: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+		sdk: sdk,
+	}
+}
+
+// Kafka headers describing a message's payload
+const (
+	contentTypeHeader = "content-type"
+	schemaHeader      = "schema"
+)
+
+// Publish sends an order as binary protobuf inside a PRODUCER span. The
+// content-type and schema headers tell the consumer how to decode it.
+func (p *KafkaProducer) Publish(ctx context.Context, order *pb.Order) error {
+	ctx, span := tracing.Tracer().Start(ctx, p.w.Topic+" publish", trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.operation", "publish"),
+		attribute.String("messaging.destination.name", p.w.Topic),
+		attribute.String("messaging.kafka.message.key", order.GetOrderId()),
+	)
+
+	value, err := pb.Marshal(ctx, p.sdk, pb.FormatProtobuf, order)
+	if err != nil {
+		p.sdk.RecordError(span, err)
+		return err
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(order.GetOrderId()),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: contentTypeHeader, Value: []byte(pb.ContentType(pb.FormatProtobuf))},
+			{Key: schemaHeader, Value: []byte(pb.Schema(order))},
+		},
+	}
+	tracing.Inject(ctx, kafkaHeaderCarrier{&msg.Headers})
+
+	if err := p.w.WriteMessages(ctx, msg); err != nil {
+		p.sdk.RecordError(span, err)
+		return err
+	}
+
+	p.sdk.AddIntAttribute(span, "messaging.message.body.size", int64(len(value)))
+	p.sdk.SetSuccess(span)
+	return nil
+}
+
+// Close flushes pending messages and closes the writer
+func (p *KafkaProducer) Close() error {
+	return p.w.Close()
+}
+
+// KafkaConsumer processes order events, continuing the producer's trace
+type KafkaConsumer struct {
+	r   *kafka.Reader
+	sdk *tracekit.SDK
+}
+
+// NewKafkaConsumer creates a consumer group member for topic
+func NewKafkaConsumer(sdk *tracekit.SDK, brokers []string, topic, groupID string) *KafkaConsumer {
+	return &KafkaConsumer{
+		r: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: brokers,
+			Topic:   topic,
+			GroupID: groupID,
+		}),
+		sdk: sdk,
+	}
+}
+
+// Run consumes messages until ctx is canceled
+func (c *KafkaConsumer) Run(ctx context.Context) {
+	defer c.r.Close()
+
+	for {
+		msg, err := c.r.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			zap.L().Warn("Kafka fetch failed", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		c.process(msg)
+
+		if err := c.r.CommitMessages(ctx, msg); err != nil && !errors.Is(err, context.Canceled) {
+			zap.L().Warn("Kafka commit failed", zap.Error(err), zap.Int64("offset", msg.Offset))
+		}
+	}
+}
+
+// process handles one message inside a CONSUMER span whose parent is the
+// PRODUCER span carried in the message headers
+func (c *KafkaConsumer) process(msg kafka.Message) {
+	ctx := tracing.Extract(context.Background(), kafkaHeaderCarrier{&msg.Headers})
+
+	ctx, span := tracing.Tracer().Start(ctx, msg.Topic+" process", trace.WithSpanKind(trace.SpanKindConsumer))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "kafka"),
+		attribute.String("messaging.operation", "process"),
+		attribute.String("messaging.destination.name", msg.Topic),
+		attribute.Int("messaging.kafka.destination.partition", msg.Partition),
+		attribute.Int64("messaging.kafka.message.offset", msg.Offset),
+		attribute.String("messaging.kafka.message.key", string(msg.Key)),
+	)
+
+	headers := kafkaHeaderCarrier{&msg.Headers}
+	order := new(pb.Order)
+	if schema := headers.Get(schemaHeader); schema != "" && schema != pb.Schema(order) {
+		c.sdk.RecordError(span, fmt.Errorf("unexpected message schema %q", schema))
+		return
+	}
+	if err := pb.Unmarshal(ctx, c.sdk, pb.FormatOf(headers.Get(contentTypeHeader)), msg.Value, order); err != nil {
+		c.sdk.RecordError(span, err)
+		return
+	}
+
+	c.sdk.AddAttribute(span, "order.id", order.GetOrderId())
+	c.sdk.AddEvent(span, "order.fulfillment.started")
+
+	// Simulate fulfilment work as a child span of the CONSUMER span
+	_, work := c.sdk.StartSpan(ctx, "fulfillOrder")
+	time.Sleep(40 * time.Millisecond)
+	c.sdk.SetSuccess(work)
+	work.End()
+
+	c.sdk.AddEvent(span, "order.fulfillment.completed")
+	c.sdk.SetSuccess(span)
+}
+
+// kafkaHeaderCarrier adapts Kafka message headers to a TextMapCarrier
+type kafkaHeaderCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c kafkaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c kafkaHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c kafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, h := range *c.headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
@@ -0,0 +1,4 @@
+// Package messaging shows trace context propagation over message brokers.
+// Producers inject the context into message headers and consumers extract
+// it, so the consumer's span continues the producer's trace.
+package messaging
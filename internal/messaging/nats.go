@@ -0,0 +1,153 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// StockRequest asks whether quantity units of SKU are in stock
+type StockRequest struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// StockReply answers a StockRequest
+type StockReply struct {
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	Available int    `json:"available"`
+	InStock   bool   `json:"in_stock"`
+}
+
+// NATSRPC is a request/reply RPC over NATS. Request injects the trace
+// context into the message headers and the responder continues the trace.
+type NATSRPC struct {
+	nc      *nats.Conn
+	sdk     *tracekit.SDK
+	subject string
+}
+
+// NewNATSRPC connects to the NATS server at url
+func NewNATSRPC(sdk *tracekit.SDK, url, subject string) (*NATSRPC, error) {
+	nc, err := nats.Connect(url, nats.Name("go-test-app"))
+	if err != nil {
+		return nil, err
+	}
+	return &NATSRPC{nc: nc, sdk: sdk, subject: subject}, nil
+}
+
+// Serve subscribes the stock responder to the subject. Instances share a
+// queue group so each request is answered once.
+func (r *NATSRPC) Serve() error {
+	_, err := r.nc.QueueSubscribe(r.subject, "go-test-app", r.respond)
+	return err
+}
+
+// Request sends req inside a CLIENT span and waits for the reply
+func (r *NATSRPC) Request(ctx context.Context, req StockRequest) (StockReply, error) {
+	ctx, span := tracing.Tracer().Start(ctx, r.subject+" request", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "nats"),
+		attribute.String("messaging.operation", "request"),
+		attribute.String("messaging.destination.name", r.subject),
+	)
+
+	var reply StockReply
+	data, err := json.Marshal(req)
+	if err != nil {
+		r.sdk.RecordError(span, err)
+		return reply, err
+	}
+
+	msg := nats.NewMsg(r.subject)
+	msg.Data = data
+	tracing.Inject(ctx, headerCarrier(msg.Header))
+
+	start := time.Now()
+	resp, err := r.nc.RequestMsgWithContext(ctx, msg)
+	if err != nil {
+		r.sdk.RecordError(span, err)
+		return reply, err
+	}
+	r.sdk.AddFloatAttribute(span, "messaging.nats.round_trip_ms", float64(time.Since(start).Microseconds())/1000)
+
+	if err := json.Unmarshal(resp.Data, &reply); err != nil {
+		r.sdk.RecordError(span, err)
+		return reply, err
+	}
+
+	r.sdk.SetSuccess(span)
+	return reply, nil
+}
+
+// respond answers one request inside a SERVER span whose parent is the
+// requester's CLIENT span carried in the message headers
+func (r *NATSRPC) respond(msg *nats.Msg) {
+	ctx := tracing.Extract(context.Background(), headerCarrier(msg.Header))
+
+	ctx, span := tracing.Tracer().Start(ctx, msg.Subject+" process", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "nats"),
+		attribute.String("messaging.operation", "process"),
+		attribute.String("messaging.destination.name", msg.Subject),
+		attribute.Int("messaging.message.body.size", len(msg.Data)),
+	)
+
+	var req StockRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		// Leave it unanswered; the requester's context times out
+		r.sdk.RecordError(span, err)
+		return
+	}
+	r.sdk.AddAttribute(span, "inventory.sku", req.SKU)
+
+	// Simulate a stock lookup as a child span of the SERVER span
+	_, lookup := r.sdk.StartSpan(ctx, "lookupStock")
+	time.Sleep(15 * time.Millisecond)
+	available := 50 + len(req.SKU)*7%50
+	r.sdk.AddIntAttribute(lookup, "inventory.available", int64(available))
+	r.sdk.SetSuccess(lookup)
+	lookup.End()
+
+	data, err := json.Marshal(StockReply{
+		SKU:       req.SKU,
+		Quantity:  req.Quantity,
+		Available: available,
+		InStock:   req.Quantity <= available,
+	})
+	if err != nil {
+		r.sdk.RecordError(span, err)
+		return
+	}
+
+	if err := msg.Respond(data); err != nil {
+		r.sdk.RecordError(span, err)
+		return
+	}
+	r.sdk.SetSuccess(span)
+}
+
+// Close drains in-flight requests and closes the connection
+func (r *NATSRPC) Close() error {
+	return r.nc.Drain()
+}
+
+// headerCarrier adapts NATS headers, which share http.Header's shape, to a
+// TextMapCarrier
+func headerCarrier(h nats.Header) propagation.HeaderCarrier {
+	return propagation.HeaderCarrier(http.Header(h))
+}
@@ -0,0 +1,213 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// Task is a unit of work enqueued by /api/enqueue or /api/enqueue-sqs
+type Task struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+
+	// FailOnce makes the first delivery fail so the redelivery shows up
+	// as a second CONSUMER span with messaging.rabbitmq.redelivered=true
+	FailOnce bool `json:"fail_once,omitempty"`
+}
+
+// errTaskFailed is the simulated failure of a FailOnce task
+var errTaskFailed = errors.New("task failed on first delivery")
+
+// RabbitMQ publishes tasks to a queue and consumes them, carrying the trace
+// context in the AMQP message headers
+type RabbitMQ struct {
+	conn  *amqp.Connection
+	ch    *amqp.Channel
+	sdk   *tracekit.SDK
+	queue string
+}
+
+// NewRabbitMQ connects to the broker at url and declares queue
+func NewRabbitMQ(sdk *tracekit.SDK, url, queue string) (*RabbitMQ, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &RabbitMQ{conn: conn, ch: ch, sdk: sdk, queue: queue}, nil
+}
+
+// Publish sends task to the queue inside a PRODUCER span
+func (r *RabbitMQ) Publish(ctx context.Context, task Task) error {
+	ctx, span := tracing.Tracer().Start(ctx, r.queue+" publish", trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "rabbitmq"),
+		attribute.String("messaging.operation", "publish"),
+		attribute.String("messaging.destination.name", r.queue),
+		attribute.String("messaging.rabbitmq.destination.routing_key", r.queue),
+		attribute.String("messaging.message.id", task.ID),
+	)
+
+	body, err := json.Marshal(task)
+	if err != nil {
+		r.sdk.RecordError(span, err)
+		return err
+	}
+
+	msg := amqp.Publishing{
+		Headers:      amqp.Table{},
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    task.ID,
+		Body:         body,
+	}
+	tracing.Inject(ctx, amqpHeaderCarrier(msg.Headers))
+
+	// The default exchange routes by queue name
+	if err := r.ch.PublishWithContext(ctx, "", r.queue, false, false, msg); err != nil {
+		r.sdk.RecordError(span, err)
+		return err
+	}
+
+	r.sdk.AddIntAttribute(span, "messaging.message.body.size", int64(len(body)))
+	r.sdk.SetSuccess(span)
+	return nil
+}
+
+// Consume processes deliveries on a channel of its own until ctx is canceled
+func (r *RabbitMQ) Consume(ctx context.Context) {
+	ch, err := r.conn.Channel()
+	if err != nil {
+		zap.L().Error("RabbitMQ channel failed", zap.Error(err))
+		return
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(10, 0, false); err != nil {
+		zap.L().Error("RabbitMQ QoS failed", zap.Error(err))
+		return
+	}
+	deliveries, err := ch.Consume(r.queue, "go-test-app", false, false, false, false, nil)
+	if err != nil {
+		zap.L().Error("RabbitMQ consume failed", zap.Error(err))
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d, ok := <-deliveries:
+			if !ok {
+				zap.L().Warn("RabbitMQ delivery channel closed")
+				return
+			}
+			r.process(d)
+		}
+	}
+}
+
+// process handles one delivery inside a CONSUMER span whose parent is the
+// PRODUCER span carried in the message headers, then acks or requeues it
+func (r *RabbitMQ) process(d amqp.Delivery) {
+	if d.Headers == nil {
+		d.Headers = amqp.Table{}
+	}
+	ctx := tracing.Extract(context.Background(), amqpHeaderCarrier(d.Headers))
+
+	ctx, span := tracing.Tracer().Start(ctx, r.queue+" process", trace.WithSpanKind(trace.SpanKindConsumer))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "rabbitmq"),
+		attribute.String("messaging.operation", "process"),
+		attribute.String("messaging.destination.name", r.queue),
+		attribute.String("messaging.message.id", d.MessageId),
+		attribute.Int64("messaging.rabbitmq.delivery_tag", int64(d.DeliveryTag)),
+		attribute.Bool("messaging.rabbitmq.redelivered", d.Redelivered),
+	)
+
+	var task Task
+	if err := json.Unmarshal(d.Body, &task); err != nil {
+		// A message that can't be decoded never will be; drop it
+		r.sdk.RecordError(span, err)
+		d.Nack(false, false)
+		return
+	}
+	r.sdk.AddAttribute(span, "task.type", task.Type)
+
+	if err := r.run(ctx, task, d.Redelivered); err != nil {
+		r.sdk.RecordError(span, err)
+		r.sdk.AddEvent(span, "message.requeued")
+		d.Nack(false, true)
+		return
+	}
+
+	if err := d.Ack(false); err != nil {
+		r.sdk.RecordError(span, err)
+		return
+	}
+	r.sdk.AddEvent(span, "message.acked")
+	r.sdk.SetSuccess(span)
+}
+
+// run simulates the task as a child span of the CONSUMER span
+func (r *RabbitMQ) run(ctx context.Context, task Task, redelivered bool) error {
+	_, span := r.sdk.StartSpan(ctx, fmt.Sprintf("runTask %s", task.Type))
+	defer span.End()
+
+	time.Sleep(30 * time.Millisecond)
+	if task.FailOnce && !redelivered {
+		r.sdk.RecordError(span, errTaskFailed)
+		return errTaskFailed
+	}
+	r.sdk.SetSuccess(span)
+	return nil
+}
+
+// Close closes the publishing channel and the connection
+func (r *RabbitMQ) Close() error {
+	r.ch.Close()
+	return r.conn.Close()
+}
+
+// amqpHeaderCarrier adapts AMQP message headers to a TextMapCarrier
+type amqpHeaderCarrier amqp.Table
+
+func (c amqpHeaderCarrier) Get(key string) string {
+	v, _ := c[key].(string)
+	return v
+}
+
+func (c amqpHeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c amqpHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,175 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// SQSQueue sends tasks to an SQS queue and polls them back, carrying the
+// trace context in the message attributes
+type SQSQueue struct {
+	client   *sqs.Client
+	sdk      *tracekit.SDK
+	queueURL string
+	name     string
+}
+
+// NewSQSQueue creates a client for queueURL using the default AWS credential
+// chain. A non-empty endpoint overrides the AWS endpoint, e.g. LocalStack.
+func NewSQSQueue(ctx context.Context, sdk *tracekit.SDK, queueURL, endpoint string) (*SQSQueue, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+	return &SQSQueue{client: client, sdk: sdk, queueURL: queueURL, name: path.Base(queueURL)}, nil
+}
+
+// Send enqueues task inside a PRODUCER span
+func (q *SQSQueue) Send(ctx context.Context, task Task) error {
+	ctx, span := tracing.Tracer().Start(ctx, q.name+" send", trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "aws_sqs"),
+		attribute.String("messaging.operation", "publish"),
+		attribute.String("messaging.destination.name", q.name),
+		attribute.String("messaging.url", q.queueURL),
+	)
+
+	body, err := json.Marshal(task)
+	if err != nil {
+		q.sdk.RecordError(span, err)
+		return err
+	}
+
+	attrs := map[string]types.MessageAttributeValue{}
+	tracing.Inject(ctx, sqsAttributeCarrier(attrs))
+
+	out, err := q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:          aws.String(q.queueURL),
+		MessageBody:       aws.String(string(body)),
+		MessageAttributes: attrs,
+	})
+	if err != nil {
+		q.sdk.RecordError(span, err)
+		return err
+	}
+
+	q.sdk.AddAttribute(span, "messaging.message.id", aws.ToString(out.MessageId))
+	q.sdk.AddIntAttribute(span, "messaging.message.body.size", int64(len(body)))
+	q.sdk.SetSuccess(span)
+	return nil
+}
+
+// Poll long-polls the queue and processes messages until ctx is canceled
+func (q *SQSQueue) Poll(ctx context.Context) {
+	for {
+		out, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:                    aws.String(q.queueURL),
+			MaxNumberOfMessages:         10,
+			WaitTimeSeconds:             10,
+			MessageAttributeNames:       []string{"All"},
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameApproximateReceiveCount},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			zap.L().Warn("SQS receive failed", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, msg := range out.Messages {
+			q.process(msg)
+		}
+	}
+}
+
+// process handles one message inside a CONSUMER span whose parent is the
+// PRODUCER span carried in the message attributes. Messages that fail are
+// left on the queue and come back after the visibility timeout.
+func (q *SQSQueue) process(msg types.Message) {
+	ctx := tracing.Extract(context.Background(), sqsAttributeCarrier(msg.MessageAttributes))
+
+	ctx, span := tracing.Tracer().Start(ctx, q.name+" process", trace.WithSpanKind(trace.SpanKindConsumer))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("messaging.system", "aws_sqs"),
+		attribute.String("messaging.operation", "process"),
+		attribute.String("messaging.destination.name", q.name),
+		attribute.String("messaging.message.id", aws.ToString(msg.MessageId)),
+	)
+	if count, err := strconv.Atoi(msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]); err == nil {
+		q.sdk.AddIntAttribute(span, "messaging.aws_sqs.receive_count", int64(count))
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(aws.ToString(msg.Body)), &task); err != nil {
+		// Left for the queue's redrive policy to move to a dead-letter queue
+		q.sdk.RecordError(span, err)
+		return
+	}
+	q.sdk.AddAttribute(span, "task.type", task.Type)
+
+	// Simulate the work as a child span of the CONSUMER span
+	_, work := q.sdk.StartSpan(ctx, "runTask "+task.Type)
+	time.Sleep(30 * time.Millisecond)
+	q.sdk.SetSuccess(work)
+	work.End()
+
+	if _, err := q.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(q.queueURL),
+		ReceiptHandle: msg.ReceiptHandle,
+	}); err != nil {
+		q.sdk.RecordError(span, err)
+		return
+	}
+	q.sdk.AddEvent(span, "message.deleted")
+	q.sdk.SetSuccess(span)
+}
+
+// sqsAttributeCarrier adapts SQS message attributes to a TextMapCarrier
+type sqsAttributeCarrier map[string]types.MessageAttributeValue
+
+func (c sqsAttributeCarrier) Get(key string) string {
+	if v, ok := c[key]; ok {
+		return aws.ToString(v.StringValue)
+	}
+	return ""
+}
+
+func (c sqsAttributeCarrier) Set(key, value string) {
+	c[key] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c sqsAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,220 @@
+// Package mocks runs in-process stand-ins for the downstream services in the
+// service topology so the cross-service demos work without the other apps.
+//
+// Each mock continues the caller's trace with a SERVER span. The spans are
+// exported under this app's service name and carry a mock.service attribute
+// naming the service they stand in for, and mock.replica naming which of
+// its replicas answered.
+package mocks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// mock is one stand-in service, with a server per replica
+type mock struct {
+	svc     clients.Service
+	servers []*httptest.Server
+}
+
+// Servers are the running mock services
+type Servers struct {
+	mocks []*mock
+	http  *http.Client
+	self  clients.Service
+}
+
+// Listen reserves a local port for a mock of every service. Call Services
+// to point the app at them and Start once the SDK is initialized.
+func Listen(services clients.Services) *Servers {
+	m := &Servers{}
+	for _, svc := range services.All() {
+		mk := &mock{svc: svc}
+		for range max(len(svc.Replicas), 1) {
+			mk.servers = append(mk.servers, httptest.NewUnstartedServer(nil))
+		}
+		m.mocks = append(m.mocks, mk)
+	}
+	return m
+}
+
+// Services describes the mocks as the downstream services they stand in for
+func (m *Servers) Services() clients.Services {
+	var services clients.Services
+	for _, mk := range m.mocks {
+		services.Downstream = append(services.Downstream, mk.service())
+	}
+	return services
+}
+
+// Start serves the mocks. The Node mock (route "node") also serves the
+// inventory saga steps and calls back into self during /api/chain, using the
+// SDK's instrumented client; the Python mock serves the payment steps.
+func (m *Servers) Start(sdk *tracekit.SDK, self clients.Service) {
+	m.http = sdk.HTTPClient(nil)
+	m.self = self
+
+	for _, mk := range m.mocks {
+		for replica, server := range mk.servers {
+			mux := http.NewServeMux()
+			mux.Handle("GET /api/data", m.traced(mk, replica, "/api/data", m.data(mk)))
+			switch mk.svc.Route {
+			case "node":
+				mux.Handle("GET /api/call-go", m.traced(mk, replica, "/api/call-go", m.callGo(mk)))
+				mux.Handle("POST /api/inventory/reserve", m.traced(mk, replica, "/api/inventory/reserve", m.sagaStep(mk, "reserved", 409)))
+				mux.Handle("POST /api/inventory/release", m.traced(mk, replica, "/api/inventory/release", m.sagaStep(mk, "released", 0)))
+			case "python":
+				mux.Handle("POST /api/payments/charge", m.traced(mk, replica, "/api/payments/charge", m.sagaStep(mk, "charged", 402)))
+				mux.Handle("POST /api/payments/refund", m.traced(mk, replica, "/api/payments/refund", m.sagaStep(mk, "refunded", 0)))
+			}
+			server.Config.Handler = mux
+			if mk.svc.Protocol == "h2c" {
+				server.Config.Protocols = new(http.Protocols)
+				server.Config.Protocols.SetHTTP1(true)
+				server.Config.Protocols.SetUnencryptedHTTP2(true)
+			}
+			server.Start()
+		}
+	}
+}
+
+// Close stops every mock
+func (m *Servers) Close() {
+	for _, mk := range m.mocks {
+		for _, server := range mk.servers {
+			server.Close()
+		}
+	}
+}
+
+// service is the service the mock stands in for, at the mock's addresses
+func (mk *mock) service() clients.Service {
+	svc := mk.svc
+	svc.Replicas = nil
+	for _, server := range mk.servers {
+		svc.Replicas = append(svc.Replicas, "http://"+server.Listener.Addr().String())
+	}
+	svc.URL = svc.Replicas[0]
+	return svc
+}
+
+// handlerFunc is a mock endpoint running inside its SERVER span
+type handlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request) error
+
+// traced continues the incoming trace with a SERVER span around fn
+func (m *Servers) traced(mk *mock, replica int, route string, fn handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Tracer().Start(ctx, r.Method+" "+route, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("http.route", route),
+			attribute.String("mock.service", mk.svc.Name),
+			attribute.String("mock.language", mk.svc.Language),
+			attribute.Int("mock.replica", replica),
+		)
+
+		if err := fn(ctx, w, r); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			writeJSON(w, 502, map[string]interface{}{"service": mk.svc.Name, "error": err.Error(), "mock": true})
+		}
+	})
+}
+
+// data mimics /api/data on every test service
+func (m *Servers) data(mk *mock) handlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		time.Sleep(time.Duration(10+rand.Intn(40)) * time.Millisecond)
+		writeJSON(w, 200, map[string]interface{}{
+			"service":   mk.svc.Name,
+			"language":  mk.svc.Language,
+			"timestamp": time.Now().Format(time.RFC3339),
+			"data":      map[string]interface{}{"random_value": rand.Intn(100)},
+			"mock":      true,
+		})
+		return nil
+	}
+}
+
+// callGo mimics the Node service's /api/call-go, which calls back into
+// this app's /api/internal to complete the Go -> Node -> Go chain
+func (m *Servers) callGo(mk *mock) handlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", m.self.URL+"/api/internal", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := m.http.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		var decoded map[string]interface{}
+		json.Unmarshal(body, &decoded)
+
+		writeJSON(w, 200, map[string]interface{}{
+			"service":     mk.svc.Name,
+			"go_response": decoded,
+			"mock":        true,
+		})
+		return nil
+	}
+}
+
+// sagaStep mimics one step of the order saga. A request body with
+// "fail": true is rejected with failStatus, unless failStatus is zero.
+func (m *Servers) sagaStep(mk *mock, result string, failStatus int) handlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var body struct {
+			OrderID string `json:"order_id"`
+			Fail    bool   `json:"fail"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		time.Sleep(time.Duration(10+rand.Intn(30)) * time.Millisecond)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("order.id", body.OrderID))
+
+		if body.Fail && failStatus != 0 {
+			writeJSON(w, failStatus, map[string]interface{}{
+				"service":  mk.svc.Name,
+				"order_id": body.OrderID,
+				"error":    "rejected by " + mk.svc.Name,
+				"mock":     true,
+			})
+			return nil
+		}
+		writeJSON(w, 200, map[string]interface{}{
+			"service":  mk.svc.Name,
+			"order_id": body.OrderID,
+			"status":   result,
+			"mock":     true,
+		})
+		return nil
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
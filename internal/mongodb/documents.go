@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// documentsCollection holds the documents served by /api/documents
+const documentsCollection = "documents"
+
+// ErrNotFound is returned when no document has the requested ID
+var ErrNotFound = errors.New("document not found")
+
+// Document is a free-form note stored in MongoDB
+type Document struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Title     string             `bson:"title" json:"title"`
+	Body      string             `bson:"body" json:"body"`
+	Tags      []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// DocumentUpdate lists the fields of a Document that can be changed;
+// nil fields are left alone
+type DocumentUpdate struct {
+	Title *string   `json:"title"`
+	Body  *string   `json:"body"`
+	Tags  *[]string `json:"tags"`
+}
+
+func (s *Store) documents() *mongo.Collection {
+	return s.db.Collection(documentsCollection)
+}
+
+// CreateDocument inserts doc and returns it with its new ID
+func (s *Store) CreateDocument(ctx context.Context, doc Document) (Document, error) {
+	now := time.Now().UTC()
+	doc.ID = primitive.NewObjectID()
+	doc.CreatedAt, doc.UpdatedAt = now, now
+
+	if _, err := s.documents().InsertOne(ctx, doc); err != nil {
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+// ListDocuments returns up to limit documents, newest first
+func (s *Store) ListDocuments(ctx context.Context, limit int) ([]Document, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := s.documents().Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []Document{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// GetDocument returns the document with the given hex ID
+func (s *Store) GetDocument(ctx context.Context, id string) (Document, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Document{}, ErrNotFound
+	}
+
+	var doc Document
+	err = s.documents().FindOne(ctx, bson.D{{Key: "_id", Value: oid}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Document{}, ErrNotFound
+	}
+	return doc, err
+}
+
+// UpdateDocument applies update to the document with the given hex ID and
+// returns the result
+func (s *Store) UpdateDocument(ctx context.Context, id string, update DocumentUpdate) (Document, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Document{}, ErrNotFound
+	}
+
+	set := bson.D{{Key: "updated_at", Value: time.Now().UTC()}}
+	if update.Title != nil {
+		set = append(set, bson.E{Key: "title", Value: *update.Title})
+	}
+	if update.Body != nil {
+		set = append(set, bson.E{Key: "body", Value: *update.Body})
+	}
+	if update.Tags != nil {
+		set = append(set, bson.E{Key: "tags", Value: *update.Tags})
+	}
+
+	var doc Document
+	err = s.documents().FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: oid}},
+		bson.D{{Key: "$set", Value: set}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Document{}, ErrNotFound
+	}
+	return doc, err
+}
+
+// DeleteDocument removes the document with the given hex ID
+func (s *Store) DeleteDocument(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNotFound
+	}
+
+	result, err := s.documents().DeleteOne(ctx, bson.D{{Key: "_id", Value: oid}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
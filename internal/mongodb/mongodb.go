@@ -0,0 +1,116 @@
+// Package mongodb connects to MongoDB with a command monitor that turns every
+// command the driver sends into a DB span with the collection, operation,
+// and duration.
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Store is a MongoDB database whose commands are traced
+type Store struct {
+	client *mongo.Client
+	db     *mongo.Database
+	sdk    *tracekit.SDK
+}
+
+// Open connects to the MongoDB deployment at uri, uses database name, and
+// verifies the connection
+func Open(ctx context.Context, sdk *tracekit.SDK, uri, name string) (*Store, error) {
+	opts := options.Client().
+		ApplyURI(uri).
+		SetMonitor(newMonitor(sdk)).
+		SetServerSelectionTimeout(5 * time.Second)
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+
+	return &Store{client: client, db: client.Database(name), sdk: sdk}, nil
+}
+
+// Close disconnects from MongoDB
+func (s *Store) Close(ctx context.Context) error {
+	return s.client.Disconnect(ctx)
+}
+
+// monitor keeps the span of every in-flight command until the driver reports
+// its outcome
+type monitor struct {
+	sdk   *tracekit.SDK
+	spans sync.Map // commandKey -> trace.Span
+}
+
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+func newMonitor(sdk *tracekit.SDK) *event.CommandMonitor {
+	m := &monitor{sdk: sdk}
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+// started opens a span named after the command, e.g. mongodb.find
+func (m *monitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	_, span := m.sdk.StartSpan(ctx, "mongodb."+evt.CommandName)
+	m.sdk.AddAttribute(span, "db.system", "mongodb")
+	m.sdk.AddAttribute(span, "db.name", evt.DatabaseName)
+	m.sdk.AddAttribute(span, "db.operation", evt.CommandName)
+
+	// The first element of a command document names its collection,
+	// e.g. {"find": "documents", "filter": {...}}
+	if collection, ok := evt.Command.Index(0).Value().StringValueOK(); ok {
+		m.sdk.AddAttribute(span, "db.mongodb.collection", collection)
+	}
+
+	m.spans.Store(commandKey{evt.ConnectionID, evt.RequestID}, span)
+}
+
+func (m *monitor) succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	span, ok := m.finish(evt.CommandFinishedEvent)
+	if !ok {
+		return
+	}
+	m.sdk.SetSuccess(span)
+	span.End()
+}
+
+func (m *monitor) failed(_ context.Context, evt *event.CommandFailedEvent) {
+	span, ok := m.finish(evt.CommandFinishedEvent)
+	if !ok {
+		return
+	}
+	m.sdk.RecordError(span, fmt.Errorf("%s failed: %s", evt.CommandName, evt.Failure))
+	span.End()
+}
+
+// finish looks up the command's span and records its duration
+func (m *monitor) finish(evt event.CommandFinishedEvent) (trace.Span, bool) {
+	value, ok := m.spans.LoadAndDelete(commandKey{evt.ConnectionID, evt.RequestID})
+	if !ok {
+		return nil, false
+	}
+	span := value.(trace.Span)
+	m.sdk.AddFloatAttribute(span, "db.duration_ms", float64(evt.Duration.Microseconds())/1000)
+	return span, true
+}
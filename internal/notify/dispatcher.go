@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"golang.org/x/sync/errgroup"
+)
+
+// Dispatch outcomes recorded as notify.status
+const (
+	StatusDelivered = "delivered"
+	StatusPartial   = "partial"
+	StatusFailed    = "failed"
+)
+
+// Channels are the simulated delivery channels, with the provider each
+// stands in for
+var Channels = map[string]string{
+	"sms":   "twilio-stub",
+	"push":  "fcm-stub",
+	"email": "smtp-stub",
+}
+
+// channelOrder keeps results in a stable order
+var channelOrder = []string{"sms", "push", "email"}
+
+// Delivery is the outcome on one channel
+type Delivery struct {
+	Channel    string `json:"channel"`
+	Provider   string `json:"provider"`
+	Delivered  bool   `json:"delivered"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Summary is the outcome of a dispatch across every channel
+type Summary struct {
+	Status     string     `json:"status"`
+	Delivered  int        `json:"delivered"`
+	Failed     int        `json:"failed"`
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Dispatcher fans a notification out to every channel at once
+type Dispatcher struct {
+	sdk *tracekit.SDK
+}
+
+// NewDispatcher creates a dispatcher
+func NewDispatcher(sdk *tracekit.SDK) *Dispatcher {
+	return &Dispatcher{sdk: sdk}
+}
+
+// Send delivers message to userID on every channel concurrently, each in its
+// own child span. The channel named by fail fails; an empty fail picks one
+// at random and "none" lets every channel succeed. One channel failing
+// doesn't stop the others.
+func (d *Dispatcher) Send(ctx context.Context, userID, message, fail string) Summary {
+	ctx, span := d.sdk.StartSpan(ctx, "notify.dispatch")
+	defer span.End()
+
+	if fail == "" {
+		fail = channelOrder[rand.Intn(len(channelOrder))]
+	}
+
+	d.sdk.AddAttribute(span, "notify.user_id", userID)
+	d.sdk.AddIntAttribute(span, "notify.channels", int64(len(channelOrder)))
+
+	deliveries := make([]Delivery, len(channelOrder))
+	var g errgroup.Group
+	for i, channel := range channelOrder {
+		g.Go(func() error {
+			deliveries[i] = d.deliver(ctx, channel, message, channel == fail)
+			return nil
+		})
+	}
+	g.Wait()
+
+	sum := Summary{Deliveries: deliveries}
+	for _, dl := range deliveries {
+		if dl.Delivered {
+			sum.Delivered++
+		} else {
+			sum.Failed++
+		}
+	}
+	switch {
+	case sum.Failed == 0:
+		sum.Status = StatusDelivered
+	case sum.Delivered == 0:
+		sum.Status = StatusFailed
+	default:
+		sum.Status = StatusPartial
+	}
+
+	d.sdk.AddIntAttribute(span, "notify.delivered", int64(sum.Delivered))
+	d.sdk.AddIntAttribute(span, "notify.failed", int64(sum.Failed))
+	d.sdk.AddAttribute(span, "notify.status", sum.Status)
+
+	// A partial dispatch still reached the user, so only a total failure
+	// marks the parent span as an error
+	if sum.Status == StatusFailed {
+		d.sdk.RecordError(span, errors.New("notification failed on every channel"))
+	} else {
+		d.sdk.SetSuccess(span)
+	}
+	return sum
+}
+
+// deliver sends on one channel in a "notify.<channel>" span
+func (d *Dispatcher) deliver(ctx context.Context, channel, message string, fail bool) Delivery {
+	_, span := d.sdk.StartSpan(ctx, "notify."+channel)
+	defer span.End()
+
+	provider := Channels[channel]
+	d.sdk.AddAttribute(span, "notify.channel", channel)
+	d.sdk.AddAttribute(span, "notify.provider", provider)
+	d.sdk.AddIntAttribute(span, "notify.message_length", int64(len(message)))
+
+	start := time.Now()
+	time.Sleep(time.Duration(20+rand.Intn(120)) * time.Millisecond)
+	dl := Delivery{Channel: channel, Provider: provider, DurationMs: time.Since(start).Milliseconds()}
+
+	if fail {
+		err := fmt.Errorf("%s: provider unavailable", provider)
+		d.sdk.RecordError(span, err)
+		dl.Error = err.Error()
+		return dl
+	}
+
+	dl.Delivered = true
+	d.sdk.SetSuccess(span)
+	return dl
+}
@@ -0,0 +1,123 @@
+// Package notify sends simulated customer notifications. Emails are queued
+// and sent by a background goroutine in their own trace, linked back to the
+// request that queued them.
+package notify
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// ErrQueueFull is returned by Enqueue when the send queue is full
+var ErrQueueFull = errors.New("email queue is full")
+
+// Email is a queued message. Only the recipient's domain is ever recorded
+// on spans, never the address.
+type Email struct {
+	To       string
+	Template string
+	OrderID  string
+
+	enqueued time.Time
+
+	// origin is the span that queued the email
+	origin trace.SpanContext
+}
+
+// Mailer sends queued emails one at a time
+type Mailer struct {
+	sdk   *tracekit.SDK
+	queue chan Email
+}
+
+// NewMailer creates a mailer with room for queueSize pending emails
+func NewMailer(sdk *tracekit.SDK, queueSize int) *Mailer {
+	return &Mailer{sdk: sdk, queue: make(chan Email, queueSize)}
+}
+
+// Enqueue queues e, remembering the span in ctx so the send can link back
+// to it. It never blocks the request.
+func (m *Mailer) Enqueue(ctx context.Context, e Email) error {
+	e.enqueued = time.Now()
+	e.origin = trace.SpanContextFromContext(ctx)
+
+	select {
+	case m.queue <- e:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run sends queued emails until ctx is canceled
+func (m *Mailer) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-m.queue:
+			m.send(e)
+		}
+	}
+}
+
+// send delivers e in a new root span linked to the request that queued it
+func (m *Mailer) send(e Email) {
+	opts := []trace.SpanStartOption{trace.WithNewRoot()}
+	if e.origin.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{
+			SpanContext: e.origin,
+			Attributes:  []attribute.KeyValue{attribute.String("link.type", "enqueued_by")},
+		}))
+	}
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "email.send", opts...)
+	defer span.End()
+
+	domain := Domain(e.To)
+	m.sdk.AddAttribute(span, "email.recipient_domain", domain)
+	m.sdk.AddAttribute(span, "email.template", e.Template)
+	m.sdk.AddAttribute(span, "email.provider", "stub")
+	m.sdk.AddIntAttribute(span, "email.queue_wait_ms", time.Since(e.enqueued).Milliseconds())
+	if e.OrderID != "" {
+		m.sdk.AddAttribute(span, "order.id", e.OrderID)
+	}
+
+	_, render := m.sdk.StartSpan(ctx, "email.render")
+	m.sdk.AddAttribute(render, "email.template", e.Template)
+	time.Sleep(time.Duration(5+rand.Intn(10)) * time.Millisecond)
+	m.sdk.SetSuccess(render)
+	render.End()
+
+	_, deliver := m.sdk.StartSpan(ctx, "email.deliver")
+	m.sdk.AddAttribute(deliver, "email.recipient_domain", domain)
+	time.Sleep(time.Duration(50+rand.Intn(150)) * time.Millisecond)
+	m.sdk.SetSuccess(deliver)
+	deliver.End()
+
+	m.sdk.SetSuccess(span)
+	logging.FromContext(ctx).Info("📧 Email sent",
+		zap.String("email.template", e.Template),
+		zap.String("email.recipient_domain", domain),
+		zap.String("order.id", e.OrderID),
+	)
+}
+
+// Domain returns the domain part of an email address
+func Domain(addr string) string {
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		return strings.ToLower(addr[i+1:])
+	}
+	return "unknown"
+}
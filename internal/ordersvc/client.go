@@ -0,0 +1,58 @@
+package ordersvc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+// Client calls OrderService
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a client for the OrderService at target.
+// The otelgrpc stats handler creates a CLIENT span per call, or per stream,
+// with a message event per message sent and received, and injects the trace
+// context into the outgoing metadata.
+func NewClient(target string) (*Client, error) {
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+		)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// GetOrder calls OrderService.GetOrder
+func (c *Client) GetOrder(ctx context.Context, req *pb.GetOrderRequest, opts ...grpc.CallOption) (*pb.Order, error) {
+	out := new(pb.Order)
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/GetOrder", req, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrackOrders opens an OrderService.TrackOrders stream. The stream ends
+// when ctx is done or the server has answered every request sent before
+// CloseSend.
+func (c *Client) TrackOrders(ctx context.Context) (TrackOrdersClient, error) {
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], "/"+serviceName+"/TrackOrders")
+	if err != nil {
+		return nil, err
+	}
+	return &grpc.GenericClientStream[pb.TrackRequest, pb.StatusUpdate]{ClientStream: stream}, nil
+}
+
+// Close closes the underlying connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
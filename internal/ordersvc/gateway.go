@@ -0,0 +1,81 @@
+package ordersvc
+
+import (
+	"net/http"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+// getOrderPattern maps GET requests onto OrderService.GetOrder, as a
+// google.api.http annotation would
+const getOrderPattern = "/v1/orders/{order_id}"
+
+// gateway translates JSON-over-HTTP requests into OrderService calls
+type gateway struct {
+	sdk    *tracekit.SDK
+	mux    *runtime.ServeMux
+	client *Client
+}
+
+// NewGateway returns a grpc-gateway handler serving OrderService as JSON
+// over HTTP, so the same service answers on both protocols. It calls the
+// service through client, like a gateway generated by
+// protoc-gen-grpc-gateway, and each call runs in a "grpc-gateway <method>"
+// span between the HTTP SERVER span and the gRPC CLIENT span.
+func NewGateway(sdk *tracekit.SDK, client *Client) (http.Handler, error) {
+	// Answer in the same JSON as the rest of the HTTP API
+	mux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions:   pb.JSONOptions,
+		UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+	}))
+	g := &gateway{sdk: sdk, mux: mux, client: client}
+	if err := g.mux.HandlePath("GET", getOrderPattern, g.getOrder); err != nil {
+		return nil, err
+	}
+	return g.mux, nil
+}
+
+func (g *gateway) getOrder(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	const method = "/" + serviceName + "/GetOrder"
+
+	ctx, span := g.sdk.StartSpan(r.Context(), "grpc-gateway GetOrder")
+	defer span.End()
+
+	g.sdk.AddAttribute(span, "rpc.system", "grpc")
+	g.sdk.AddAttribute(span, "rpc.service", serviceName)
+	g.sdk.AddAttribute(span, "rpc.method", "GetOrder")
+	g.sdk.AddAttribute(span, "gateway.http.pattern", getOrderPattern)
+
+	_, outbound := runtime.MarshalerForRequest(g.mux, r)
+
+	// Forwards Grpc-Metadata-* and the standard headers as gRPC metadata
+	ctx, err := runtime.AnnotateContext(ctx, g.mux, r, method, runtime.WithHTTPPathPattern(getOrderPattern))
+	if err != nil {
+		g.sdk.RecordError(span, err)
+		runtime.HTTPError(ctx, g.mux, outbound, w, r, err)
+		return
+	}
+
+	var header, trailer metadata.MD
+	order, err := g.client.GetOrder(ctx, &pb.GetOrderRequest{OrderId: params["order_id"]}, grpc.Header(&header), grpc.Trailer(&trailer))
+	ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: header, TrailerMD: trailer})
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+	if err != nil {
+		g.sdk.RecordError(span, err)
+		runtime.HTTPError(ctx, g.mux, outbound, w, r, err)
+		return
+	}
+
+	g.sdk.AddAttribute(span, "serialization.format", pb.FormatJSON)
+	g.sdk.AddAttribute(span, "serialization.schema", pb.Schema(order))
+	runtime.ForwardResponseMessage(ctx, g.mux, outbound, w, r, order, g.mux.GetForwardResponseOptions()...)
+	g.sdk.SetSuccess(span)
+}
@@ -0,0 +1,60 @@
+package ordersvc
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+// Server implements OrderServiceServer
+type Server struct {
+	sdk *tracekit.SDK
+}
+
+// NewServer creates a gRPC server with OrderService registered.
+// The otelgrpc stats handler extracts the trace context from incoming
+// metadata and creates a SERVER span for every call, or stream, with a
+// message event per message sent and received.
+func NewServer(sdk *tracekit.SDK) *grpc.Server {
+	s := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler(
+		otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+	)))
+	RegisterOrderServiceServer(s, &Server{sdk: sdk})
+	return s
+}
+
+// GetOrder looks up an order
+func (s *Server) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
+	ctx, span := s.sdk.StartSpan(ctx, "lookupOrder")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "order.id", req.GetOrderId())
+
+	if req.GetOrderId() == "" {
+		err := fmt.Errorf("order_id is required")
+		s.sdk.RecordError(span, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	s.sdk.AddEvent(span, "order.loaded")
+	s.sdk.SetSuccess(span)
+
+	return &pb.Order{
+		OrderId:    req.GetOrderId(),
+		CustomerId: "cust-123",
+		Amount:     rand.Float64() * 1000,
+		Status:     "shipped",
+		CreatedAt:  timestamppb.New(time.Now().Add(-48 * time.Hour)),
+	}, nil
+}
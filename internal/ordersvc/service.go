@@ -0,0 +1,77 @@
+// Package ordersvc is a small gRPC OrderService used to demonstrate
+// CLIENT/SERVER gRPC spans alongside the HTTP ones, for unary calls and for
+// a bidirectional stream.
+package ordersvc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+const serviceName = "tracekit.example.OrderService"
+
+// TrackOrdersServer is the server side of a TrackOrders stream
+type TrackOrdersServer = grpc.BidiStreamingServer[pb.TrackRequest, pb.StatusUpdate]
+
+// TrackOrdersClient is the client side of a TrackOrders stream
+type TrackOrdersClient = grpc.BidiStreamingClient[pb.TrackRequest, pb.StatusUpdate]
+
+// OrderServiceServer is implemented by Server
+type OrderServiceServer interface {
+	GetOrder(context.Context, *pb.GetOrderRequest) (*pb.Order, error)
+
+	// TrackOrders streams status updates for every order the client sends
+	// until the client closes its side
+	TrackOrders(TrackOrdersServer) error
+}
+
+// serviceDesc describes OrderService, defined in order.proto, to grpc-go the
+// same way protoc-gen-go-grpc would
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*OrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrder",
+			Handler:    getOrderHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TrackOrders",
+			Handler:       trackOrdersHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// RegisterOrderServiceServer registers srv on s
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+func getOrderHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(pb.GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/GetOrder",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(OrderServiceServer).GetOrder(ctx, req.(*pb.GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func trackOrdersHandler(srv any, stream grpc.ServerStream) error {
+	return srv.(OrderServiceServer).TrackOrders(&grpc.GenericServerStream[pb.TrackRequest, pb.StatusUpdate]{ServerStream: stream})
+}
@@ -0,0 +1,82 @@
+package ordersvc
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Tracekit-Dev/test-app/internal/pb"
+)
+
+// trackingSteps are the statuses every tracked order goes through
+var trackingSteps = []string{"placed", "packed", "shipped", "delivered"}
+
+// stepDelay spaces out the status updates of one order
+const stepDelay = 10 * time.Millisecond
+
+// TrackOrders answers every TrackRequest with the order's status updates.
+// The whole stream is one "trackOrders" span, however many messages it
+// carries; each message is an event on it numbered by stream.seq, instead
+// of a span per message.
+func (s *Server) TrackOrders(stream TrackOrdersServer) error {
+	ctx, span := s.sdk.StartSpan(stream.Context(), "trackOrders")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "rpc.grpc.stream", "bidi")
+
+	received, sent := 0, 0
+	defer func() {
+		s.sdk.AddIntAttribute(span, "stream.messages.received", int64(received))
+		s.sdk.AddIntAttribute(span, "stream.messages.sent", int64(sent))
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			s.sdk.RecordError(span, err)
+			return err
+		}
+		received++
+		span.AddEvent("stream.message.received", trace.WithAttributes(
+			attribute.Int("stream.seq", received),
+			attribute.String("order.id", req.GetOrderId()),
+		))
+		if req.GetOrderId() == "" {
+			err := errors.New("order_id is required")
+			s.sdk.RecordError(span, err)
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		for _, step := range trackingSteps {
+			select {
+			case <-ctx.Done():
+				s.sdk.AddEvent(span, "stream.cancelled")
+				return status.FromContextError(ctx.Err()).Err()
+			case <-time.After(stepDelay):
+			}
+			sent++
+			update := &pb.StatusUpdate{Seq: int32(sent), OrderId: req.GetOrderId(), Status: step, At: timestamppb.Now()}
+			if err := stream.Send(update); err != nil {
+				s.sdk.RecordError(span, err)
+				return err
+			}
+			span.AddEvent("stream.message.sent", trace.WithAttributes(
+				attribute.Int("stream.seq", sent),
+				attribute.String("order.id", req.GetOrderId()),
+				attribute.String("order.status", step),
+			))
+		}
+	}
+
+	s.sdk.SetSuccess(span)
+	return nil
+}
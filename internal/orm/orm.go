@@ -0,0 +1,130 @@
+// Package orm shows GORM with a tracing plugin: every Create, Find, Update,
+// and Delete produces a DB span with the SQL, table, and rows affected.
+package orm
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Store is a GORM connection with the tracing plugin installed
+type Store struct {
+	db *gorm.DB
+}
+
+// Open connects to Postgres at dsn through GORM
+func Open(sdk *tracekit.SDK, dsn string) (*Store, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		// Spans carry the SQL; only log what needs attention
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(&Plugin{sdk: sdk}); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying connection pool
+func (s *Store) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Instance keys used to hand the span from the before to the after callback
+const (
+	spanKey  = "tracekit:span"
+	startKey = "tracekit:start"
+)
+
+// Plugin is a GORM plugin that wraps every operation in a span
+type Plugin struct {
+	sdk *tracekit.SDK
+}
+
+// Name identifies the plugin to GORM
+func (p *Plugin) Name() string {
+	return "tracekit"
+}
+
+// Initialize registers before/after callbacks around each GORM operation
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation string
+		before    func(string, func(*gorm.DB)) error
+		after     func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, hook := range hooks {
+		if err := hook.before("tracekit:before_"+hook.operation, p.before(hook.operation)); err != nil {
+			return err
+		}
+		if err := hook.after("tracekit:after_"+hook.operation, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before starts a gorm.<operation> span as a child of the statement context
+func (p *Plugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := p.sdk.StartSpan(db.Statement.Context, "gorm."+operation)
+		p.sdk.AddAttribute(span, "db.system", "postgresql")
+		p.sdk.AddAttribute(span, "db.orm", "gorm")
+		p.sdk.AddAttribute(span, "db.operation", operation)
+
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+		db.InstanceSet(startKey, time.Now())
+	}
+}
+
+// after records the statement and outcome and ends the span
+func (p *Plugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	if start, ok := db.InstanceGet(startKey); ok {
+		p.sdk.AddFloatAttribute(span, "db.duration_ms", float64(time.Since(start.(time.Time)).Microseconds())/1000)
+	}
+	if db.Statement.Table != "" {
+		p.sdk.AddAttribute(span, "db.sql.table", db.Statement.Table)
+	}
+	// The SQL keeps its placeholders, so parameter values stay out of the span
+	p.sdk.AddAttribute(span, "db.statement", db.Statement.SQL.String())
+	p.sdk.AddIntAttribute(span, "db.rows_affected", db.Statement.RowsAffected)
+
+	switch err := db.Error; {
+	case err == nil:
+		p.sdk.SetSuccess(span)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// An empty result isn't a failed query
+		p.sdk.AddEvent(span, "db.record_not_found")
+	default:
+		p.sdk.RecordError(span, err)
+	}
+}
@@ -0,0 +1,105 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when no product has the requested ID
+var ErrNotFound = errors.New("product not found")
+
+// Product is a catalog item managed through GORM
+type Product struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SKU        string    `gorm:"uniqueIndex;size:64;not null" json:"sku"`
+	Name       string    `gorm:"size:200;not null" json:"name"`
+	PriceCents int64     `gorm:"not null" json:"price_cents"`
+	Stock      int       `gorm:"not null;default:0" json:"stock"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ProductUpdate lists the fields of a Product that can be changed;
+// nil fields are left alone
+type ProductUpdate struct {
+	Name       *string `json:"name"`
+	PriceCents *int64  `json:"price_cents"`
+	Stock      *int    `json:"stock"`
+}
+
+// Migrate creates the products table and seeds it when empty
+func (s *Store) Migrate(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+	if err := db.AutoMigrate(&Product{}); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := db.Model(&Product{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return db.Create([]Product{
+		{SKU: "LAPTOP-13", Name: "13\" Laptop", PriceCents: 129900, Stock: 12},
+		{SKU: "MOUSE-BT", Name: "Bluetooth Mouse", PriceCents: 2999, Stock: 140},
+		{SKU: "DOCK-USB4", Name: "USB4 Dock", PriceCents: 21900, Stock: 35},
+	}).Error
+}
+
+// ListProducts returns up to limit products ordered by ID
+func (s *Store) ListProducts(ctx context.Context, limit int) ([]Product, error) {
+	products := []Product{}
+	err := s.db.WithContext(ctx).Order("id").Limit(limit).Find(&products).Error
+	return products, err
+}
+
+// GetProduct returns the product with the given ID
+func (s *Store) GetProduct(ctx context.Context, id uint) (Product, error) {
+	var product Product
+	err := s.db.WithContext(ctx).First(&product, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Product{}, ErrNotFound
+	}
+	return product, err
+}
+
+// CreateProduct inserts product and returns it with its ID and timestamps
+func (s *Store) CreateProduct(ctx context.Context, product Product) (Product, error) {
+	product.ID = 0
+	err := s.db.WithContext(ctx).Create(&product).Error
+	return product, err
+}
+
+// UpdateProduct applies update to the product with the given ID and returns
+// the result
+func (s *Store) UpdateProduct(ctx context.Context, id uint, update ProductUpdate) (Product, error) {
+	product, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return Product{}, err
+	}
+
+	changes := map[string]interface{}{}
+	if update.Name != nil {
+		changes["name"] = *update.Name
+	}
+	if update.PriceCents != nil {
+		changes["price_cents"] = *update.PriceCents
+	}
+	if update.Stock != nil {
+		changes["stock"] = *update.Stock
+	}
+	if len(changes) == 0 {
+		return product, nil
+	}
+
+	if err := s.db.WithContext(ctx).Model(&product).Updates(changes).Error; err != nil {
+		return Product{}, err
+	}
+	return s.GetProduct(ctx, id)
+}
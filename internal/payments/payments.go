@@ -0,0 +1,158 @@
+// Package payments is an in-process payment gateway simulator that honors
+// idempotency keys the way real gateways do: retrying a charge with the same
+// key returns the original result instead of charging again.
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// keyTTL is how long an idempotency key is remembered
+const keyTTL = 24 * time.Hour
+
+// ErrKeyReused is returned when an idempotency key is sent again with a
+// different request
+var ErrKeyReused = errors.New("idempotency key reused with a different request")
+
+// ErrDeclined is returned for charges the simulator refuses
+var ErrDeclined = errors.New("card declined")
+
+// Charge is a payment request
+type Charge struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	Card     string  `json:"card"`
+}
+
+// Payment is the result of a successful charge
+type Payment struct {
+	ID        string    `json:"payment_id"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// entry remembers the outcome of the first request made with a key.
+// done is closed once payment and err are set.
+type entry struct {
+	charge  Charge
+	done    chan struct{}
+	payment *Payment
+	err     error
+	created time.Time
+}
+
+// Gateway simulates a payment provider
+type Gateway struct {
+	sdk *tracekit.SDK
+
+	mu   sync.Mutex
+	keys map[string]*entry
+}
+
+// NewGateway creates a simulator with no remembered keys
+func NewGateway(sdk *tracekit.SDK) *Gateway {
+	return &Gateway{sdk: sdk, keys: make(map[string]*entry)}
+}
+
+// Charge charges c once per idempotency key. A repeated key returns the
+// first request's outcome with replay set, waiting for it if that request
+// is still in flight. An empty key disables deduplication. The card number
+// "4000000000000002" is always declined.
+func (g *Gateway) Charge(ctx context.Context, key string, c Charge) (p *Payment, replay bool, err error) {
+	ctx, span := g.sdk.StartSpan(ctx, "paymentGateway.charge")
+	defer span.End()
+
+	g.sdk.AddAttribute(span, "payment.gateway", "simulator")
+	g.sdk.AddFloatAttribute(span, "payment.amount", c.Amount)
+	g.sdk.AddAttribute(span, "payment.currency", c.Currency)
+	if key != "" {
+		g.sdk.AddAttribute(span, "idempotency.key", key)
+	}
+
+	e, replay := g.claim(key, c)
+	span.SetAttributes(attribute.Bool("idempotent.replay", replay))
+
+	if replay {
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			g.sdk.RecordError(span, ctx.Err())
+			return nil, true, ctx.Err()
+		}
+		if e.charge != c {
+			g.sdk.RecordError(span, ErrKeyReused)
+			return nil, true, ErrKeyReused
+		}
+		g.sdk.AddEvent(span, "payment.replayed")
+	} else {
+		e.payment, e.err = g.process(ctx, c)
+		close(e.done)
+	}
+
+	if e.err != nil {
+		g.sdk.RecordError(span, e.err)
+		return nil, replay, e.err
+	}
+	g.sdk.AddAttribute(span, "payment.id", e.payment.ID)
+	g.sdk.SetSuccess(span)
+	return e.payment, replay, nil
+}
+
+// claim returns the entry for key and whether another request already
+// holds it
+func (g *Gateway) claim(key string, c Charge) (*entry, bool) {
+	fresh := &entry{charge: c, done: make(chan struct{}), created: time.Now()}
+	if key == "" {
+		return fresh, false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for k, e := range g.keys {
+		if time.Since(e.created) > keyTTL {
+			delete(g.keys, k)
+		}
+	}
+	if e, ok := g.keys[key]; ok {
+		return e, true
+	}
+	g.keys[key] = fresh
+	return fresh, false
+}
+
+// process is the simulated call to the card network
+func (g *Gateway) process(ctx context.Context, c Charge) (*Payment, error) {
+	_, span := g.sdk.StartSpan(ctx, "paymentGateway.authorize")
+	defer span.End()
+
+	time.Sleep(80 * time.Millisecond)
+
+	if c.Card == "4000000000000002" {
+		g.sdk.RecordError(span, ErrDeclined)
+		return nil, ErrDeclined
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	p := &Payment{
+		ID:        "pay_" + hex.EncodeToString(b),
+		Amount:    c.Amount,
+		Currency:  c.Currency,
+		Status:    "succeeded",
+		CreatedAt: time.Now(),
+	}
+	g.sdk.AddAttribute(span, "payment.id", p.ID)
+	g.sdk.SetSuccess(span)
+	return p, nil
+}
@@ -0,0 +1,108 @@
+// Package pb holds the protobuf messages shared by the gRPC OrderService,
+// the Kafka order events, and the HTTP API, generated from the .proto files
+// next to it. Marshal and Unmarshal run in "serialize" and "deserialize"
+// spans recording the format, the schema, and the size, so every transport
+// reports its payloads the same way.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative order.proto user.proto
+
+import (
+	"context"
+	"fmt"
+	"mime"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Wire formats of a message
+const (
+	FormatProtobuf = "protobuf"
+	FormatJSON     = "json"
+)
+
+// JSONOptions render messages the way the rest of the HTTP API looks:
+// snake_case field names, with zero values included
+var JSONOptions = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// ContentType is the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatJSON {
+		return "application/json"
+	}
+	return "application/x-protobuf"
+}
+
+// FormatOf returns the format of a MIME type. No type at all is JSON, which
+// messages carried before they were protobuf; any other type is read as
+// binary protobuf.
+func FormatOf(contentType string) string {
+	if contentType == "" {
+		return FormatJSON
+	}
+	if media, _, _ := mime.ParseMediaType(contentType); media == "application/json" {
+		return FormatJSON
+	}
+	return FormatProtobuf
+}
+
+// Schema is the full protobuf name of a message, e.g. tracekit.example.Order
+func Schema(msg proto.Message) string {
+	return string(msg.ProtoReflect().Descriptor().FullName())
+}
+
+// Marshal encodes msg in format inside a "serialize" span
+func Marshal(ctx context.Context, sdk *tracekit.SDK, format string, msg proto.Message) ([]byte, error) {
+	_, span := sdk.StartSpan(ctx, "serialize")
+	defer span.End()
+
+	sdk.AddAttribute(span, "serialization.format", format)
+	sdk.AddAttribute(span, "serialization.schema", Schema(msg))
+
+	var data []byte
+	var err error
+	switch format {
+	case FormatProtobuf:
+		data, err = proto.Marshal(msg)
+	case FormatJSON:
+		data, err = JSONOptions.Marshal(msg)
+	default:
+		err = fmt.Errorf("unknown serialization format %q", format)
+	}
+	if err != nil {
+		sdk.RecordError(span, err)
+		return nil, err
+	}
+	sdk.AddIntAttribute(span, "serialization.size_bytes", int64(len(data)))
+	sdk.SetSuccess(span)
+	return data, nil
+}
+
+// Unmarshal decodes data in format into msg inside a "deserialize" span.
+// Unknown JSON fields are ignored, as unknown protobuf fields are.
+func Unmarshal(ctx context.Context, sdk *tracekit.SDK, format string, data []byte, msg proto.Message) error {
+	_, span := sdk.StartSpan(ctx, "deserialize")
+	defer span.End()
+
+	sdk.AddAttribute(span, "serialization.format", format)
+	sdk.AddAttribute(span, "serialization.schema", Schema(msg))
+	sdk.AddIntAttribute(span, "serialization.size_bytes", int64(len(data)))
+
+	var err error
+	switch format {
+	case FormatProtobuf:
+		err = proto.Unmarshal(data, msg)
+	case FormatJSON:
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+	default:
+		err = fmt.Errorf("unknown serialization format %q", format)
+	}
+	if err != nil {
+		sdk.RecordError(span, err)
+		return err
+	}
+	sdk.SetSuccess(span)
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package poll is the in-memory event hub behind the /api/poll long-polling
+// endpoint. Events are numbered per topic, and a poller asks for whatever
+// came after the last number it saw, so nothing published between two polls
+// is missed.
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// keep is how many of a topic's latest events are held for pollers that
+	// fall behind
+	keep = 100
+
+	// maxTopics bounds the topics a hub holds; topics are never removed
+	maxTopics = 1000
+)
+
+var (
+	// ErrClosed is returned to pollers still waiting when the hub shuts down
+	ErrClosed = errors.New("poll hub closed")
+
+	// ErrTooManyTopics is returned when publishing to a new topic would
+	// exceed maxTopics
+	ErrTooManyTopics = errors.New("too many poll topics")
+)
+
+// Event is one published event
+type Event struct {
+	Seq         int64           `json:"seq"`
+	Topic       string          `json:"topic"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	PublishedAt time.Time       `json:"published_at"`
+}
+
+// Hub holds the latest events of each topic and wakes the pollers waiting
+// on it
+type Hub struct {
+	mu     sync.Mutex
+	topics map[string]*topic
+	// created is closed and replaced whenever a topic is created, waking
+	// pollers of topics nobody has published to yet
+	created chan struct{}
+	closed  chan struct{}
+	once    sync.Once
+}
+
+type topic struct {
+	seq    int64
+	events []Event
+	// wake is closed and replaced on every publish
+	wake chan struct{}
+}
+
+// New creates an empty Hub
+func New() *Hub {
+	return &Hub{topics: make(map[string]*topic), created: make(chan struct{}), closed: make(chan struct{})}
+}
+
+// Publish adds an event to a topic, creating the topic on its first event,
+// and wakes its pollers
+func (h *Hub) Publish(name string, data json.RawMessage) (Event, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[name]
+	if !ok {
+		if len(h.topics) >= maxTopics {
+			return Event{}, ErrTooManyTopics
+		}
+		t = &topic{wake: make(chan struct{})}
+		h.topics[name] = t
+		close(h.created)
+		h.created = make(chan struct{})
+	}
+	t.seq++
+	e := Event{Seq: t.seq, Topic: name, Data: data, PublishedAt: time.Now()}
+	t.events = append(t.events, e)
+	if len(t.events) > keep {
+		t.events = t.events[len(t.events)-keep:]
+	}
+	close(t.wake)
+	t.wake = make(chan struct{})
+	return e, nil
+}
+
+// Cursor is the number of a topic's latest event, where a poller that only
+// wants new events starts from; 0 for a topic without events
+func (h *Hub) Cursor(name string) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if t, ok := h.topics[name]; ok {
+		return t.seq
+	}
+	return 0
+}
+
+// Wait returns the events of a topic after since, waiting for the next one
+// when there are none yet, until ctx is done or the hub closes. The cursor
+// is where the next poll should continue from. Waiting on a topic doesn't
+// create it.
+func (h *Hub) Wait(ctx context.Context, name string, since int64) (events []Event, cursor int64, err error) {
+	for {
+		h.mu.Lock()
+		t, ok := h.topics[name]
+		if !ok {
+			created := h.created
+			h.mu.Unlock()
+			select {
+			case <-created:
+				continue
+			case <-ctx.Done():
+				return nil, 0, ctx.Err()
+			case <-h.closed:
+				return nil, 0, ErrClosed
+			}
+		}
+		// A cursor ahead of the topic is from before a restart; everything
+		// held is news to that poller
+		if since > t.seq {
+			since = 0
+		}
+		for _, e := range t.events {
+			if e.Seq > since {
+				events = append(events, e)
+			}
+		}
+		cursor, wake := t.seq, t.wake
+		h.mu.Unlock()
+
+		if len(events) > 0 {
+			return events, cursor, nil
+		}
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return nil, cursor, ctx.Err()
+		case <-h.closed:
+			return nil, cursor, ErrClosed
+		}
+	}
+}
+
+// Close releases every waiting poller, so a shutdown doesn't wait out their
+// timeouts
+func (h *Hub) Close() {
+	h.once.Do(func() { close(h.closed) })
+}
@@ -0,0 +1,85 @@
+// Package prom exposes request metrics in the Prometheus format. Latency
+// observations carry the trace ID as an exemplar so Grafana can jump from a
+// histogram bucket straight to the matching TraceKit trace.
+package prom
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics holds the Prometheus registry and request collectors
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// New creates the collectors on a dedicated registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total HTTP requests by method, route, and status code.",
+		}, []string{"method", "route", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.latency,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Middleware records every request. It must run after the SDK middleware so
+// the request context already holds the server span.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+
+		m.requests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+
+		observer := m.latency.WithLabelValues(method, route)
+		seconds := time.Since(start).Seconds()
+
+		spanCtx := trace.SpanContextFromContext(c.Request.Context())
+		if exemplar, ok := observer.(prometheus.ExemplarObserver); ok && spanCtx.IsSampled() {
+			exemplar.ObserveWithExemplar(seconds, prometheus.Labels{
+				"trace_id": spanCtx.TraceID().String(),
+				"span_id":  spanCtx.SpanID().String(),
+			})
+			return
+		}
+		observer.Observe(seconds)
+	}
+}
+
+// Handler serves the registry. Exemplars are only part of the OpenMetrics
+// exposition format, so it is enabled for scrapers that ask for it.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
@@ -0,0 +1,135 @@
+// Package ratelimit throttles clients with a token bucket per API key or IP.
+// Rejected requests still get a server span, tagged so throttling shows up
+// in TraceKit next to the traffic it protects.
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// Buckets idle for longer than idleTTL are dropped, checked every sweepEvery
+const (
+	idleTTL    = 3 * time.Minute
+	sweepEvery = time.Minute
+)
+
+// APIKeyHeader identifies a client more precisely than its IP
+const APIKeyHeader = "X-API-Key"
+
+// bucket is one client's token bucket
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter holds a token bucket per client
+type Limiter struct {
+	rps    rate.Limit
+	burst  int
+	exempt map[string]bool
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New creates a Limiter allowing rps requests per second per client with
+// bursts of up to burst. Requests to exempt routes are never limited.
+func New(rps float64, burst int, exempt ...string) *Limiter {
+	l := &Limiter{
+		rps:       rate.Limit(rps),
+		burst:     burst,
+		exempt:    make(map[string]bool, len(exempt)),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+	for _, route := range exempt {
+		l.exempt[route] = true
+	}
+	return l
+}
+
+// Middleware rejects clients that are over their limit with 429. Register it
+// after the SDK middleware so the server span exists to record the decision.
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.exempt[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		key, keyType := clientKey(c)
+		lim := l.bucket(key)
+		allowed := lim.Allow()
+
+		span := trace.SpanFromContext(c.Request.Context())
+		span.SetAttributes(
+			attribute.Bool("ratelimit.exceeded", !allowed),
+			attribute.String("ratelimit.key_type", keyType),
+			attribute.Float64("ratelimit.limit_rps", float64(l.rps)),
+			attribute.Int("ratelimit.burst", l.burst),
+			attribute.Int("ratelimit.remaining", int(math.Max(0, lim.Tokens()))),
+		)
+
+		if !allowed {
+			retryAfter := int(math.Ceil(1 / float64(l.rps)))
+			span.SetAttributes(attribute.Int("http.status_code", 429))
+			span.AddEvent("ratelimit.rejected")
+
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(429, gin.H{
+				"error":       "Too Many Requests",
+				"message":     "Rate limit exceeded, slow down",
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// bucket returns the limiter for key, creating it on first use and sweeping
+// idle buckets now and then
+func (l *Limiter) bucket(key string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > sweepEvery {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > idleTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// clientKey identifies the caller by API key when one is sent, otherwise by
+// IP. API keys are hashed so raw keys aren't kept as map keys.
+func clientKey(c *gin.Context) (key, keyType string) {
+	if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:]), "api_key"
+	}
+	return "ip:" + c.ClientIP(), "ip"
+}
@@ -0,0 +1,111 @@
+// Package recent remembers the last few requests and their trace IDs so the
+// status page can link straight to them in TraceKit.
+package recent
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Request is one handled request
+type Request struct {
+	Time     time.Time
+	Method   string
+	Path     string
+	Status   int
+	Duration time.Duration
+	TraceID  string
+}
+
+// Log is a fixed-size ring of the most recent requests
+type Log struct {
+	mu       sync.Mutex
+	buf      []Request
+	next     int
+	full     bool
+	skip     map[string]bool
+	prefixes []string
+}
+
+// New creates a Log holding up to size requests. Requests to the skip paths
+// (the status page itself, probes, scrapes) are not recorded; a path ending
+// in * skips everything under it, e.g. /static/*.
+func New(size int, skip ...string) *Log {
+	l := &Log{buf: make([]Request, size), skip: make(map[string]bool, len(skip))}
+	for _, path := range skip {
+		if prefix, ok := strings.CutSuffix(path, "*"); ok {
+			l.prefixes = append(l.prefixes, prefix)
+			continue
+		}
+		l.skip[path] = true
+	}
+	return l
+}
+
+// Middleware records every request once it has been handled. Register it
+// after the SDK middleware so the trace ID is available.
+func (l *Log) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		if l.skipped(c.Request.URL.Path) {
+			return
+		}
+		sc := trace.SpanContextFromContext(c.Request.Context())
+		if !sc.IsValid() {
+			return
+		}
+		l.add(Request{
+			Time:     start,
+			Method:   c.Request.Method,
+			Path:     c.Request.URL.RequestURI(),
+			Status:   c.Writer.Status(),
+			Duration: time.Since(start),
+			TraceID:  sc.TraceID().String(),
+		})
+	}
+}
+
+func (l *Log) skipped(path string) bool {
+	if l.skip[path] {
+		return true
+	}
+	for _, prefix := range l.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Log) add(r Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf[l.next] = r
+	l.next = (l.next + 1) % len(l.buf)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// List returns the recorded requests, newest first
+func (l *Log) List() []Request {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.next
+	if l.full {
+		n = len(l.buf)
+	}
+	out := make([]Request, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, l.buf[(l.next-i+len(l.buf))%len(l.buf)])
+	}
+	return out
+}
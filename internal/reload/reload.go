@@ -0,0 +1,271 @@
+// Package reload applies configuration changes to the running app on
+// SIGHUP: downstream service URLs, sampling, and the log level. Each reload
+// is traced as a "config.reload" root span with an event per change.
+package reload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/config"
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/sampling"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// reloadable are the Config fields a reload applies; a change to any other
+// field is reported as needing a restart
+var reloadable = []string{
+	"LogLevel",
+	"SampleRate", "SampleSource", "SampleParentBased", "SampleRoutes", "TraceSkipPaths",
+	"ServicesFile",
+}
+
+// Change is one setting a reload changed
+type Change struct {
+	Setting string `json:"setting"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// Result describes one reload
+type Result struct {
+	Trigger string    `json:"trigger"`
+	At      time.Time `json:"at"`
+	Changes []Change  `json:"changes"`
+
+	// RestartRequired lists changed Config fields that only take effect
+	// after a restart
+	RestartRequired []string `json:"restart_required,omitempty"`
+
+	// Error is why the reload was rejected; nothing was applied
+	Error string `json:"error,omitempty"`
+}
+
+// Targets are the parts of the running app a reload updates
+type Targets struct {
+	Sampler *sampling.Sampler
+	Client  *clients.Client
+
+	// SamplingSettings and LoadServices derive the sampler settings and the
+	// service topology from a configuration, as at startup
+	SamplingSettings func(cfg *config.Config) sampling.Settings
+	LoadServices     func(cfg *config.Config) (clients.Services, error)
+}
+
+// Reloader re-reads the configuration and applies what changed
+type Reloader struct {
+	sdk     *tracekit.SDK
+	args    []string
+	targets Targets
+
+	// routes have a /api/call-<route> endpoint; new ones need a restart
+	routes map[string]bool
+	// mapped are the host:port to service name mappings the SDK was created
+	// with; it doesn't take new ones, so a new host needs a restart
+	mapped map[string]string
+
+	mu      sync.Mutex
+	current *config.Config
+}
+
+// New creates a Reloader for the app started with cfg, parsed from the
+// command-line args
+func New(sdk *tracekit.SDK, cfg *config.Config, args []string, targets Targets) *Reloader {
+	routes := make(map[string]bool)
+	for _, svc := range targets.Client.Services().All() {
+		routes[svc.Route] = true
+	}
+	return &Reloader{
+		sdk:     sdk,
+		args:    args,
+		targets: targets,
+		routes:  routes,
+		mapped:  targets.Client.Services().NameMappings(),
+		current: cfg,
+	}
+}
+
+// Watch reloads on every SIGHUP until ctx is done
+func (r *Reloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.Reload(ctx, "SIGHUP")
+		}
+	}
+}
+
+// Reload re-reads .env, the flags, and the service topology, and applies
+// every change it can. A configuration that doesn't load is rejected as a
+// whole and the running one kept.
+func (r *Reloader) Reload(ctx context.Context, trigger string) Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx, span := tracing.Tracer().Start(ctx, "config.reload", trace.WithNewRoot())
+	defer span.End()
+	r.sdk.AddAttribute(span, "config.reload.trigger", trigger)
+	logger := logging.FromContext(ctx)
+
+	result := Result{Trigger: trigger, At: time.Now(), Changes: []Change{}}
+	changes, restart, err := r.apply(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		r.sdk.RecordError(span, err)
+		logger.Error("❌ Config reload rejected, keeping the running configuration", zap.String("trigger", trigger), zap.Error(err))
+		return result
+	}
+	result.Changes, result.RestartRequired = append(result.Changes, changes...), restart
+
+	for _, c := range changes {
+		span.AddEvent("config.changed", trace.WithAttributes(
+			attribute.String("config.setting", c.Setting),
+			attribute.String("config.from", c.From),
+			attribute.String("config.to", c.To),
+		))
+	}
+	r.sdk.AddIntAttribute(span, "config.reload.changes", int64(len(changes)))
+	if len(restart) > 0 {
+		span.SetAttributes(attribute.StringSlice("config.reload.restart_required", restart))
+		logger.Warn("⚠️  Some changed settings need a restart", zap.Strings("settings", restart))
+	}
+	r.sdk.SetSuccess(span)
+	logger.Info("🔄 Configuration reloaded", zap.String("trigger", trigger), zap.Any("changes", changes))
+	return result
+}
+
+// apply loads the configuration and applies it, returning what changed and
+// what needs a restart. Nothing is applied when it returns an error.
+func (r *Reloader) apply(ctx context.Context) ([]Change, []string, error) {
+	cfg, err := config.Reload(r.args)
+	if err != nil {
+		return nil, nil, err
+	}
+	services, err := r.targets.LoadServices(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := r.current
+
+	// The running components hold the live state, which /admin/config may
+	// have changed since the last load. Only settings changed in the
+	// configuration itself are applied, so an unchanged .env doesn't undo
+	// those edits; "from" is whatever was live.
+	var changes []Change
+	if cfg.LogLevel != current.LogLevel {
+		from := logging.Level()
+		if err := logging.SetLevel(cfg.LogLevel); err != nil {
+			return nil, nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+		}
+		changes = append(changes, Change{Setting: "log.level", From: from, To: cfg.LogLevel})
+	}
+
+	oldSampling, newSampling := r.targets.Sampler.Settings(), r.targets.SamplingSettings(cfg)
+	if !reflect.DeepEqual(r.targets.SamplingSettings(current), newSampling) {
+		if r.targets.Sampler.Update(newSampling) {
+			trace.SpanFromContext(ctx).AddEvent("sampling.capped")
+			logging.FromContext(ctx).Warn("⚠️  A sampling ratio is above the one the SDK started with; it's capped until a restart",
+				zap.Float64("sdk_ratio", r.targets.Sampler.SDKRatio()))
+		}
+		changes = append(changes, Change{Setting: "sampling", From: describe(oldSampling), To: describe(newSampling)})
+	}
+
+	// In standalone mode the mocks stand in for whatever the topology says
+	var restart []string
+	if !current.Standalone {
+		var serviceChanges []Change
+		serviceChanges, restart = r.diffServices(services)
+		if len(serviceChanges) > 0 {
+			services.Self = r.targets.Client.Services().Self
+			r.targets.Client.SetServices(services)
+			changes = append(changes, serviceChanges...)
+		}
+	}
+
+	for _, field := range config.Changed(current, cfg) {
+		if !slices.Contains(reloadable, field) {
+			restart = append(restart, field)
+		}
+	}
+
+	// Only the reloadable settings took effect
+	next := *current
+	next.LogLevel = cfg.LogLevel
+	next.SampleRate, next.SampleSource, next.SampleParentBased = cfg.SampleRate, cfg.SampleSource, cfg.SampleParentBased
+	next.SampleRoutes, next.TraceSkipPaths = cfg.SampleRoutes, cfg.TraceSkipPaths
+	next.ServicesFile = cfg.ServicesFile
+	r.current = &next
+
+	return changes, restart, nil
+}
+
+// diffServices compares the running services with a reloaded topology. A
+// service on a new route needs a restart for its /api/call-<route>; it's
+// called by /api/call-all and probed by /health/deep right away. One on a
+// host the SDK has no name for needs a restart to be named in the service
+// graph; until then its calls show up under the bare host.
+func (r *Reloader) diffServices(services clients.Services) ([]Change, []string) {
+	var changes []Change
+	var restart []string
+	old := r.targets.Client.Services().All()
+	for _, svc := range services.All() {
+		i := slices.IndexFunc(old, func(o clients.Service) bool { return o.Name == svc.Name })
+		if i < 0 {
+			changes = append(changes, Change{Setting: "service " + svc.Name, To: svc.URL})
+			if !r.routes[svc.Route] {
+				restart = append(restart, "/api/call-"+svc.Route)
+			}
+			continue
+		}
+		prev := old[i]
+		if prev.Route != svc.Route && !r.routes[svc.Route] {
+			restart = append(restart, "/api/call-"+svc.Route)
+		}
+		a, b := reflect.ValueOf(prev), reflect.ValueOf(svc)
+		for f := range a.NumField() {
+			if from, to := fmt.Sprint(a.Field(f).Interface()), fmt.Sprint(b.Field(f).Interface()); from != to {
+				changes = append(changes, Change{Setting: "service " + svc.Name + " " + a.Type().Field(f).Name, From: from, To: to})
+			}
+		}
+	}
+	for _, prev := range old {
+		if !slices.ContainsFunc(services.All(), func(svc clients.Service) bool { return svc.Name == prev.Name }) {
+			changes = append(changes, Change{Setting: "service " + prev.Name, From: prev.URL})
+		}
+	}
+	mappings := services.NameMappings()
+	for _, host := range slices.Sorted(maps.Keys(mappings)) {
+		if r.mapped[host] != mappings[host] {
+			restart = append(restart, "ServiceNameMappings "+host)
+		}
+	}
+	return changes, restart
+}
+
+// describe renders sampling settings for a Change
+func describe(s sampling.Settings) string {
+	out, _ := json.Marshal(s)
+	return string(out)
+}
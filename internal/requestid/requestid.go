@@ -0,0 +1,81 @@
+// Package requestid gives every request an X-Request-ID and returns it with
+// the trace ID, so API consumers have an identifier to quote that support
+// can look up in TraceKit.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Header carries the request ID in both directions
+	Header = "X-Request-ID"
+
+	// TraceHeader returns the trace ID of sampled requests
+	TraceHeader = "X-Trace-ID"
+)
+
+// maxLength bounds accepted request IDs so callers can't bloat spans and logs
+const maxLength = 128
+
+type ctxKey struct{}
+
+// FromContext returns the request ID stored by the middleware, or ""
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Middleware accepts the caller's X-Request-ID, or generates one when it is
+// missing or unusable, and records it on the server span as request.id. The
+// response echoes it, along with X-Trace-ID when the trace is sampled; an
+// unsampled trace ID would point support at a trace that doesn't exist.
+// Register it after the SDK middleware.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, source := c.GetHeader(Header), "caller"
+		if !valid(id) {
+			id, source = generate(), "generated"
+		}
+
+		ctx := c.Request.Context()
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(
+			attribute.String("request.id", id),
+			attribute.String("request.id.source", source),
+		)
+
+		c.Header(Header, id)
+		if sc := span.SpanContext(); sc.IsSampled() {
+			c.Header(TraceHeader, sc.TraceID().String())
+		}
+
+		c.Request = c.Request.WithContext(context.WithValue(ctx, ctxKey{}, id))
+		c.Next()
+	}
+}
+
+// valid accepts short IDs of visible ASCII characters
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func generate() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
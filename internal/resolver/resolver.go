@@ -0,0 +1,126 @@
+// Package resolver looks up downstream hostnames itself instead of leaving
+// it to the dialer, caching the answers. Every lookup is a short
+// "dns.lookup" span under the CLIENT span that needed it, so intermittent
+// DNS latency shows up next to the call it held up.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/singleflight"
+)
+
+// Resolver resolves and caches hostnames for the downstream transports
+type Resolver struct {
+	sdk    *tracekit.SDK
+	ttl    time.Duration
+	server string
+	net    *net.Resolver
+
+	lookups singleflight.Group
+	mu      sync.Mutex
+	cache   map[string]entry
+}
+
+type entry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// New creates a Resolver that keeps answers for ttl. server is a DNS server
+// as host:port to ask instead of the system's, or "" for the system's.
+func New(sdk *tracekit.SDK, server string, ttl time.Duration) *Resolver {
+	r := &Resolver{sdk: sdk, ttl: ttl, server: server, net: net.DefaultResolver, cache: make(map[string]entry)}
+	if server != "" {
+		dialer := &net.Dialer{Timeout: 2 * time.Second}
+		r.net = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+	}
+	return r
+}
+
+// DialContext dials like dialer, but resolves the host itself and tries
+// the addresses in turn
+func (r *Resolver) DialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		addrs, err := r.Lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var errs []error
+		for _, ip := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+// Lookup returns the addresses of host, from the cache while the answer is
+// fresh. Concurrent lookups of the same host share one query.
+func (r *Resolver) Lookup(ctx context.Context, host string) ([]string, error) {
+	ctx, span := r.sdk.StartSpan(ctx, "dns.lookup")
+	defer span.End()
+	r.sdk.AddAttribute(span, "dns.question.name", host)
+
+	r.mu.Lock()
+	cached, ok := r.cache[host]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		span.SetAttributes(attribute.Bool("dns.cache_hit", true))
+		r.sdk.AddIntAttribute(span, "dns.answers", int64(len(cached.addrs)))
+		r.sdk.SetSuccess(span)
+		return cached.addrs, nil
+	}
+
+	span.SetAttributes(attribute.Bool("dns.cache_hit", false))
+	if r.server != "" {
+		r.sdk.AddAttribute(span, "dns.server", r.server)
+	}
+	lookup := r.lookups.DoChan(host, func() (interface{}, error) {
+		// The query outlives a caller that gives up, so the others sharing
+		// it still get an answer
+		addrs, err := r.net.LookupHost(context.WithoutCancel(ctx), host)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		r.cache[host] = entry{addrs: addrs, expires: time.Now().Add(r.ttl)}
+		r.mu.Unlock()
+		return addrs, nil
+	})
+	var result singleflight.Result
+	select {
+	case result = <-lookup:
+	case <-ctx.Done():
+		result.Err = ctx.Err()
+	}
+	span.SetAttributes(attribute.Bool("dns.shared", result.Shared))
+	if result.Err != nil {
+		r.sdk.RecordError(span, result.Err)
+		return nil, result.Err
+	}
+
+	addrs := result.Val.([]string)
+	r.sdk.AddIntAttribute(span, "dns.answers", int64(len(addrs)))
+	r.sdk.SetSuccess(span)
+	return addrs, nil
+}
@@ -0,0 +1,281 @@
+// Package respcache keeps successful responses of chosen GET routes in
+// memory for a while and answers repeats from there, recording on the
+// server span whether the response came from the cache and how old it was,
+// so cached requests can be told apart from the ones that did the work.
+package respcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BypassParam is the query parameter that skips the cache, e.g.
+// ?cache=bypass; the fresh response still replaces the cached one
+const BypassParam = "cache"
+
+// maxEntries bounds the memory a cache full of distinct query strings takes
+const maxEntries = 1000
+
+// unstored are response headers that belong to one response only; the rest
+// of what the handler set is replayed with every hit
+var unstored = []string{"Set-Cookie", "Date", "Content-Length"}
+
+// Cache holds the responses of the routes it was given
+type Cache struct {
+	// routes are the gin route templates to cache, with their TTLs
+	routes map[string]time.Duration
+	// staleFor is how long past its TTL an entry may still stand in for a
+	// failed refresh
+	staleFor time.Duration
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	status int
+	header http.Header
+	body   []byte
+	stored time.Time
+}
+
+// New creates a Cache for routes, keyed by gin route template, keeping
+// responses for their TTL and serving them up to staleFor longer when the
+// refresh fails
+func New(routes map[string]time.Duration, staleFor time.Duration) *Cache {
+	return &Cache{routes: routes, staleFor: staleFor, entries: make(map[string]entry)}
+}
+
+// Middleware answers cached GET routes from the cache while the response is
+// fresh and stores 200 responses otherwise. Register it last so the other
+// middleware still sees every request.
+func (rc *Cache) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ttl, ok := rc.routes[c.FullPath()]
+		if !ok || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		span := trace.SpanFromContext(c.Request.Context())
+		span.SetAttributes(attribute.Int64("cache.ttl_ms", ttl.Milliseconds()))
+
+		key := cacheKey(c.Request)
+		bypass := c.Query(BypassParam) == "bypass"
+		cached, found := rc.lookup(key)
+		age := time.Since(cached.stored)
+		if bypass {
+			span.SetAttributes(attribute.Bool("cache.bypass", true))
+			found = false
+		} else if found && age < ttl {
+			rc.serve(c, span, cached, age, false)
+			return
+		}
+
+		// Headers the middleware set already, such as the request ID, are
+		// per request; only the handler's are stored
+		before := c.Writer.Header().Clone()
+		w := &writer{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+		header := handlerHeader(before, w.Header())
+		if w.streamed {
+			span.SetAttributes(attribute.Bool("cache.hit", false), attribute.Bool("cache.stale", false))
+			return
+		}
+
+		// A failed refresh is answered with the last good response while
+		// that's recent enough
+		if w.Status() >= 500 && found && age < ttl+rc.staleFor {
+			span.SetAttributes(attribute.Int("cache.refresh_status_code", w.Status()))
+			// Drop what the failed handler set before replaying the entry
+			for name := range header {
+				if values, ok := before[name]; ok {
+					w.Header()[name] = values
+				} else {
+					delete(w.Header(), name)
+				}
+			}
+			rc.serve(c, span, cached, age, true)
+			return
+		}
+		span.SetAttributes(attribute.Bool("cache.hit", false), attribute.Bool("cache.stale", false))
+		if bypass {
+			c.Header("X-Cache", "BYPASS")
+		} else {
+			c.Header("X-Cache", "MISS")
+		}
+		w.send()
+		if w.Status() == http.StatusOK {
+			rc.store(key, entry{
+				status: http.StatusOK,
+				header: header,
+				body:   w.buf.Bytes(),
+				stored: time.Now(),
+			})
+		}
+	}
+}
+
+// serve answers c with a cached response, headers included, or with a 304
+// when the request's If-None-Match names its ETag
+func (rc *Cache) serve(c *gin.Context, span trace.Span, e entry, age time.Duration, stale bool) {
+	span.SetAttributes(
+		attribute.Bool("cache.hit", true),
+		attribute.Bool("cache.stale", stale),
+		attribute.Int64("cache.age_ms", age.Milliseconds()),
+	)
+	c.Header("Age", strconv.Itoa(int(age.Seconds())))
+	if stale {
+		c.Header("X-Cache", "STALE")
+	} else {
+		c.Header("X-Cache", "HIT")
+	}
+	for name, values := range e.header {
+		c.Writer.Header()[name] = slices.Clone(values)
+	}
+	if etag := e.header.Get("ETag"); etag != "" && ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		c.Abort()
+		return
+	}
+	c.Data(e.status, e.header.Get("Content-Type"), e.body)
+	c.Abort()
+}
+
+// handlerHeader is what a handler added to the response headers in before
+func handlerHeader(before, after http.Header) http.Header {
+	header := make(http.Header)
+	for name, values := range after {
+		if slices.Contains(unstored, name) || slices.Equal(before[name], values) {
+			continue
+		}
+		header[name] = slices.Clone(values)
+	}
+	return header
+}
+
+// ETagMatches reports whether an If-None-Match header names etag, comparing
+// weakly as RFC 9110 asks for If-None-Match
+func ETagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (rc *Cache) lookup(key string) (entry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	e, ok := rc.entries[key]
+	return e, ok
+}
+
+// store keeps e under key, first dropping entries too old to serve even
+// stale once the cache is full
+func (rc *Cache) store(key string, e entry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= maxEntries {
+		for k, old := range rc.entries {
+			if time.Since(old.stored) >= rc.maxTTL()+rc.staleFor {
+				delete(rc.entries, k)
+			}
+		}
+		if len(rc.entries) >= maxEntries {
+			return
+		}
+	}
+	rc.entries[key] = e
+}
+
+// maxTTL is the longest TTL of any route
+func (rc *Cache) maxTTL() time.Duration {
+	var longest time.Duration
+	for _, ttl := range rc.routes {
+		longest = max(longest, ttl)
+	}
+	return longest
+}
+
+// cacheKey identifies a response by path and query, leaving out the bypass
+// parameter so a bypassing request refreshes the entry others read. A
+// request with credentials, a cookie or an Authorization header, gets
+// entries of its own, keyed by a hash of them, so one caller's response is
+// never served to another.
+func cacheKey(r *http.Request) string {
+	query := r.URL.Query()
+	query.Del(BypassParam)
+	key := r.URL.Path
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+	cookie, auth := r.Header.Values("Cookie"), r.Header.Get("Authorization")
+	if len(cookie) == 0 && auth == "" {
+		return key
+	}
+	sum := sha256.Sum256([]byte(strings.Join(cookie, "; ") + "\n" + auth))
+	return key + "#" + hex.EncodeToString(sum[:16])
+}
+
+// writer holds the response back until the handler is done, so it can be
+// stored, or dropped in favour of a stale copy. A handler that flushes is
+// streaming; that response goes out as it's written and isn't cached.
+type writer struct {
+	gin.ResponseWriter
+	buf      bytes.Buffer
+	streamed bool
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	if w.streamed {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+func (w *writer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow is deferred with the body
+func (w *writer) WriteHeaderNow() {
+	if w.streamed {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Flush gives up on caching and sends what was held back
+func (w *writer) Flush() {
+	if !w.streamed {
+		w.streamed = true
+		w.send()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// send writes out the held-back response
+func (w *writer) send() {
+	w.ResponseWriter.WriteHeaderNow()
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+	}
+}
@@ -0,0 +1,101 @@
+// Package retry retries operations with jittered exponential backoff and
+// gives every attempt its own span, so the retry shape shows up in the
+// trace waterfall.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+)
+
+// Policy controls how often and how patiently an operation is retried
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Default makes up to four attempts, waiting up to 100ms, 200ms, then 400ms
+// between them
+var Default = Policy{
+	MaxAttempts: 4,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// permanentError stops retrying
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Do returns it without further attempts
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Do calls fn until it succeeds, returns a Permanent error, the attempts run
+// out, or ctx is done. Each attempt runs in a span named name+".attempt"
+// with retry.attempt and retry.delay_ms attributes. It returns the number of
+// attempts made and the last error.
+func Do(ctx context.Context, sdk *tracekit.SDK, name string, p Policy, fn func(ctx context.Context) error) (int, error) {
+	var err error
+	var delay time.Duration
+
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay = p.Backoff(attempt - 1)
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return attempt - 1, ctx.Err()
+			}
+		}
+
+		err = runAttempt(ctx, sdk, name, attempt, p.MaxAttempts, delay, fn)
+		if err == nil {
+			return attempt, nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return attempt, perm.err
+		}
+	}
+	return p.MaxAttempts, err
+}
+
+// runAttempt runs a single attempt inside its own span
+func runAttempt(ctx context.Context, sdk *tracekit.SDK, name string, attempt, maxAttempts int, delay time.Duration, fn func(ctx context.Context) error) error {
+	ctx, span := sdk.StartSpan(ctx, name+".attempt")
+	defer span.End()
+
+	sdk.AddIntAttribute(span, "retry.attempt", int64(attempt))
+	sdk.AddIntAttribute(span, "retry.max_attempts", int64(maxAttempts))
+	sdk.AddIntAttribute(span, "retry.delay_ms", delay.Milliseconds())
+
+	if err := fn(ctx); err != nil {
+		sdk.RecordError(span, err)
+		return err
+	}
+	sdk.SetSuccess(span)
+	return nil
+}
+
+// Backoff returns a full-jitter delay for the given retry: a random duration
+// between zero and BaseDelay doubled per retry, capped at MaxDelay
+func (p Policy) Backoff(retry int) time.Duration {
+	ceiling := p.BaseDelay << (retry - 1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
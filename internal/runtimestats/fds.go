@@ -0,0 +1,14 @@
+package runtimestats
+
+import "os"
+
+// OpenFDs counts the process's open file descriptors. It relies on
+// /proc/self/fd and returns an error on platforms without it.
+func OpenFDs() (int, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, err
+	}
+	// Reading the directory holds one descriptor open itself
+	return len(entries) - 1, nil
+}
@@ -0,0 +1,116 @@
+// Package runtimestats samples Go runtime statistics in the background and
+// attaches the latest snapshot to slow request spans, so a slow trace shows
+// what the process looked like at the time.
+package runtimestats
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Snapshot is one sample of the runtime
+type Snapshot struct {
+	Goroutines     int       `json:"goroutines"`
+	HeapInUseBytes uint64    `json:"heap_inuse_bytes"`
+	HeapObjects    uint64    `json:"heap_objects"`
+	GCCount        uint32    `json:"gc_count"`
+	GCLastPauseMs  float64   `json:"gc_last_pause_ms"`
+	GCPauseTotalMs float64   `json:"gc_pause_total_ms"`
+	SampledAt      time.Time `json:"sampled_at"`
+}
+
+// Take samples the runtime now
+func Take() Snapshot {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	s := Snapshot{
+		Goroutines:     runtime.NumGoroutine(),
+		HeapInUseBytes: m.HeapInuse,
+		HeapObjects:    m.HeapObjects,
+		GCCount:        m.NumGC,
+		GCPauseTotalMs: float64(m.PauseTotalNs) / 1e6,
+		SampledAt:      time.Now(),
+	}
+	if m.NumGC > 0 {
+		s.GCLastPauseMs = float64(m.PauseNs[(m.NumGC+255)%256]) / 1e6
+	}
+	return s
+}
+
+// Attributes describes s as runtime.* span attributes
+func (s Snapshot) Attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int("runtime.goroutines", s.Goroutines),
+		attribute.Int64("runtime.heap_inuse_bytes", int64(s.HeapInUseBytes)),
+		attribute.Int64("runtime.heap_objects", int64(s.HeapObjects)),
+		attribute.Int64("runtime.gc_count", int64(s.GCCount)),
+		attribute.Float64("runtime.gc_last_pause_ms", s.GCLastPauseMs),
+		attribute.Float64("runtime.gc_pause_total_ms", s.GCPauseTotalMs),
+	}
+}
+
+// Sampler keeps the latest Snapshot, refreshed every interval. Reading
+// MemStats briefly stops the world, so requests read the cached sample
+// instead of taking their own.
+type Sampler struct {
+	interval time.Duration
+	latest   atomic.Pointer[Snapshot]
+}
+
+// NewSampler creates a sampler holding an initial snapshot
+func NewSampler(interval time.Duration) *Sampler {
+	s := &Sampler{interval: interval}
+	snap := Take()
+	s.latest.Store(&snap)
+	return s
+}
+
+// Latest returns the most recent snapshot
+func (s *Sampler) Latest() Snapshot {
+	return *s.latest.Load()
+}
+
+// Run refreshes the snapshot until ctx is canceled
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			snap := Take()
+			s.latest.Store(&snap)
+		}
+	}
+}
+
+// Middleware attaches the latest snapshot to the span of every request that
+// takes at least threshold, marked request.slow=true
+func (s *Sampler) Middleware(threshold time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The server span, before handlers swap in their own child spans
+		span := trace.SpanFromContext(c.Request.Context())
+		start := time.Now()
+		c.Next()
+		if time.Since(start) < threshold {
+			return
+		}
+
+		snap := s.Latest()
+		span.SetAttributes(snap.Attributes()...)
+		span.SetAttributes(
+			attribute.Bool("request.slow", true),
+			attribute.Int64("request.slow_threshold_ms", threshold.Milliseconds()),
+			attribute.Int64("runtime.snapshot_age_ms", time.Since(snap.SampledAt).Milliseconds()),
+		)
+	}
+}
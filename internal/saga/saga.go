@@ -0,0 +1,127 @@
+// Package saga runs a sequence of steps across services and, when one fails,
+// undoes the completed ones in reverse order. Every step and compensation
+// gets its own span, so both the happy path and the rollback are visible.
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+
+	"github.com/Tracekit-Dev/test-app/internal/retry"
+)
+
+// Status values recorded as saga.status
+const (
+	StatusCompleted          = "completed"
+	StatusCompensated        = "compensated"
+	StatusCompensationFailed = "compensation_failed"
+)
+
+// Step is one local transaction of a saga and the action that undoes it
+type Step struct {
+	Name   string
+	Action func(ctx context.Context) error
+
+	// Compensate undoes Action; nil when there is nothing to undo
+	Compensate func(ctx context.Context) error
+}
+
+// Error reports which step failed and whether the rollback succeeded
+type Error struct {
+	Step   string
+	Err    error
+	Status string
+
+	// CompensationErr joins the errors of compensations that failed
+	CompensationErr error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("saga step %s failed: %v", e.Step, e.Err)
+	if e.CompensationErr != nil {
+		msg += fmt.Sprintf(" (compensation failed: %v)", e.CompensationErr)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run executes steps in order inside a "saga <name>" span. If a step fails,
+// the steps before it are compensated newest first and an *Error is
+// returned. Compensations are retried and run even if ctx is cancelled,
+// since leaving a half-finished saga behind is worse than finishing late.
+func Run(ctx context.Context, sdk *tracekit.SDK, name string, steps []Step) error {
+	ctx, span := sdk.StartSpan(ctx, "saga "+name)
+	defer span.End()
+
+	sdk.AddAttribute(span, "saga.name", name)
+	sdk.AddIntAttribute(span, "saga.steps", int64(len(steps)))
+
+	for i, step := range steps {
+		if err := runStep(ctx, sdk, step); err != nil {
+			sdk.AddAttribute(span, "saga.failed_step", step.Name)
+			sdk.AddEvent(span, "saga.compensation_started")
+
+			compErr := compensate(context.WithoutCancel(ctx), sdk, steps[:i])
+			status := StatusCompensated
+			if compErr != nil {
+				status = StatusCompensationFailed
+			}
+			sdk.AddAttribute(span, "saga.status", status)
+
+			sagaErr := &Error{Step: step.Name, Err: err, Status: status, CompensationErr: compErr}
+			sdk.RecordError(span, sagaErr)
+			return sagaErr
+		}
+	}
+
+	sdk.AddAttribute(span, "saga.status", StatusCompleted)
+	sdk.SetSuccess(span)
+	return nil
+}
+
+// runStep runs one action in a "saga.step <name>" span
+func runStep(ctx context.Context, sdk *tracekit.SDK, step Step) error {
+	ctx, span := sdk.StartSpan(ctx, "saga.step "+step.Name)
+	defer span.End()
+
+	sdk.AddAttribute(span, "saga.step", step.Name)
+	if err := step.Action(ctx); err != nil {
+		sdk.RecordError(span, err)
+		return err
+	}
+	sdk.SetSuccess(span)
+	return nil
+}
+
+// compensate undoes done, newest first, each in a "saga.compensate <name>"
+// span with a span per attempt. It keeps going past failures so one stuck
+// compensation doesn't block the others.
+func compensate(ctx context.Context, sdk *tracekit.SDK, done []Step) error {
+	var errs []error
+	for i := len(done) - 1; i >= 0; i-- {
+		step := done[i]
+		if step.Compensate == nil {
+			continue
+		}
+
+		stepCtx, span := sdk.StartSpan(ctx, "saga.compensate "+step.Name)
+		sdk.AddAttribute(span, "saga.step", step.Name)
+
+		attempts, err := retry.Do(stepCtx, sdk, "saga.compensate", retry.Default, step.Compensate)
+		sdk.AddIntAttribute(span, "retry.attempts", int64(attempts))
+		if err != nil {
+			sdk.RecordError(span, err)
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, err))
+		} else {
+			sdk.SetSuccess(span)
+		}
+		span.End()
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,238 @@
+// Package sampling makes the app's head sampling decisions at the edge of
+// the Gin router: a default ratio, per-route ratios, paths that are never
+// traced, and whether to follow the caller's decision for requests that
+// arrive with one.
+package sampling
+
+import (
+	"crypto/rand"
+	mrand "math/rand"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Settings are the effective sampling settings
+type Settings struct {
+	// Ratio is the fraction of traces recorded, from 0 to 1
+	Ratio float64 `json:"ratio"`
+
+	// ParentBased follows the caller's decision when a request carries one
+	ParentBased bool `json:"parent_based"`
+
+	// Source names the setting Ratio came from
+	Source string `json:"source"`
+
+	// Routes override Ratio by gin route template. A key ending in "*" is a
+	// prefix covering a whole route group, e.g. "/api/admin/*".
+	Routes map[string]float64 `json:"routes,omitempty"`
+
+	// SkipPaths are request paths that are never traced, such as probes
+	// and metrics scrapes. A path ending in "*" skips everything under it.
+	SkipPaths []string `json:"skip_paths,omitempty"`
+}
+
+// Decision describes how the sampler treated one request
+type Decision struct {
+	Route string `json:"route"`
+
+	// Ratio is the ratio that applied to the route
+	Ratio float64 `json:"ratio"`
+
+	HadParent     bool `json:"had_parent"`
+	ParentSampled bool `json:"parent_sampled"`
+
+	// Overridden reports that the caller's decision was replaced
+	Overridden bool `json:"overridden"`
+
+	// Dropped reports that the sampler dropped the trace before the SDK
+	// saw the request
+	Dropped bool `json:"dropped"`
+
+	// Skipped reports that the path is excluded from tracing
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// decisionKey stores a request's Decision in the gin context
+const decisionKey = "sampling.decision"
+
+// Sampler applies Settings in front of the SDK's middleware. The SDK is
+// configured with SDKRatio, the highest ratio of any route, and samples
+// root traces at that ratio. The sampler lowers it per route by dropping
+// the remaining share of root requests before the SDK sees them, so a
+// route at 1% and another at 100% can coexist.
+type Sampler struct {
+	sdkRatio float64
+	rules    atomic.Pointer[rules]
+	tc       propagation.TraceContext
+}
+
+// rules are Settings compiled for lookups on every request
+type rules struct {
+	settings     Settings
+	exact        map[string]float64
+	prefixes     []string
+	skip         map[string]bool
+	skipPrefixes []string
+}
+
+// New creates a sampler for s
+func New(s Settings) *Sampler {
+	smp := &Sampler{sdkRatio: maxRatio(s)}
+	smp.rules.Store(compile(s))
+	return smp
+}
+
+// Update replaces the settings of a running sampler. The SDK keeps the
+// ratio it started with, so it reports whether any ratio is now above
+// SDKRatio, which caps it until a restart.
+func (s *Sampler) Update(settings Settings) (capped bool) {
+	s.rules.Store(compile(settings))
+	return maxRatio(settings) > s.sdkRatio
+}
+
+// maxRatio is the highest ratio of s, default or per route
+func maxRatio(s Settings) float64 {
+	ratio := s.Ratio
+	for _, r := range s.Routes {
+		ratio = max(ratio, r)
+	}
+	return ratio
+}
+
+func compile(s Settings) *rules {
+	r := &rules{
+		settings: s,
+		exact:    make(map[string]float64),
+		skip:     make(map[string]bool, len(s.SkipPaths)),
+	}
+	for route, ratio := range s.Routes {
+		if prefix, ok := strings.CutSuffix(route, "*"); ok {
+			r.prefixes = append(r.prefixes, prefix)
+		} else {
+			r.exact[route] = ratio
+		}
+	}
+	// Longest prefix wins, so nested groups can differ from their parent
+	sort.Slice(r.prefixes, func(i, j int) bool { return len(r.prefixes[i]) > len(r.prefixes[j]) })
+
+	for _, path := range s.SkipPaths {
+		if prefix, ok := strings.CutSuffix(path, "*"); ok {
+			r.skipPrefixes = append(r.skipPrefixes, prefix)
+			continue
+		}
+		r.skip[path] = true
+	}
+	return r
+}
+
+// Settings returns the effective settings
+func (s *Sampler) Settings() Settings {
+	return s.rules.Load().settings
+}
+
+// SDKRatio is the sampling rate to configure the SDK with
+func (s *Sampler) SDKRatio() float64 {
+	return s.sdkRatio
+}
+
+// ratioFor returns the ratio of a gin route template
+func (r *rules) ratioFor(route string) float64 {
+	if ratio, ok := r.exact[route]; ok {
+		return ratio
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(route, prefix) {
+			return r.settings.Routes[prefix+"*"]
+		}
+	}
+	return r.settings.Ratio
+}
+
+// skipped reports whether a request path is excluded from tracing
+func (r *rules) skipped(path string) bool {
+	if r.skip[path] {
+		return true
+	}
+	for _, prefix := range r.skipPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware wraps the SDK's middleware. Requests to SkipPaths bypass it
+// entirely, whatever the caller decided. A request carrying a caller's
+// decision follows it unless ParentBased is off, in which case the sampled
+// flag of its traceparent is replaced with the route ratio's decision for
+// its trace ID. Any other request is kept at the route's ratio; a dropped
+// request bypasses sdkMiddleware and runs under an unsampled span context,
+// so nothing below it is recorded and downstream services see sampled=0.
+func (s *Sampler) Middleware(sdkMiddleware gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := s.rules.Load()
+		carrier := propagation.HeaderCarrier(c.Request.Header)
+		parent := trace.SpanContextFromContext(s.tc.Extract(c.Request.Context(), carrier))
+
+		d := Decision{
+			Route:         c.FullPath(),
+			HadParent:     parent.IsValid(),
+			ParentSampled: parent.IsSampled(),
+		}
+		d.Ratio = r.ratioFor(d.Route)
+
+		switch {
+		case r.skipped(c.Request.URL.Path):
+			d.Skipped = true
+			d.Dropped = true
+		case d.HadParent && r.settings.ParentBased:
+			// The caller decided; the SDK follows it
+		case d.HadParent:
+			sampled := sdktrace.TraceIDRatioBased(d.Ratio).ShouldSample(sdktrace.SamplingParameters{
+				ParentContext: c.Request.Context(),
+				TraceID:       parent.TraceID(),
+			}).Decision == sdktrace.RecordAndSample
+
+			if sampled != parent.IsSampled() {
+				flags := parent.TraceFlags().WithSampled(sampled)
+				rewritten := trace.ContextWithRemoteSpanContext(c.Request.Context(), parent.WithTraceFlags(flags))
+				s.tc.Inject(rewritten, carrier)
+				d.Overridden = true
+			}
+		case d.Ratio < s.sdkRatio:
+			// The SDK keeps sdkRatio of what it sees, so let it see
+			// ratio/sdkRatio of the requests
+			d.Dropped = mrand.Float64() >= d.Ratio/s.sdkRatio
+		}
+		c.Set(decisionKey, d)
+
+		if d.Dropped {
+			c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), unsampled()))
+			c.Next()
+			return
+		}
+		sdkMiddleware(c)
+	}
+}
+
+// unsampled returns a span context with fresh IDs and the sampled flag off
+func unsampled() trace.SpanContext {
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	rand.Read(traceID[:])
+	rand.Read(spanID[:])
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+}
+
+// DecisionFrom returns the decision the middleware recorded for c
+func DecisionFrom(c *gin.Context) Decision {
+	d, _ := c.Get(decisionKey)
+	decision, _ := d.(Decision)
+	return decision
+}
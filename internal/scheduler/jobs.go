@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+
+	"github.com/Tracekit-Dev/test-app/internal/runtimestats"
+)
+
+// Cleanup simulates purging expired sessions
+func Cleanup(sdk *tracekit.SDK) JobFunc {
+	return func(ctx context.Context) error {
+		ctx, span := sdk.StartSpan(ctx, "purgeExpiredSessions")
+		defer span.End()
+
+		time.Sleep(time.Duration(20+rand.Intn(40)) * time.Millisecond)
+
+		sdk.AddIntAttribute(span, "sessions.purged", int64(rand.Intn(25)))
+		sdk.SetSuccess(span)
+		return nil
+	}
+}
+
+// Report simulates building and storing a daily sales report
+func Report(sdk *tracekit.SDK) JobFunc {
+	return func(ctx context.Context) error {
+		_, aggregate := sdk.StartSpan(ctx, "aggregateOrders")
+		time.Sleep(time.Duration(80+rand.Intn(120)) * time.Millisecond)
+		sdk.AddIntAttribute(aggregate, "orders.aggregated", int64(100+rand.Intn(900)))
+		sdk.SetSuccess(aggregate)
+		aggregate.End()
+
+		_, store := sdk.StartSpan(ctx, "storeReport")
+		time.Sleep(30 * time.Millisecond)
+		sdk.AddAttribute(store, "report.format", "csv")
+		sdk.SetSuccess(store)
+		store.End()
+
+		return nil
+	}
+}
+
+// Runtime records baseline process telemetry: GC stats, heap, goroutines,
+// and open file descriptors, as span attributes and as gauges
+func Runtime(sdk *tracekit.SDK) JobFunc {
+	goroutines := sdk.Gauge("runtime.goroutines", nil)
+	heapInUse := sdk.Gauge("runtime.heap_inuse_bytes", nil)
+	gcPause := sdk.Gauge("runtime.gc_pause_total_ms", nil)
+	openFDs := sdk.Gauge("process.open_fds", nil)
+
+	return func(ctx context.Context) error {
+		_, span := sdk.StartSpan(ctx, "reportRuntime")
+		defer span.End()
+
+		snap := runtimestats.Take()
+		span.SetAttributes(snap.Attributes()...)
+		goroutines.Set(float64(snap.Goroutines))
+		heapInUse.Set(float64(snap.HeapInUseBytes))
+		gcPause.Set(snap.GCPauseTotalMs)
+
+		// Not every platform can count descriptors; that isn't a job failure
+		if fds, err := runtimestats.OpenFDs(); err == nil {
+			sdk.AddIntAttribute(span, "process.open_fds", int64(fds))
+			openFDs.Set(float64(fds))
+		} else {
+			sdk.AddEvent(span, "process.open_fds_unavailable")
+		}
+
+		sdk.SetSuccess(span)
+		return nil
+	}
+}
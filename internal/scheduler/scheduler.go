@@ -0,0 +1,84 @@
+// Package scheduler runs periodic jobs that aren't triggered by a request.
+// Every execution starts a new root span carrying the job name, schedule,
+// and duration.
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// JobFunc is the work done by one execution of a scheduled job
+type JobFunc func(ctx context.Context) error
+
+// Scheduler wraps a cron runner with tracing
+type Scheduler struct {
+	cron *cron.Cron
+	sdk  *tracekit.SDK
+}
+
+// New creates an empty scheduler
+func New(sdk *tracekit.SDK) *Scheduler {
+	return &Scheduler{
+		cron: cron.New(),
+		sdk:  sdk,
+	}
+}
+
+// Add schedules fn under name. schedule is a cron expression such as
+// "*/5 * * * *" or a descriptor such as "@every 1m".
+func (s *Scheduler) Add(name, schedule string, fn JobFunc) error {
+	var runs atomic.Int64
+	_, err := s.cron.AddFunc(schedule, func() {
+		s.execute(name, schedule, runs.Add(1), fn)
+	})
+	return err
+}
+
+// Run starts the scheduler and blocks until ctx is canceled, then waits for
+// running jobs to finish
+func (s *Scheduler) Run(ctx context.Context) {
+	s.cron.Start()
+	<-ctx.Done()
+	<-s.cron.Stop().Done()
+}
+
+// execute runs fn inside a new root span
+func (s *Scheduler) execute(name, schedule string, run int64, fn JobFunc) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "cron."+name, trace.WithNewRoot())
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "job.name", name)
+	s.sdk.AddAttribute(span, "job.schedule", schedule)
+	s.sdk.AddIntAttribute(span, "job.run", run)
+
+	start := time.Now()
+	err := fn(ctx)
+	duration := time.Since(start)
+	s.sdk.AddIntAttribute(span, "job.duration_ms", duration.Milliseconds())
+
+	if err != nil {
+		s.sdk.RecordError(span, err)
+		logging.FromContext(ctx).Error("⏰ Scheduled job failed",
+			zap.String("job.name", name),
+			zap.Duration("duration", duration.Round(time.Millisecond)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	s.sdk.SetSuccess(span)
+	logging.FromContext(ctx).Info("⏰ Scheduled job completed",
+		zap.String("job.name", name),
+		zap.Duration("duration", duration.Round(time.Millisecond)),
+	)
+}
@@ -0,0 +1,113 @@
+package soap
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OrdersNS is the namespace of the order status service's messages
+const OrdersNS = "urn:tracekit:orders"
+
+// ActionGetOrderStatus is the SOAPAction of the one operation the service has
+const ActionGetOrderStatus = OrdersNS + "/GetOrderStatus"
+
+// GetOrderStatus asks for the status of an order
+type GetOrderStatus struct {
+	XMLName xml.Name `xml:"urn:tracekit:orders GetOrderStatus"`
+	OrderID string   `xml:"OrderId"`
+}
+
+// GetOrderStatusResponse is the answer to GetOrderStatus
+type GetOrderStatusResponse struct {
+	XMLName  xml.Name `xml:"urn:tracekit:orders GetOrderStatusResponse" json:"-"`
+	OrderID  string   `xml:"OrderId" json:"order_id"`
+	Status   string   `xml:"Status" json:"status"`
+	Amount   float64  `xml:"Amount" json:"amount"`
+	Currency string   `xml:"Currency" json:"currency"`
+}
+
+// legacyOrders are the orders the mock service knows about
+var legacyOrders = map[string]GetOrderStatusResponse{
+	"ORD-1001": {OrderID: "ORD-1001", Status: "SHIPPED", Amount: 129.99, Currency: "USD"},
+	"ORD-1002": {OrderID: "ORD-1002", Status: "PENDING", Amount: 42.50, Currency: "USD"},
+	"ORD-1003": {OrderID: "ORD-1003", Status: "DELIVERED", Amount: 310.00, Currency: "USD"},
+}
+
+// service plays a legacy SOAP 1.1 order status service
+type service struct {
+	sdk *tracekit.SDK
+}
+
+// NewService returns the mock order status service. Unknown actions,
+// malformed envelopes, and unknown orders are answered with faults.
+func NewService(sdk *tracekit.SDK) http.Handler {
+	return &service{sdk: sdk}
+}
+
+func (s *service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, span := s.sdk.StartSpan(r.Context(), "soap GetOrderStatus")
+	defer span.End()
+
+	action := strings.Trim(r.Header.Get("SOAPAction"), `"`)
+	s.sdk.AddAttribute(span, "soap.action", action)
+	if action != ActionGetOrderStatus {
+		s.fault(span, w, "soap:Client", "Unknown SOAPAction "+action)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.fault(span, w, "soap:Client", "Failed to read request")
+		return
+	}
+	var env received
+	var req GetOrderStatus
+	if err := xml.Unmarshal(body, &env); err != nil {
+		s.fault(span, w, "soap:Client", "Malformed envelope: "+err.Error())
+		return
+	}
+	if err := xml.Unmarshal(env.Body.Content, &req); err != nil {
+		s.fault(span, w, "soap:Client", "Malformed GetOrderStatus: "+err.Error())
+		return
+	}
+	s.sdk.AddAttribute(span, "order.id", req.OrderID)
+
+	order, ok := legacyOrders[req.OrderID]
+	if !ok {
+		s.fault(span, w, "soap:Client", "Order "+req.OrderID+" not found")
+		return
+	}
+	out, err := wrap(order)
+	if err != nil {
+		s.fault(span, w, "soap:Server", err.Error())
+		return
+	}
+	s.sdk.SetSuccess(span)
+	w.Header().Set("Content-Type", ContentType)
+	w.Write(out)
+}
+
+// fault answers with a SOAP fault, sent with a 500 as SOAP 1.1 requires.
+// Only soap:Server faults mark the span failed; soap:Client ones are the
+// caller's mistake.
+func (s *service) fault(span trace.Span, w http.ResponseWriter, code, message string) {
+	f := &Fault{Code: code, String: message}
+	s.sdk.AddAttribute(span, "soap.fault.code", code)
+	s.sdk.AddAttribute(span, "soap.fault.string", message)
+	if code == "soap:Server" {
+		s.sdk.RecordError(span, f)
+	}
+
+	out, _ := wrap(struct {
+		XMLName xml.Name `xml:"soap:Fault"`
+		*Fault
+	}{Fault: f})
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(500)
+	w.Write(out)
+}
@@ -0,0 +1,161 @@
+// Package soap is a minimal SOAP 1.1 client and the mock legacy order status
+// service it calls. A call is traced as three child spans: building the XML
+// envelope, the HTTP call, and parsing the response.
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+)
+
+// envelopeNS is the SOAP 1.1 envelope namespace
+const envelopeNS = "http://schemas.xmlsoap.org/soap/envelope/"
+
+// ContentType is the content type of SOAP 1.1 messages
+const ContentType = "text/xml; charset=utf-8"
+
+// envelope wraps an outgoing message. encoding/xml can't write namespace
+// prefixes, so the soap: prefix is spelled out in the element names.
+type envelope struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	NS      string   `xml:"xmlns:soap,attr"`
+	Body    struct {
+		Content any
+	} `xml:"soap:Body"`
+}
+
+// received is an incoming envelope, matched by local names. The body is
+// kept raw until it's known whether it holds a fault.
+type received struct {
+	Body struct {
+		Fault   *Fault `xml:"Fault"`
+		Content []byte `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// Fault is a SOAP 1.1 fault, returned by Call as an error
+type Fault struct {
+	Code   string `xml:"faultcode"`
+	String string `xml:"faultstring"`
+}
+
+func (f *Fault) Error() string {
+	return "soap fault " + f.Code + ": " + f.String
+}
+
+// wrap puts content in an envelope and encodes it
+func wrap(content any) ([]byte, error) {
+	env := envelope{NS: envelopeNS}
+	env.Body.Content = content
+	out, err := xml.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// Call posts req to the SOAP endpoint at url with the given SOAPAction and
+// decodes the response body into resp. A fault from the service is returned
+// as a *Fault.
+func Call(ctx context.Context, sdk *tracekit.SDK, client *http.Client, url, action string, req, resp any) error {
+	payload, err := marshal(ctx, sdk, action, req)
+	if err != nil {
+		return err
+	}
+	body, err := call(ctx, sdk, client, url, action, payload)
+	if err != nil {
+		return err
+	}
+	return unmarshal(ctx, sdk, action, body, resp)
+}
+
+// marshal builds the request envelope in a "soap.marshal" span
+func marshal(ctx context.Context, sdk *tracekit.SDK, action string, req any) ([]byte, error) {
+	_, span := sdk.StartSpan(ctx, "soap.marshal")
+	defer span.End()
+
+	sdk.AddAttribute(span, "soap.action", action)
+	payload, err := wrap(req)
+	if err != nil {
+		sdk.RecordError(span, err)
+		return nil, fmt.Errorf("build soap envelope: %w", err)
+	}
+	sdk.AddIntAttribute(span, "soap.envelope.size_bytes", int64(len(payload)))
+	sdk.SetSuccess(span)
+	return payload, nil
+}
+
+// call posts the envelope in a "soap.call" span; the instrumented client
+// adds its CLIENT span beneath it. SOAP 1.1 sends faults with a 500, so
+// that status still returns the body.
+func call(ctx context.Context, sdk *tracekit.SDK, client *http.Client, url, action string, payload []byte) ([]byte, error) {
+	ctx, span := sdk.StartSpan(ctx, "soap.call")
+	defer span.End()
+
+	sdk.AddAttribute(span, "soap.action", action)
+	sdk.AddAttribute(span, "soap.version", "1.1")
+	sdk.AddAttribute(span, "server.url", url)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		sdk.RecordError(span, err)
+		return nil, err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("SOAPAction", `"`+action+`"`)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		sdk.RecordError(span, err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		sdk.RecordError(span, err)
+		return nil, err
+	}
+	sdk.AddIntAttribute(span, "http.response.status_code", int64(resp.StatusCode))
+	if resp.StatusCode != 200 && resp.StatusCode != 500 {
+		err := fmt.Errorf("soap endpoint answered %d", resp.StatusCode)
+		sdk.RecordError(span, err)
+		return nil, err
+	}
+	sdk.SetSuccess(span)
+	return body, nil
+}
+
+// unmarshal parses the response envelope into resp in a "soap.unmarshal"
+// span. A fault is parsed successfully, so it's an event here and the
+// caller decides what it means.
+func unmarshal(ctx context.Context, sdk *tracekit.SDK, action string, body []byte, resp any) error {
+	_, span := sdk.StartSpan(ctx, "soap.unmarshal")
+	defer span.End()
+
+	sdk.AddAttribute(span, "soap.action", action)
+	sdk.AddIntAttribute(span, "soap.envelope.size_bytes", int64(len(body)))
+
+	var env received
+	if err := xml.Unmarshal(body, &env); err != nil {
+		sdk.RecordError(span, err)
+		return fmt.Errorf("parse soap envelope: %w", err)
+	}
+	if env.Body.Fault != nil {
+		sdk.AddEvent(span, "soap.fault")
+		sdk.SetSuccess(span)
+		return env.Body.Fault
+	}
+	if err := xml.Unmarshal(env.Body.Content, resp); err != nil {
+		sdk.RecordError(span, err)
+		return fmt.Errorf("parse soap body: %w", err)
+	}
+	sdk.SetSuccess(span)
+	return nil
+}
@@ -0,0 +1,9 @@
+// Click a curl command on the status page to copy it
+document.querySelectorAll("td code").forEach(function (el) {
+  if (!el.textContent.startsWith("curl ")) return;
+  el.title = "Click to copy";
+  el.style.cursor = "pointer";
+  el.addEventListener("click", function () {
+    navigator.clipboard.writeText(el.textContent);
+  });
+});
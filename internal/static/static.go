@@ -0,0 +1,118 @@
+// Package static serves the embedded assets under /static with a span per
+// file recording its path, size, and caching headers.
+package static
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// CacheControl is sent with every asset
+const CacheControl = "public, max-age=3600"
+
+// Prefix is the URL path the assets are served under
+const Prefix = "/static/"
+
+// Server serves the embedded assets
+type Server struct {
+	sdk   *tracekit.SDK
+	files fs.FS
+
+	// etags are content hashes, computed once since the files never change
+	etags map[string]string
+}
+
+// New creates a Server for the embedded assets
+func New(sdk *tracekit.SDK) (*Server, error) {
+	files, err := fs.Sub(assets, "assets")
+	if err != nil {
+		return nil, err
+	}
+
+	etags := map[string]string{}
+	err = fs.WalkDir(files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		etags[name] = `"` + hex.EncodeToString(sum[:8]) + `"`
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Server{sdk: sdk, files: files, etags: etags}, nil
+}
+
+// ServeHTTP serves the asset named by the path after Prefix. Conditional
+// requests with a matching ETag get 304 Not Modified.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.sdk.StartSpan(r.Context(), "static.serve")
+	defer span.End()
+
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), strings.TrimSuffix(Prefix, "/"))
+	name = strings.TrimPrefix(name, "/")
+	s.sdk.AddAttribute(span, "static.file.path", name)
+
+	etag, ok := s.etags[name]
+	if !ok {
+		s.sdk.AddEvent(span, "static.not_found")
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := s.files.Open(name)
+	if err != nil {
+		s.sdk.RecordError(span, err)
+		http.Error(w, "failed to open asset", 500)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		s.sdk.RecordError(span, err)
+		http.Error(w, "failed to stat asset", 500)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", CacheControl)
+	w.Header().Set("ETag", etag)
+
+	notModified := r.Header.Get("If-None-Match") == etag
+	span.SetAttributes(
+		attribute.Int64("static.file.size", info.Size()),
+		attribute.String("static.file.content_type", contentType),
+		attribute.String("http.response.cache_control", CacheControl),
+		attribute.String("http.response.etag", etag),
+		attribute.Bool("static.not_modified", notModified),
+	)
+
+	// Embedded files have no modification time; the ETag drives caching.
+	// ServeContent answers If-None-Match and Range requests itself.
+	http.ServeContent(w, r.WithContext(ctx), name, time.Time{}, f.(io.ReadSeeker))
+	s.sdk.SetSuccess(span)
+}
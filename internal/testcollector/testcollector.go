@@ -0,0 +1,336 @@
+// Package testcollector is an in-memory stand-in for the TraceKit exporter
+// endpoint. It accepts OTLP/HTTP trace exports (protobuf or JSON, optionally
+// gzipped) and keeps the spans, so an integration test can assert that a
+// request produced the expected spans without a live backend:
+//
+//	col := testcollector.Start()
+//	defer col.Close()
+//	// point TRACEKIT_ENDPOINT at col.Endpoint(), with TRACEKIT_USE_SSL=false
+//	spans, err := col.WaitFor(5*time.Second, 1, testcollector.Named("createOrder"))
+//
+// Processor records spans in-process instead, for tests that install their
+// own tracer provider.
+package testcollector
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Span is a received span, flattened for assertions
+type Span struct {
+	Service       string
+	Name          string
+	TraceID       string
+	SpanID        string
+	ParentSpanID  string
+	Kind          string
+	StatusCode    string
+	StatusMessage string
+	Attributes    map[string]any
+	Events        []string
+}
+
+// Attr returns the attribute value as a string, or "" when it is missing
+func (s Span) Attr(key string) string {
+	v, ok := s.Attributes[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// Collector stores every span exported to it
+type Collector struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	spans   []Span
+	exports int
+	changed chan struct{}
+}
+
+// New creates a collector without a listener; mount it as an http.Handler
+// or use Processor
+func New() *Collector {
+	return &Collector{changed: make(chan struct{})}
+}
+
+// Start creates a collector listening on a local port
+func Start() *Collector {
+	c := New()
+	c.server = httptest.NewServer(c)
+	return c
+}
+
+// Endpoint is the host:port of a started collector
+func (c *Collector) Endpoint() string {
+	return strings.TrimPrefix(c.server.URL, "http://")
+}
+
+// URL is the base URL of a started collector
+func (c *Collector) URL() string {
+	return c.server.URL
+}
+
+// Close stops a started collector
+func (c *Collector) Close() {
+	if c.server != nil {
+		c.server.Close()
+	}
+}
+
+// ServeHTTP accepts an OTLP/HTTP trace export on any path, so it works
+// whatever path the exporter appends to the endpoint
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST an OTLP trace export", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	var req coltracepb.ExportTraceServiceRequest
+	if isJSON {
+		err = protojson.Unmarshal(raw, &req)
+	} else {
+		err = proto.Unmarshal(raw, &req)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.add(fromOTLP(&req)...)
+
+	var resp []byte
+	if isJSON {
+		w.Header().Set("Content-Type", "application/json")
+		resp, _ = protojson.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	} else {
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		resp, _ = proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	}
+	w.Write(resp)
+}
+
+// Spans returns every span received so far, in arrival order
+func (c *Collector) Spans() []Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Span(nil), c.spans...)
+}
+
+// Find returns the received spans that match
+func (c *Collector) Find(match func(Span) bool) []Span {
+	var found []Span
+	for _, s := range c.Spans() {
+		if match(s) {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+// Exports counts the export requests received so far; Processor counts
+// each span as one
+func (c *Collector) Exports() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.exports
+}
+
+// Reset forgets every received span
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	c.spans = nil
+	c.exports = 0
+	c.mu.Unlock()
+}
+
+// WaitFor waits until at least n received spans match, since exporters send
+// in batches some time after the spans end. It returns the matches so far
+// and an error when timeout passes first.
+func (c *Collector) WaitFor(timeout time.Duration, n int, match func(Span) bool) ([]Span, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		c.mu.Lock()
+		changed := c.changed
+		c.mu.Unlock()
+
+		found := c.Find(match)
+		if len(found) >= n {
+			return found, nil
+		}
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return found, fmt.Errorf("testcollector: %d of %d matching spans after %s", len(found), n, timeout)
+		}
+	}
+}
+
+// Named matches spans by name
+func Named(name string) func(Span) bool {
+	return func(s Span) bool { return s.Name == name }
+}
+
+// InTrace matches the spans of one trace
+func InTrace(traceID string) func(Span) bool {
+	return func(s Span) bool { return s.TraceID == traceID }
+}
+
+func (c *Collector) add(spans ...Span) {
+	c.mu.Lock()
+	c.spans = append(c.spans, spans...)
+	c.exports++
+	close(c.changed)
+	c.changed = make(chan struct{})
+	c.mu.Unlock()
+}
+
+// Processor returns a span processor that records ended spans directly,
+// without the OTLP round trip
+func (c *Collector) Processor() sdktrace.SpanProcessor {
+	return processor{c}
+}
+
+type processor struct{ c *Collector }
+
+func (p processor) OnEnd(s sdktrace.ReadOnlySpan) {
+	span := Span{
+		Name:          s.Name(),
+		TraceID:       s.SpanContext().TraceID().String(),
+		SpanID:        s.SpanContext().SpanID().String(),
+		Kind:          s.SpanKind().String(),
+		StatusCode:    s.Status().Code.String(),
+		StatusMessage: s.Status().Description,
+		Attributes:    make(map[string]any),
+	}
+	if s.Parent().IsValid() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	if svc, ok := s.Resource().Set().Value("service.name"); ok {
+		span.Service = svc.AsString()
+	}
+	for _, kv := range s.Attributes() {
+		span.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, e.Name)
+	}
+	p.c.add(span)
+}
+
+func (processor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (processor) Shutdown(context.Context) error                  { return nil }
+func (processor) ForceFlush(context.Context) error                { return nil }
+
+// fromOTLP flattens an export request into spans
+func fromOTLP(req *coltracepb.ExportTraceServiceRequest) []Span {
+	var spans []Span
+	for _, rs := range req.GetResourceSpans() {
+		service := ""
+		for _, kv := range rs.GetResource().GetAttributes() {
+			if kv.GetKey() == "service.name" {
+				service = kv.GetValue().GetStringValue()
+			}
+		}
+		for _, ss := range rs.GetScopeSpans() {
+			for _, s := range ss.GetSpans() {
+				span := Span{
+					Service:       service,
+					Name:          s.GetName(),
+					TraceID:       hex.EncodeToString(s.GetTraceId()),
+					SpanID:        hex.EncodeToString(s.GetSpanId()),
+					Kind:          trace.SpanKind(s.GetKind()).String(),
+					StatusCode:    statusCode(s.GetStatus().GetCode()),
+					StatusMessage: s.GetStatus().GetMessage(),
+					Attributes:    attributes(s.GetAttributes()),
+				}
+				if len(s.GetParentSpanId()) > 0 {
+					span.ParentSpanID = hex.EncodeToString(s.GetParentSpanId())
+				}
+				for _, e := range s.GetEvents() {
+					span.Events = append(span.Events, e.GetName())
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans
+}
+
+// statusCode names OTLP status codes the way codes.Code does
+func statusCode(code tracepb.Status_StatusCode) string {
+	switch code {
+	case tracepb.Status_STATUS_CODE_OK:
+		return "Ok"
+	case tracepb.Status_STATUS_CODE_ERROR:
+		return "Error"
+	default:
+		return "Unset"
+	}
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]any {
+	m := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		m[kv.GetKey()] = value(kv.GetValue())
+	}
+	return m
+}
+
+func value(v *commonpb.AnyValue) any {
+	switch v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.GetStringValue()
+	case *commonpb.AnyValue_BoolValue:
+		return v.GetBoolValue()
+	case *commonpb.AnyValue_IntValue:
+		return v.GetIntValue()
+	case *commonpb.AnyValue_DoubleValue:
+		return v.GetDoubleValue()
+	case *commonpb.AnyValue_ArrayValue:
+		var out []any
+		for _, el := range v.GetArrayValue().GetValues() {
+			out = append(out, value(el))
+		}
+		return out
+	case *commonpb.AnyValue_BytesValue:
+		return v.GetBytesValue()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,207 @@
+package testcollector_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Tracekit-Dev/test-app/internal/testcollector"
+)
+
+var (
+	traceID = []byte{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}
+	spanID  = []byte{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31}
+	parent  = []byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+)
+
+// export is a request with one createOrder span, the way an exporter sends it
+func export() *coltracepb.ExportTraceServiceRequest {
+	str := func(s string) *commonpb.AnyValue {
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+	}
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				{Key: "service.name", Value: str("go-test-app")},
+			}},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{
+					TraceId:      traceID,
+					SpanId:       spanID,
+					ParentSpanId: parent,
+					Name:         "createOrder",
+					Kind:         tracepb.Span_SPAN_KIND_INTERNAL,
+					Attributes: []*commonpb.KeyValue{
+						{Key: "order.id", Value: str("ORD-1")},
+						{Key: "order.quantity", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 3}}},
+						{Key: "order.paid", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}}},
+					},
+					Events: []*tracepb.Span_Event{{Name: "order.created"}},
+					Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "out of stock"},
+				}},
+			}},
+		}},
+	}
+}
+
+func post(t *testing.T, col *testcollector.Collector, contentType string, body []byte, gzipped bool) *http.Response {
+	t.Helper()
+	if gzipped {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(body)
+		gz.Close()
+		body = buf.Bytes()
+	}
+	req, _ := http.NewRequest(http.MethodPost, col.URL()+"/v1/traces", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func checkExported(t *testing.T, spans []testcollector.Span) {
+	t.Helper()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	s := spans[0]
+	if s.Service != "go-test-app" || s.Name != "createOrder" || s.Kind != "internal" {
+		t.Errorf("got service %q, name %q, kind %q", s.Service, s.Name, s.Kind)
+	}
+	if s.TraceID != "0af7651916cd43dd8448eb211c80319c" || s.SpanID != "b7ad6b7169203331" || s.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("got trace %s, span %s, parent %s", s.TraceID, s.SpanID, s.ParentSpanID)
+	}
+	if s.StatusCode != "Error" || s.StatusMessage != "out of stock" {
+		t.Errorf("got status %s %q", s.StatusCode, s.StatusMessage)
+	}
+	if s.Attr("order.id") != "ORD-1" || s.Attr("order.quantity") != "3" || s.Attr("order.paid") != "true" || s.Attr("missing") != "" {
+		t.Errorf("got attributes %v", s.Attributes)
+	}
+	if len(s.Events) != 1 || s.Events[0] != "order.created" {
+		t.Errorf("got events %v", s.Events)
+	}
+}
+
+func TestExportProtobuf(t *testing.T) {
+	col := testcollector.Start()
+	defer col.Close()
+
+	body, _ := proto.Marshal(export())
+	if resp := post(t, col, "application/x-protobuf", body, true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	checkExported(t, col.Spans())
+	if col.Exports() != 1 {
+		t.Errorf("got %d exports, want 1", col.Exports())
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	col := testcollector.Start()
+	defer col.Close()
+
+	body, _ := protojson.Marshal(export())
+	if resp := post(t, col, "application/json", body, false); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	checkExported(t, col.Spans())
+
+	col.Reset()
+	if n := len(col.Spans()); n != 0 {
+		t.Errorf("got %d spans after Reset", n)
+	}
+}
+
+func TestExportRejected(t *testing.T) {
+	col := testcollector.Start()
+	defer col.Close()
+
+	if resp := post(t, col, "application/x-protobuf", []byte("not protobuf"), false); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("garbage got status %d, want 400", resp.StatusCode)
+	}
+	resp, err := http.Get(col.URL() + "/v1/traces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET got status %d, want 405", resp.StatusCode)
+	}
+	if n := len(col.Spans()); n != 0 {
+		t.Errorf("got %d spans from rejected exports", n)
+	}
+}
+
+func TestProcessor(t *testing.T) {
+	col := testcollector.New()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(col.Processor()))
+	defer tp.Shutdown(context.Background())
+
+	ctx, root := tp.Tracer("test").Start(context.Background(), "GET /api/order", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tp.Tracer("test").Start(ctx, "createOrder")
+	child.SetAttributes(attribute.String("order.id", "ORD-1"), attribute.Int("order.quantity", 3))
+	child.AddEvent("order.created")
+	child.SetStatus(codes.Error, "out of stock")
+	child.End()
+	root.End()
+
+	spans, err := col.WaitFor(time.Second, 2, testcollector.InTrace(root.SpanContext().TraceID().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := col.Find(testcollector.Named("createOrder"))
+	if len(created) != 1 {
+		t.Fatalf("got %d createOrder spans in %v", len(created), spans)
+	}
+	s := created[0]
+	if s.ParentSpanID != root.SpanContext().SpanID().String() || s.Kind != "internal" {
+		t.Errorf("got parent %s, kind %s", s.ParentSpanID, s.Kind)
+	}
+	if s.Attr("order.id") != "ORD-1" || s.Attr("order.quantity") != "3" || s.StatusCode != "Error" {
+		t.Errorf("got attributes %v, status %s", s.Attributes, s.StatusCode)
+	}
+	if len(s.Events) != 1 || s.Events[0] != "order.created" {
+		t.Errorf("got events %v", s.Events)
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	col := testcollector.New()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(col.Processor()))
+	defer tp.Shutdown(context.Background())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, span := tp.Tracer("test").Start(context.Background(), "late")
+		span.End()
+	}()
+	if _, err := col.WaitFor(5*time.Second, 1, testcollector.Named("late")); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := col.WaitFor(50*time.Millisecond, 2, testcollector.Named("late"))
+	if err == nil || len(found) != 1 {
+		t.Errorf("got %d spans and error %v, want 1 and a timeout", len(found), err)
+	}
+}
@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RouteNames names the server span after the gin route template instead of
+// the concrete path, so /api/products/1 and /api/products/2 share the span
+// name "GET /api/products/:id" and span names stay low-cardinality. The raw
+// values of the path parameters are recorded as http.route.param.<name>
+// attributes. Register it right after the SDK middleware.
+func RouteNames() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span := trace.SpanFromContext(c.Request.Context())
+
+		route := c.FullPath()
+		if route == "" {
+			// Unmatched paths are unbounded; the method alone is the name
+			span.SetName(c.Request.Method)
+			c.Next()
+			return
+		}
+
+		attrs := make([]attribute.KeyValue, 0, len(c.Params)+1)
+		attrs = append(attrs, attribute.String("http.route", route))
+		for _, p := range c.Params {
+			attrs = append(attrs, attribute.String("http.route.param."+p.Key, p.Value))
+		}
+		span.SetName(c.Request.Method + " " + route)
+		span.SetAttributes(attrs...)
+		c.Next()
+	}
+}
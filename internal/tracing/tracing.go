@@ -0,0 +1,73 @@
+// Package tracing holds the OpenTelemetry helpers for what sdk.StartSpan
+// doesn't cover: span kinds, span links, and propagating trace context over
+// transports other than HTTP.
+//
+// The TraceKit SDK registers itself as the global tracer provider and
+// propagator, so spans started here are exported alongside the SDK's own.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Tracekit-Dev/test-app"
+
+// Tracer returns the app's tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// propagators are the formats SetupPropagation can install, by their
+// OTEL_PROPAGATORS names
+var propagators = map[string]propagation.TextMapPropagator{
+	"tracecontext": propagation.TraceContext{},
+	"baggage":      propagation.Baggage{},
+	"b3":           b3.New(),
+	"b3multi":      b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)),
+	"jaeger":       jaeger.Jaeger{},
+}
+
+// Propagator returns the propagator with the given name
+func Propagator(name string) (propagation.TextMapPropagator, bool) {
+	p, ok := propagators[name]
+	return p, ok
+}
+
+// SetupPropagation installs the named propagators as the global propagator,
+// W3C Trace Context and W3C Baggage when names is empty. Every format is
+// extracted from incoming requests and injected into outgoing ones, so
+// adding b3 or jaeger lets traces cross services that only speak Zipkin
+// headers or Jaeger's uber-trace-id.
+// Call it after the SDK is initialized.
+func SetupPropagation(names ...string) error {
+	if len(names) == 0 {
+		names = []string{"tracecontext", "baggage"}
+	}
+	composite := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		p, ok := propagators[name]
+		if !ok {
+			return fmt.Errorf("unknown propagator %q", name)
+		}
+		composite = append(composite, p)
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(composite...))
+	return nil
+}
+
+// Inject writes the trace context of ctx into carrier
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+}
+
+// Extract returns ctx with the trace context read from carrier
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
@@ -0,0 +1,150 @@
+// Package userstore keeps the demo users in memory behind /api/users and
+// /api/users/:id. IDs arrive in several spellings ("7", "007", "usr_7") and
+// are normalized before lookup, so one user is always recorded on spans
+// under the same user.id.
+package userstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+)
+
+var (
+	// ErrInvalidID is returned for IDs that don't normalize to a positive integer
+	ErrInvalidID = errors.New("user id must be a positive integer, optionally prefixed with usr_")
+
+	// ErrNotFound is returned for well-formed IDs without a user
+	ErrNotFound = errors.New("user not found")
+)
+
+// idPrefix is the prefix of the external form of a user ID
+const idPrefix = "usr_"
+
+// User is one stored user
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Update changes the fields that are set
+type Update struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+// NormalizeID parses the accepted spellings of a user ID: surrounding space,
+// a usr_ prefix in any case, and leading zeros are dropped
+func NormalizeID(raw string) (int, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	s = strings.TrimPrefix(s, idPrefix)
+	id, err := strconv.Atoi(s)
+	if err != nil || id < 1 || strings.HasPrefix(s, "+") {
+		return 0, ErrInvalidID
+	}
+	return id, nil
+}
+
+// Store holds users by ID
+type Store struct {
+	sdk *tracekit.SDK
+
+	mu    sync.RWMutex
+	users map[int]User
+}
+
+// NewStore creates a store seeded with the demo users followed by generated
+// more, so listings have enough rows to page through
+func NewStore(sdk *tracekit.SDK, generated int) *Store {
+	s := &Store{sdk: sdk, users: make(map[int]User)}
+	for _, u := range []User{
+		{1, "Alice", "alice@example.com"},
+		{2, "Bob", "bob@example.com"},
+		{3, "Charlie", "charlie@example.com"},
+	} {
+		s.users[u.ID] = u
+	}
+	for id := 4; id < 4+generated; id++ {
+		s.users[id] = User{id, fmt.Sprintf("User %d", id), fmt.Sprintf("user%d@example.com", id)}
+	}
+	return s
+}
+
+// List returns every user ordered by ID
+func (s *Store) List(ctx context.Context) []User {
+	_, span := s.sdk.StartSpan(ctx, "userStore.list")
+	defer span.End()
+
+	s.mu.RLock()
+	list := make([]User, 0, len(s.users))
+	for _, u := range s.users {
+		list = append(list, u)
+	}
+	s.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	s.sdk.AddAttribute(span, "user.store", "memory")
+	s.sdk.AddIntAttribute(span, "user_count", int64(len(list)))
+	s.sdk.SetSuccess(span)
+	return list
+}
+
+// Get returns the user with the given ID
+func (s *Store) Get(ctx context.Context, id int) (User, error) {
+	_, span := s.sdk.StartSpan(ctx, "userStore.get")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "user.store", "memory")
+	s.sdk.AddIntAttribute(span, "user.id", int64(id))
+
+	s.mu.RLock()
+	u, ok := s.users[id]
+	s.mu.RUnlock()
+	if !ok {
+		s.sdk.AddEvent(span, "user.not_found")
+		return User{}, ErrNotFound
+	}
+
+	s.sdk.SetSuccess(span)
+	return u, nil
+}
+
+// Update applies upd to the user with the given ID and returns the result
+func (s *Store) Update(ctx context.Context, id int, upd Update) (User, error) {
+	_, span := s.sdk.StartSpan(ctx, "userStore.update")
+	defer span.End()
+
+	s.sdk.AddAttribute(span, "user.store", "memory")
+	s.sdk.AddIntAttribute(span, "user.id", int64(id))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		s.sdk.AddEvent(span, "user.not_found")
+		return User{}, ErrNotFound
+	}
+
+	var changed []string
+	if upd.Name != nil {
+		u.Name = *upd.Name
+		changed = append(changed, "name")
+	}
+	if upd.Email != nil {
+		u.Email = *upd.Email
+		changed = append(changed, "email")
+	}
+	s.users[id] = u
+
+	s.sdk.AddAttribute(span, "user.changed_fields", strings.Join(changed, ","))
+	s.sdk.SetSuccess(span)
+	return u, nil
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/retry"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// ErrQueueFull is returned by Emit when the delivery queue is full
+var ErrQueueFull = errors.New("webhook queue is full")
+
+// Dispositions recorded as webhook.disposition on each attempt
+const (
+	DispositionDelivered    = "delivered"
+	DispositionRetry        = "retry_scheduled"
+	DispositionDeadLettered = "dead_lettered"
+)
+
+// maxDeadLetters bounds how many dead-lettered events are kept
+const maxDeadLetters = 100
+
+// Event is an outbound webhook
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+
+	attempt int
+
+	// origin is the span that emitted the event
+	origin trace.SpanContext
+}
+
+// DeadLetter is an event that could not be delivered
+type DeadLetter struct {
+	Event    Event     `json:"event"`
+	Attempts int       `json:"attempts"`
+	Reason   string    `json:"reason"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// Dispatcher delivers events to a single URL. Failed deliveries go back on
+// the queue after a backoff delay until the attempts run out, then to the
+// dead-letter list.
+type Dispatcher struct {
+	sdk    *tracekit.SDK
+	http   *http.Client
+	url    string
+	secret []byte
+	policy retry.Policy
+	queue  chan Event
+
+	mu   sync.Mutex
+	dead []DeadLetter
+}
+
+// NewDispatcher creates a dispatcher posting to url, signing payloads with
+// secret when it is set, and trying each event up to maxAttempts times
+func NewDispatcher(sdk *tracekit.SDK, url string, secret []byte, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		sdk:    sdk,
+		http:   sdk.HTTPClient(&http.Client{Timeout: 5 * time.Second}),
+		url:    url,
+		secret: secret,
+		policy: retry.Policy{MaxAttempts: maxAttempts, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second},
+		queue:  make(chan Event, 100),
+	}
+}
+
+// Emit queues an event of eventType, remembering the span in ctx so every
+// delivery attempt can link back to it
+func (d *Dispatcher) Emit(ctx context.Context, eventType string, data interface{}) (Event, error) {
+	ev := Event{
+		ID:        fmt.Sprintf("evt_%d", time.Now().UnixNano()),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+		origin:    trace.SpanContextFromContext(ctx),
+	}
+	select {
+	case d.queue <- ev:
+		return ev, nil
+	default:
+		return Event{}, ErrQueueFull
+	}
+}
+
+// DeadLetters returns the dead-lettered events, newest first
+func (d *Dispatcher) DeadLetters() []DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]DeadLetter, len(d.dead))
+	for i, dl := range d.dead {
+		out[len(d.dead)-1-i] = dl
+	}
+	return out
+}
+
+// Run delivers queued events until ctx is canceled
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-d.queue:
+			d.deliver(ctx, ev)
+		}
+	}
+}
+
+// deliver makes one attempt in a new root span linked to the emitting
+// request, then schedules a retry or dead-letters the event if it failed
+func (d *Dispatcher) deliver(ctx context.Context, ev Event) {
+	ev.attempt++
+
+	opts := []trace.SpanStartOption{trace.WithNewRoot()}
+	if ev.origin.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{
+			SpanContext: ev.origin,
+			Attributes:  []attribute.KeyValue{attribute.String("link.type", "emitted_by")},
+		}))
+	}
+
+	attemptCtx, span := tracing.Tracer().Start(context.Background(), "webhook.deliver", opts...)
+	defer span.End()
+
+	d.sdk.AddAttribute(span, "webhook.event_id", ev.ID)
+	d.sdk.AddAttribute(span, "webhook.event_type", ev.Type)
+	d.sdk.AddAttribute(span, "webhook.url", d.url)
+	d.sdk.AddIntAttribute(span, "webhook.attempt", int64(ev.attempt))
+	d.sdk.AddIntAttribute(span, "webhook.max_attempts", int64(d.policy.MaxAttempts))
+
+	status, err := d.post(attemptCtx, ev)
+	if status != 0 {
+		d.sdk.AddIntAttribute(span, "http.response.status_code", int64(status))
+	}
+	if err == nil {
+		d.sdk.AddAttribute(span, "webhook.disposition", DispositionDelivered)
+		d.sdk.SetSuccess(span)
+		return
+	}
+	d.sdk.RecordError(span, err)
+
+	// Client errors won't succeed on retry, except rate limiting
+	retryable := status == 0 || status >= 500 || status == http.StatusTooManyRequests
+	if !retryable || ev.attempt >= d.policy.MaxAttempts {
+		d.sdk.AddAttribute(span, "webhook.disposition", DispositionDeadLettered)
+		d.deadLetter(ev, err)
+		logging.FromContext(attemptCtx).Warn("🪝 Webhook dead-lettered",
+			zap.String("webhook.event_id", ev.ID),
+			zap.Int("webhook.attempts", ev.attempt),
+			zap.Error(err),
+		)
+		return
+	}
+
+	delay := d.policy.Backoff(ev.attempt)
+	d.sdk.AddAttribute(span, "webhook.disposition", DispositionRetry)
+	d.sdk.AddIntAttribute(span, "webhook.next_retry_ms", delay.Milliseconds())
+
+	time.AfterFunc(delay, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case d.queue <- ev:
+		default:
+			d.deadLetter(ev, ErrQueueFull)
+		}
+	})
+}
+
+// post sends ev and returns the response status, zero if there was none
+func (d *Dispatcher) post(ctx context.Context, ev Event) (int, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", d.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(d.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(d.secret, body))
+	}
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) deadLetter(ev Event, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.dead = append(d.dead, DeadLetter{Event: ev, Attempts: ev.attempt, Reason: err.Error(), FailedAt: time.Now()})
+	if len(d.dead) > maxDeadLetters {
+		d.dead = d.dead[len(d.dead)-maxDeadLetters:]
+	}
+}
@@ -0,0 +1,63 @@
+// Package webhook signs and verifies webhook payloads with HMAC-SHA256.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// SignatureHeader carries the payload signature as "sha256=<hex>"
+const SignatureHeader = "X-Webhook-Signature"
+
+// Verification failures, recorded as webhook.verification_failure
+var (
+	ErrMissingSignature   = errors.New("missing signature")
+	ErrMalformedSignature = errors.New("malformed signature")
+	ErrSignatureMismatch  = errors.New("signature mismatch")
+)
+
+// Sign returns the SignatureHeader value for body
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature, a SignatureHeader value, against body in
+// constant time
+func Verify(secret, body []byte, signature string) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	hexSum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrMalformedSignature
+	}
+	got, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return ErrMalformedSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// FailureReason names err for span attributes: "missing", "malformed", or
+// "mismatch"
+func FailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrMissingSignature):
+		return "missing"
+	case errors.Is(err, ErrMalformedSignature):
+		return "malformed"
+	default:
+		return "mismatch"
+	}
+}
@@ -0,0 +1,129 @@
+// Package worker runs jobs that outlive the HTTP request that created them.
+// Each job gets its own root span linked back to the request span, so the
+// request trace stays short while the job remains discoverable from it.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Tracekit-Dev/go-sdk/tracekit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+)
+
+// ErrQueueFull is returned by Submit when the pool can't accept more jobs
+var ErrQueueFull = errors.New("worker queue is full")
+
+// jobDurations is how long each simulated job type takes
+var jobDurations = map[string]time.Duration{
+	"report":    300 * time.Millisecond,
+	"export":    500 * time.Millisecond,
+	"thumbnail": 80 * time.Millisecond,
+}
+
+// Job is a unit of work handed off by a handler
+type Job struct {
+	ID       string
+	Type     string
+	Enqueued time.Time
+
+	// origin is the span that submitted the job
+	origin trace.SpanContext
+}
+
+// Pool is a fixed-size pool of workers fed through a channel
+type Pool struct {
+	sdk  *tracekit.SDK
+	jobs chan Job
+	size int
+}
+
+// NewPool creates a pool of size workers with room for queueSize pending jobs
+func NewPool(sdk *tracekit.SDK, size, queueSize int) *Pool {
+	return &Pool{
+		sdk:  sdk,
+		jobs: make(chan Job, queueSize),
+		size: size,
+	}
+}
+
+// Submit queues a job of jobType, remembering the span in ctx so the job's
+// span can link back to it. It never blocks the request.
+func (p *Pool) Submit(ctx context.Context, jobType string) (Job, error) {
+	if _, ok := jobDurations[jobType]; !ok {
+		return Job{}, fmt.Errorf("unknown job type %q", jobType)
+	}
+
+	job := Job{
+		ID:       fmt.Sprintf("job-%d", time.Now().UnixNano()),
+		Type:     jobType,
+		Enqueued: time.Now(),
+		origin:   trace.SpanContextFromContext(ctx),
+	}
+
+	select {
+	case p.jobs <- job:
+		return job, nil
+	default:
+		return Job{}, ErrQueueFull
+	}
+}
+
+// Run starts the workers and blocks until ctx is canceled and every worker
+// has finished its current job
+func (p *Pool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.size; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-p.jobs:
+					p.process(id, job)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// process runs job in a new root span linked to the submitting request
+func (p *Pool) process(workerID int, job Job) {
+	opts := []trace.SpanStartOption{trace.WithNewRoot()}
+	if job.origin.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{
+			SpanContext: job.origin,
+			Attributes:  []attribute.KeyValue{attribute.String("link.type", "enqueued_by")},
+		}))
+	}
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "job."+job.Type, opts...)
+	defer span.End()
+
+	p.sdk.AddAttribute(span, "job.id", job.ID)
+	p.sdk.AddAttribute(span, "job.type", job.Type)
+	p.sdk.AddIntAttribute(span, "worker.id", int64(workerID))
+	p.sdk.AddIntAttribute(span, "job.queue_wait_ms", time.Since(job.Enqueued).Milliseconds())
+
+	p.sdk.AddEvent(span, "job.started")
+	time.Sleep(jobDurations[job.Type])
+	p.sdk.AddEvent(span, "job.completed")
+
+	p.sdk.SetSuccess(span)
+	logging.FromContext(ctx).Info("⚙️  Job finished",
+		zap.Int("worker.id", workerID),
+		zap.String("job.id", job.ID),
+		zap.String("job.type", job.Type),
+	)
+}
@@ -2,589 +2,905 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
+	"crypto/tls"
+	_ "embed"
+	"errors"
+	"flag"
 	"log"
-	"math/rand"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/Tracekit-Dev/go-sdk/tracekit"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/Tracekit-Dev/test-app/internal/admin"
+	"github.com/Tracekit-Dev/test-app/internal/batch"
+	"github.com/Tracekit-Dev/test-app/internal/cache"
+	"github.com/Tracekit-Dev/test-app/internal/capture"
+	"github.com/Tracekit-Dev/test-app/internal/cart"
+	"github.com/Tracekit-Dev/test-app/internal/certs"
+	"github.com/Tracekit-Dev/test-app/internal/chaos"
+	"github.com/Tracekit-Dev/test-app/internal/clients"
+	"github.com/Tracekit-Dev/test-app/internal/compress"
+	"github.com/Tracekit-Dev/test-app/internal/config"
+	"github.com/Tracekit-Dev/test-app/internal/database"
+	"github.com/Tracekit-Dev/test-app/internal/deadline"
+	"github.com/Tracekit-Dev/test-app/internal/export"
+	"github.com/Tracekit-Dev/test-app/internal/flags"
+	"github.com/Tracekit-Dev/test-app/internal/gql"
+	"github.com/Tracekit-Dev/test-app/internal/handlers"
+	"github.com/Tracekit-Dev/test-app/internal/health"
+	"github.com/Tracekit-Dev/test-app/internal/inventory"
+	"github.com/Tracekit-Dev/test-app/internal/logging"
+	"github.com/Tracekit-Dev/test-app/internal/messaging"
+	"github.com/Tracekit-Dev/test-app/internal/mocks"
+	"github.com/Tracekit-Dev/test-app/internal/mongodb"
+	"github.com/Tracekit-Dev/test-app/internal/notify"
+	"github.com/Tracekit-Dev/test-app/internal/ordersvc"
+	"github.com/Tracekit-Dev/test-app/internal/orm"
+	"github.com/Tracekit-Dev/test-app/internal/payments"
+	"github.com/Tracekit-Dev/test-app/internal/poll"
+	"github.com/Tracekit-Dev/test-app/internal/prom"
+	"github.com/Tracekit-Dev/test-app/internal/ratelimit"
+	"github.com/Tracekit-Dev/test-app/internal/recent"
+	"github.com/Tracekit-Dev/test-app/internal/reload"
+	"github.com/Tracekit-Dev/test-app/internal/requestid"
+	"github.com/Tracekit-Dev/test-app/internal/resolver"
+	"github.com/Tracekit-Dev/test-app/internal/respcache"
+	"github.com/Tracekit-Dev/test-app/internal/runtimestats"
+	"github.com/Tracekit-Dev/test-app/internal/sampling"
+	"github.com/Tracekit-Dev/test-app/internal/scheduler"
+	"github.com/Tracekit-Dev/test-app/internal/soap"
+	"github.com/Tracekit-Dev/test-app/internal/static"
+	"github.com/Tracekit-Dev/test-app/internal/tracing"
+	"github.com/Tracekit-Dev/test-app/internal/userstore"
+	"github.com/Tracekit-Dev/test-app/internal/webhook"
+	"github.com/Tracekit-Dev/test-app/internal/worker"
 )
 
-var sdk *tracekit.SDK
-var httpClient *http.Client
+// defaultTopology describes the downstream services unless SERVICES_FILE
+// points elsewhere
+//
+//go:embed services.yaml
+var defaultTopology []byte
 
-// Metrics
-var (
-	requestCounter       tracekit.Counter
-	activeRequestsGauge  tracekit.Gauge
-	requestDurationHisto tracekit.Histogram
-	orderCounter         tracekit.Counter
-	orderAmountHisto     tracekit.Histogram
-)
+// defaultFlags defines the feature flags unless FEATURE_FLAGS_FILE points
+// elsewhere
+//
+//go:embed flags.yaml
+var defaultFlags []byte
 
-// Service URLs for cross-service communication
-const (
-	nodeServiceURL    = "http://localhost:8084"
-	pythonServiceURL  = "http://localhost:5001"
-	laravelServiceURL = "http://localhost:8083"
-	phpServiceURL     = "http://localhost:8086"
-)
+// shutdownTimeout bounds both request draining and the final span flush
+const shutdownTimeout = 10 * time.Second
 
 func main() {
-	// Load environment variables from .env file
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
-	}
+	os.Exit(run())
+}
 
-	// Get configuration from environment variables with defaults
-	apiKey := getEnv("TRACEKIT_API_KEY", "")
-	if apiKey == "" {
-		log.Fatal("TRACEKIT_API_KEY environment variable is required. Copy .env.example to .env and add your API key.")
+// run loads the configuration, serves the app, and blocks until shutdown.
+// It returns the process exit code so deferred cleanups run before os.Exit.
+func run() int {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
 	}
-
-	serviceName := getEnv("SERVICE_NAME", "go-test-app")
-	environment := getEnv("ENVIRONMENT", "development")
-	endpoint := getEnv("TRACEKIT_ENDPOINT", "localhost:8081")
-	useSSL := getEnv("TRACEKIT_USE_SSL", "false") == "true"
-
-	var err error
-	// Initialize TraceKit SDK with environment configuration
-	sdk, err = tracekit.NewSDK(&tracekit.Config{
-		APIKey:               apiKey,
-		ServiceName:          serviceName,
-		Environment:          environment,
-		Endpoint:             endpoint,
-		UseSSL:               useSSL,
-		EnableCodeMonitoring: true,
-		// Map localhost URLs to actual service names for service graph
-		// This helps TraceKit understand cross-service dependencies
-		ServiceNameMappings: map[string]string{
-			"localhost:8084": "node-test-app",
-			"localhost:5001": "python-test-app",
-			"localhost:8083": "laravel-test-app",
-			"localhost:8086": "php-test-app",
-		},
-	})
 	if err != nil {
-		log.Fatal("Failed to initialize SDK:", err)
+		log.Fatal(err)
 	}
 
-	defer sdk.Shutdown(context.Background())
-
-	// Create instrumented HTTP client for outgoing calls
-	httpClient = sdk.HTTPClient(nil)
+	// Structured logs carry trace_id/span_id so they join up with traces
+	logger, err := logging.Setup(cfg.LogLevel, cfg.Environment)
+	if err != nil {
+		log.Fatal("Invalid LOG_LEVEL: ", err)
+	}
+	defer logger.Sync()
 
-	// Initialize metrics
-	requestCounter = sdk.Counter("http.requests.total", map[string]string{"service": "go-test-app"})
-	activeRequestsGauge = sdk.Gauge("http.requests.active", nil)
-	requestDurationHisto = sdk.Histogram("http.request.duration", map[string]string{"unit": "ms"})
-	orderCounter = sdk.Counter("orders.total", nil)
-	orderAmountHisto = sdk.Histogram("order.amount", map[string]string{"currency": "usd"})
+	a := newApp(cfg, logger)
+	defer a.close()
 
-	log.Println("✅ TraceKit SDK initialized successfully!")
-	log.Println("📊 Metrics initialized!")
+	logger.Info("🚀 Go Test App starting",
+		zap.String("url", "http://localhost:"+cfg.Port),
+		zap.String("status_page", "http://localhost:"+cfg.Port+"/"),
+		zap.Int("routes", len(a.router.Routes())),
+	)
 
-	// Setup Gin with tracing
-	r := gin.Default()
-	r.Use(sdk.GinMiddleware())
+	// Stop on Ctrl+C or SIGTERM (docker stop, Kubernetes pod termination)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Simple hello endpoint
-	r.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "Hello from Go Test App! 👋",
-			"service": "go-test-app",
-		})
-	})
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: a.router,
+	}
+	if cfg.H2C {
+		// HTTP/2 with prior knowledge, e.g. curl --http2-prior-knowledge or
+		// SELF_PROTOCOL=h2c, next to HTTP/1.1
+		srv.Protocols = new(http.Protocols)
+		srv.Protocols.SetHTTP1(true)
+		srv.Protocols.SetUnencryptedHTTP2(true)
+	}
 
-	// Endpoint with custom span and metrics
-	r.GET("/api/users", func(c *gin.Context) {
-		start := time.Now()
-		activeRequestsGauge.Inc()
-		defer func() {
-			activeRequestsGauge.Dec()
-			duration := float64(time.Since(start).Milliseconds())
-			requestDurationHisto.Record(duration)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.serverErr <- err
+		}
+	}()
+
+	// HTTPS serves the same router next to plain HTTP, which the self-calls
+	// and health probes keep using. Rotated certificates are picked up by
+	// checking the files in the background.
+	servers := []*http.Server{srv}
+	if cfg.TLSCertFile != "" {
+		certReloader, err := certs.New(a.sdk, cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			logger.Fatal("Invalid TLS certificate", zap.Error(err))
+		}
+		tlsSrv := &http.Server{
+			Addr:      ":" + cfg.TLSPort,
+			Handler:   a.router,
+			TLSConfig: certReloader.TLSConfig(),
+		}
+		servers = append(servers, tlsSrv)
+		go func() {
+			if err := tlsSrv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.serverErr <- err
+			}
 		}()
+		if cfg.TLSReloadInterval > 0 {
+			a.spawn(func(ctx context.Context) { certReloader.Watch(ctx, cfg.TLSReloadInterval) })
+		}
+		logger.Info("🔒 HTTPS listening",
+			zap.String("url", "https://localhost:"+cfg.TLSPort),
+			zap.String("subject", certReloader.Leaf().Subject.String()),
+			zap.Time("not_after", certReloader.Leaf().NotAfter),
+		)
+	}
 
-		requestCounter.Inc()
+	a.serveGRPC()
 
-		ctx := c.Request.Context()
+	// Services started alongside this one, e.g. by docker-compose, may still
+	// be booting; probe them with backoff so the first requests don't fail
+	if cfg.StartupWait > 0 {
+		probes := []health.Probe{a.exporterProbe}
+		for _, svc := range a.client.Services().All() {
+			probes = append(probes, health.HTTPProbe(svc.Name, svc.URL+"/api/data", a.client.HTTP(svc)))
+		}
+		a.spawn(func(ctx context.Context) {
+			logger.Info("⏳ Waiting for dependencies", zap.Duration("timeout", cfg.StartupWait))
+			if ok, results := health.Wait(ctx, a.sdk, probes, cfg.StartupWait); ok {
+				logger.Info("✅ Dependencies ready", zap.Any("dependencies", results))
+			} else {
+				logger.Warn("⚠️  Dependencies still down after STARTUP_WAIT, marking ready anyway", zap.Any("dependencies", results))
+			}
+			a.readiness.MarkReady("dependencies")
+		})
+	}
 
-		ctx, span := sdk.StartSpan(ctx, "fetchUsers")
-		defer span.End()
+	// SIGHUP re-reads .env, the flags, and the service topology
+	reloader := reload.New(a.sdk, cfg, os.Args[1:], reload.Targets{
+		Sampler:          a.sampler,
+		Client:           a.client,
+		SamplingSettings: samplingSettings,
+		LoadServices:     loadServices,
+	})
+	a.spawn(reloader.Watch)
+
+	exitCode := 0
+	select {
+	case err := <-a.serverErr:
+		logger.Error("Failed to start server", zap.Error(err))
+		exitCode = 1
+	case <-ctx.Done():
+		logger.Info("🛑 Shutdown signal received, draining in-flight requests...")
+
+		// Fail /readyz first and keep serving while load balancers notice
+		a.readiness.Drain()
+		if cfg.ShutdownDelay > 0 {
+			logger.Info("⏳ Waiting for load balancers to stop sending traffic", zap.Duration("delay", cfg.ShutdownDelay))
+			time.Sleep(cfg.ShutdownDelay)
+		}
+	}
+	stop()
+	// Answer waiting long polls now instead of at their timeouts
+	a.polls.Close()
 
-		c.Request = c.Request.WithContext(ctx)
+	shutdown(logger, a.sdk, servers, a.admin.Server, a.grpcServer, a.stopBackground)
+	return exitCode
+}
 
-		sdk.AddAttribute(span, "endpoint", "/api/users")
-		sdk.AddIntAttribute(span, "user_count", 5)
+// app is the test app wired together: the router serving the API and the
+// components behind it. run serves it on real listeners; the end-to-end
+// tests serve the same router through httptest.
+type app struct {
+	cfg    *config.Config
+	logger *zap.Logger
+	sdk    *tracekit.SDK
+	router *gin.Engine
+
+	admin         *admin.Server
+	grpcServer    *grpc.Server
+	client        *clients.Client
+	readiness     *health.Readiness
+	exporterProbe health.Probe
+	sampler       *sampling.Sampler
+	polls         *poll.Hub
+
+	// serverErr reports listeners that fail to serve
+	serverErr chan error
+
+	// Consumers and workers run until stopBackground cancels bgCtx
+	bgCtx            context.Context
+	cancelBackground context.CancelFunc
+	background       sync.WaitGroup
+
+	// closers release connections once the app has shut down
+	closers []func() error
+}
 
-		time.Sleep(50 * time.Millisecond)
+// newApp creates the components cfg asks for and mounts every route. The
+// admin listener starts right away so startup can be profiled. Like run, it
+// exits through logger.Fatal when something can't be set up.
+func newApp(cfg *config.Config, logger *zap.Logger) *app {
+	a := &app{cfg: cfg, logger: logger, serverErr: make(chan error, 4)}
+	a.bgCtx, a.cancelBackground = context.WithCancel(context.Background())
 
-		sdk.AddEvent(span, "users.fetched")
+	services, err := loadServices(cfg)
+	if err != nil {
+		logger.Fatal("Invalid service topology", zap.String("file", cfg.ServicesFile), zap.Error(err))
+	}
 
-		users := []map[string]interface{}{
-			{"id": 1, "name": "Alice", "email": "alice@example.com"},
-			{"id": 2, "name": "Bob", "email": "bob@example.com"},
-			{"id": 3, "name": "Charlie", "email": "charlie@example.com"},
+	// Standalone mode swaps the polyglot services for in-process mocks
+	var mockServers *mocks.Servers
+	if cfg.Standalone {
+		mockServers = mocks.Listen(services)
+		a.closers = append(a.closers, func() error { mockServers.Close(); return nil })
+		services = mockServers.Services()
+	}
+	services.Self = clients.Service{Name: cfg.ServiceName, URL: "http://localhost:" + cfg.Port, Protocol: cfg.SelfProtocol}
+
+	// /api/call-external calls a third-party API that reports no spans of
+	// its own. Its host is mapped to a peer name so the CLIENT span still
+	// shows up as a node in the service graph.
+	external := clients.Service{Name: "external-api", URL: cfg.ExternalAPIURL, Span: "callExternalAPI", Timeout: 5 * time.Second}
+	nameMappings := services.NameMappings()
+	nameMappings[external.Host()] = external.Name
+
+	// /proxy/*path forwards to this app unless PROXY_TARGET_URL names
+	// another upstream
+	proxyTarget := services.Self
+	if cfg.ProxyTargetURL != "" {
+		proxyTarget = clients.Service{Name: "proxy-upstream", URL: cfg.ProxyTargetURL}
+		if _, ok := nameMappings[proxyTarget.Host()]; !ok {
+			nameMappings[proxyTarget.Host()] = proxyTarget.Name
 		}
+	}
 
-		sdk.SetSuccess(span)
-		c.JSON(200, gin.H{"users": users})
-	})
-
-	// Endpoint that calls Node.js service - tests CLIENT spans
-	r.GET("/api/call-node", func(c *gin.Context) {
-		ctx := c.Request.Context()
+	// Spans go to TraceKit, unless an OTLP fallback is configured and
+	// TraceKit can't be reached, e.g. in an airgapped demo
+	collectorAddr := health.EndpointAddr(cfg.Endpoint, cfg.UseSSL)
+	exporterProbe := health.TCPProbe("tracekit", collectorAddr)
+	useFallback := false
+	if cfg.OTLPFallbackEndpoint != "" {
+		probeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		useFallback = exporterProbe.Check(probeCtx) != nil
+		cancel()
+	}
+	if useFallback {
+		collectorAddr = cfg.OTLPFallbackEndpoint
+		exporterProbe = health.TCPProbe("otlp-collector", collectorAddr)
+	}
 
-		ctx, span := sdk.StartSpan(ctx, "callNodeService")
-		defer span.End()
+	// /readyz stays 503 until the config and SDK are in place, and with
+	// STARTUP_WAIT until the dependencies are up or the wait runs out
+	conditions := []string{"config", "sdk"}
+	if cfg.StartupWait > 0 {
+		conditions = append(conditions, "dependencies")
+	}
+	readiness := health.NewReadiness(exporterProbe, conditions...)
+	readiness.MarkReady("config")
 
-		c.Request = c.Request.WithContext(ctx)
+	// The sampler makes head sampling decisions per route in front of the
+	// SDK, which is configured with the highest ratio of any route
+	sampler := sampling.New(samplingSettings(cfg))
 
-		sdk.AddAttribute(span, "target.service", "node-test-app")
-		sdk.AddEvent(span, "calling.node.service")
+	// Initialize TraceKit SDK with environment configuration
+	sdk, err := tracekit.NewSDK(&tracekit.Config{
+		APIKey:               cfg.APIKey,
+		ServiceName:          cfg.ServiceName,
+		Environment:          cfg.Environment,
+		Endpoint:             cfg.Endpoint,
+		UseSSL:               cfg.UseSSL,
+		EnableCodeMonitoring: true,
+		SamplingRate:         sampler.SDKRatio(),
+		// Map downstream host:port pairs to service names for the service graph
+		// This helps TraceKit understand cross-service dependencies
+		ServiceNameMappings: nameMappings,
+	})
+	if err != nil {
+		logger.Fatal("Failed to initialize SDK", zap.Error(err))
+	}
+	readiness.MarkReady("sdk")
+	a.sdk, a.readiness, a.exporterProbe, a.sampler = sdk, readiness, exporterProbe, sampler
 
-		// Make HTTP call to Node.js service with context propagation
-		req, err := http.NewRequestWithContext(ctx, "GET", nodeServiceURL+"/api/data", nil)
+	if useFallback {
+		processor, err := export.OTLP(context.Background(), cfg.OTLPFallbackEndpoint)
+		if err == nil {
+			err = export.Register(processor)
+		}
 		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"error": "Failed to create request"})
-			return
+			logger.Fatal("Failed to start the OTLP fallback exporter", zap.Error(err))
 		}
+		logger.Warn("📡 TraceKit unreachable, exporting spans over OTLP/gRPC instead",
+			zap.String("tracekit", cfg.Endpoint),
+			zap.String("collector", cfg.OTLPFallbackEndpoint),
+		)
+	}
 
-		resp, err := httpClient.Do(req)
+	// Tee spans locally to inspect exactly what the app emits
+	if cfg.DebugExport != "" {
+		processor, err := export.Debug(cfg.DebugExport)
+		if err == nil {
+			err = export.Register(processor)
+		}
 		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"error": fmt.Sprintf("Failed to call Node service: %v", err)})
-			return
+			logger.Fatal("Failed to start the debug span exporter", zap.Error(err))
 		}
-		defer resp.Body.Close()
+		logger.Info("🔍 Writing every span as JSON lines", zap.String("target", cfg.DebugExport))
+	}
 
-		body, _ := io.ReadAll(resp.Body)
-		var nodeResponse map[string]interface{}
-		json.Unmarshal(body, &nodeResponse)
+	// The last finished spans, served at /debug/traces on the admin port
+	var spanRing *export.Ring
+	if cfg.DebugTraceBuffer > 0 {
+		spanRing = export.NewRing(cfg.DebugTraceBuffer)
+		if err := export.Register(spanRing); err != nil {
+			logger.Fatal("Failed to start the span ring buffer", zap.Error(err))
+		}
+	}
 
-		sdk.AddEvent(span, "node.service.responded")
-		sdk.AddIntAttribute(span, "response.status", int64(resp.StatusCode))
-		sdk.SetSuccess(span)
+	// Logs every finished span while turned on through /admin/config
+	spanLog := export.NewSpanLog(logger)
+	if err := export.Register(spanLog); err != nil {
+		logger.Fatal("Failed to start the span log", zap.Error(err))
+	}
 
-		c.JSON(200, gin.H{
-			"message":       "Successfully called Node.js service",
-			"node_response": nodeResponse,
-			"status":        resp.StatusCode,
-		})
+	// Operational endpoints get their own untraced listener with its own
+	// lifecycle: it starts before the API so startup can be profiled, and
+	// stops after it so metrics and profiles cover the drain. The other
+	// endpoints are mounted as their components are created.
+	adminSrv := admin.New(":"+cfg.AdminPort, admin.SDKInfo{
+		ServiceName:    cfg.ServiceName,
+		Environment:    cfg.Environment,
+		Endpoint:       cfg.Endpoint,
+		UseSSL:         cfg.UseSSL,
+		CodeMonitoring: true,
+		SampleRate:     cfg.SampleRate,
+		ParentBased:    cfg.SampleParentBased,
+		APIKeySet:      cfg.APIKey != "",
 	})
+	if spanRing != nil {
+		adminSrv.Handle("/debug/traces", spanRing)
+	}
+	adminSrv.Start(a.serverErr)
+	a.admin = adminSrv
+	logger.Info("🔧 Admin endpoints (metrics, pprof, runtime, SDK info, recent spans, transports, live config)", zap.String("url", "http://localhost:"+cfg.AdminPort+"/"))
+
+	// Propagate baggage alongside the trace context, plus any legacy formats
+	// the downstream services need
+	if err := tracing.SetupPropagation(cfg.Propagators...); err != nil {
+		logger.Fatal("Invalid OTEL_PROPAGATORS", zap.Error(err))
+	}
 
-	// Endpoint that calls Node.js and Node calls back - tests circular calls
-	r.GET("/api/chain", func(c *gin.Context) {
-		ctx := c.Request.Context()
+	if mockServers != nil {
+		mockServers.Start(sdk, services.Self)
+		logger.Info("🧪 Standalone mode: serving mock downstream services", zap.Any("services", services.NameMappings()))
+	}
 
-		ctx, span := sdk.StartSpan(ctx, "chainCall")
-		defer span.End()
+	// Create instrumented HTTP client for outgoing calls, presenting a client
+	// certificate to services that require mTLS
+	clientTLS, clientCert, err := downstreamTLS(sdk, cfg)
+	if err != nil {
+		logger.Fatal("Invalid downstream TLS configuration", zap.Error(err))
+	}
+	clientOpts := clients.Options{
+		TLS: clientTLS,
+		Transport: clients.Transport{
+			MaxIdleConns:        cfg.HTTPClientMaxIdleConns,
+			MaxIdleConnsPerHost: cfg.HTTPClientMaxIdleConnsPerHost,
+			IdleConnTimeout:     cfg.HTTPClientIdleConnTimeout,
+			DialTimeout:         cfg.HTTPClientDialTimeout,
+			TLSHandshakeTimeout: cfg.HTTPClientTLSHandshakeTimeout,
+		},
+	}
+	if cfg.DNSResolver {
+		clientOpts.Resolver = resolver.New(sdk, cfg.DNSServer, cfg.DNSCacheTTL)
+	}
+	client := clients.New(sdk, services, clientOpts)
+	a.client = client
+	adminSrv.Handle("/debug/transport", admin.TransportHandler(client))
 
-		c.Request = c.Request.WithContext(ctx)
+	// gRPC OrderService and a client that calls it through the network
+	a.grpcServer = ordersvc.NewServer(sdk)
+	orders, err := ordersvc.NewClient("localhost:" + cfg.GRPCPort)
+	if err != nil {
+		logger.Fatal("Failed to create gRPC client", zap.Error(err))
+	}
+	a.closers = append(a.closers, orders.Close)
 
-		sdk.AddAttribute(span, "chain.initiator", "go-test-app")
-		sdk.AddEvent(span, "chain.started")
+	// grpc-gateway serves the same OrderService as JSON over HTTP
+	orderGateway, err := ordersvc.NewGateway(sdk, orders)
+	if err != nil {
+		logger.Fatal("Failed to create the OrderService gateway", zap.Error(err))
+	}
 
-		// Call Node.js which will call us back
-		req, err := http.NewRequestWithContext(ctx, "GET", nodeServiceURL+"/api/call-go", nil)
+	// Postgres is optional so the example still runs without a database
+	var db *database.DB
+	var ormStore *orm.Store
+	if cfg.DatabaseURL != "" {
+		db, err = database.Open(context.Background(), sdk, cfg.DatabaseURL, cfg.SlowQueryThreshold)
 		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"error": "Failed to create request"})
-			return
+			logger.Fatal("Failed to connect to database", zap.Error(err))
 		}
+		a.closers = append(a.closers, db.Close)
 
-		resp, err := httpClient.Do(req)
-		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"error": fmt.Sprintf("Chain call failed: %v", err)})
-			return
+		if err := db.Migrate(context.Background()); err != nil {
+			logger.Fatal("Failed to migrate database", zap.Error(err))
 		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		var nodeResponse map[string]interface{}
-		json.Unmarshal(body, &nodeResponse)
-
-		sdk.AddEvent(span, "chain.completed")
-		sdk.SetSuccess(span)
+		logger.Info("🐘 Connected to Postgres")
 
-		c.JSON(200, gin.H{
-			"message":       "Chain call completed",
-			"node_response": nodeResponse,
-		})
-	})
-
-	// Internal endpoint for Node.js to call back
-	r.GET("/api/internal", func(c *gin.Context) {
-		ctx := c.Request.Context()
+		// The same database also backs the GORM example
+		ormStore, err = orm.Open(sdk, cfg.DatabaseURL)
+		if err != nil {
+			logger.Fatal("Failed to open GORM connection", zap.Error(err))
+		}
+		a.closers = append(a.closers, ormStore.Close)
 
-		ctx, span := sdk.StartSpan(ctx, "internalEndpoint")
-		defer span.End()
+		if err := ormStore.Migrate(context.Background()); err != nil {
+			logger.Fatal("Failed to migrate products", zap.Error(err))
+		}
+	}
 
-		time.Sleep(30 * time.Millisecond)
+	// SQLite keeps /api/order self-contained; if it can't be opened (e.g. a
+	// build without cgo) orders simply aren't persisted
+	var orderStore *database.DB
+	if cfg.SQLitePath != "" {
+		orderStore, err = database.OpenSQLite(context.Background(), sdk, cfg.SQLitePath, cfg.SlowQueryThreshold)
+		if err != nil {
+			logger.Warn("SQLite order store disabled", zap.Error(err))
+		} else {
+			a.closers = append(a.closers, orderStore.Close)
 
-		sdk.AddAttribute(span, "called.by", "node-test-app")
-		sdk.AddEvent(span, "internal.processed")
-		sdk.SetSuccess(span)
+			if err := orderStore.MigrateOrders(context.Background()); err != nil {
+				logger.Fatal("Failed to migrate orders", zap.Error(err))
+			}
+			if err := orderStore.MigrateInventory(context.Background(), inventory.Seed); err != nil {
+				logger.Fatal("Failed to migrate inventory", zap.Error(err))
+			}
+			logger.Info("🗄️ Persisting orders to SQLite", zap.String("path", cfg.SQLitePath))
+		}
+	}
 
-		c.JSON(200, gin.H{
-			"message":   "Internal endpoint response from Go",
-			"service":   "go-test-app",
-			"timestamp": time.Now().Format(time.RFC3339),
-		})
-	})
+	// MongoDB is optional; /api/documents returns 503 without it
+	var mongoStore *mongodb.Store
+	if cfg.MongoURI != "" {
+		mongoStore, err = mongodb.Open(context.Background(), sdk, cfg.MongoURI, cfg.MongoDatabase)
+		if err != nil {
+			logger.Fatal("Failed to connect to MongoDB", zap.Error(err))
+		}
+		a.closers = append(a.closers, func() error { return mongoStore.Close(context.Background()) })
+		logger.Info("🍃 Connected to MongoDB", zap.String("database", cfg.MongoDatabase))
+	}
 
-	// Data endpoint - called by other services for distributed tracing
-	r.GET("/api/data", func(c *gin.Context) {
-		ctx := c.Request.Context()
+	// Redis is optional too; /api/users falls back to the origin without it
+	var userCache *cache.Cache
+	if cfg.RedisAddr != "" {
+		userCache, err = cache.New(context.Background(), sdk, cfg.RedisAddr, cfg.CacheTTL)
+		if err != nil {
+			logger.Fatal("Failed to connect to Redis", zap.Error(err))
+		}
+		a.closers = append(a.closers, userCache.Close)
+		logger.Info("🧠 Connected to Redis cache", zap.String("addr", cfg.RedisAddr))
+	}
 
-		ctx, span := sdk.StartSpan(ctx, "processData")
-		defer span.End()
+	// Worker pool for jobs that outlive the request that created them
+	workers := worker.NewPool(sdk, cfg.WorkerPoolSize, cfg.WorkerQueueSize)
+	a.spawn(workers.Run)
 
-		time.Sleep(30 * time.Millisecond)
+	// Batcher for /api/batch-process; each batch links to every request it serves
+	batcher := batch.New(sdk, cfg.BatchMaxSize, cfg.BatchWindow)
+	a.spawn(batcher.Run)
 
-		sdk.AddAttribute(span, "data.source", "go-test-app")
-		sdk.SetSuccess(span)
+	// Runtime stats are sampled in the background for slow request spans
+	runtimeSampler := runtimestats.NewSampler(cfg.RuntimeSampleInterval)
+	a.spawn(runtimeSampler.Run)
 
-		c.JSON(200, gin.H{
-			"service":   "go-test-app",
-			"timestamp": time.Now().Format(time.RFC3339),
-			"data": gin.H{
-				"go_version":   "1.21",
-				"random_value": rand.Intn(100),
-			},
-		})
-	})
+	// Order confirmation emails are sent in their own trace
+	mailer := notify.NewMailer(sdk, cfg.WorkerQueueSize)
+	a.spawn(mailer.Run)
 
-	// Call Python service
-	r.GET("/api/call-python", func(c *gin.Context) {
-		ctx := c.Request.Context()
+	// The mTLS client certificate rotates like the server one
+	if clientCert != nil && cfg.TLSReloadInterval > 0 {
+		a.spawn(func(ctx context.Context) { clientCert.Watch(ctx, cfg.TLSReloadInterval) })
+	}
 
-		ctx, span := sdk.StartSpan(ctx, "callPythonService")
-		defer span.End()
+	// Scheduled jobs produce a root span per execution
+	if cfg.SchedulerEnabled {
+		sched := scheduler.New(sdk)
+		if err := sched.Add("cleanup", cfg.CleanupSchedule, scheduler.Cleanup(sdk)); err != nil {
+			logger.Fatal("Invalid CLEANUP_SCHEDULE", zap.Error(err))
+		}
+		if err := sched.Add("report", cfg.ReportSchedule, scheduler.Report(sdk)); err != nil {
+			logger.Fatal("Invalid REPORT_SCHEDULE", zap.Error(err))
+		}
+		if err := sched.Add("runtime", cfg.RuntimeSchedule, scheduler.Runtime(sdk)); err != nil {
+			logger.Fatal("Invalid RUNTIME_REPORT_SCHEDULE", zap.Error(err))
+		}
+		a.spawn(sched.Run)
+		logger.Info("⏰ Scheduler started")
+	}
 
-		c.Request = c.Request.WithContext(ctx)
+	// Kafka is optional; the consumer continues traces started by /api/publish-order
+	var producer *messaging.KafkaProducer
+	if len(cfg.KafkaBrokers) > 0 {
+		producer = messaging.NewKafkaProducer(sdk, cfg.KafkaBrokers, cfg.KafkaTopic)
+		a.closers = append(a.closers, producer.Close)
 
-		sdk.AddAttribute(span, "target.service", "python-test-app")
+		consumer := messaging.NewKafkaConsumer(sdk, cfg.KafkaBrokers, cfg.KafkaTopic, cfg.KafkaGroupID)
+		a.spawn(consumer.Run)
+		logger.Info("📨 Kafka enabled", zap.String("topic", cfg.KafkaTopic))
+	}
 
-		req, err := http.NewRequestWithContext(ctx, "GET", pythonServiceURL+"/api/data", nil)
+	// NATS is optional; /api/check-stock and its responder share this connection
+	var natsRPC *messaging.NATSRPC
+	if cfg.NATSURL != "" {
+		natsRPC, err = messaging.NewNATSRPC(sdk, cfg.NATSURL, cfg.NATSSubject)
 		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"service": "go-test-app", "called": "python-test-app", "error": "Failed to create request"})
-			return
+			logger.Fatal("Failed to connect to NATS", zap.Error(err))
 		}
+		a.closers = append(a.closers, natsRPC.Close)
 
-		resp, err := httpClient.Do(req)
-		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"service": "go-test-app", "called": "python-test-app", "error": err.Error()})
-			return
-		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		var response map[string]interface{}
-		json.Unmarshal(body, &response)
-
-		sdk.SetSuccess(span)
-		c.JSON(200, gin.H{
-			"service":  "go-test-app",
-			"called":   "python-test-app",
-			"response": response,
-			"status":   resp.StatusCode,
-		})
-	})
-
-	// Call Laravel service
-	r.GET("/api/call-laravel", func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		ctx, span := sdk.StartSpan(ctx, "callLaravelService")
-		defer span.End()
-
-		c.Request = c.Request.WithContext(ctx)
-
-		sdk.AddAttribute(span, "target.service", "laravel-test-app")
-
-		req, err := http.NewRequestWithContext(ctx, "GET", laravelServiceURL+"/api/data", nil)
-		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"service": "go-test-app", "called": "laravel-test-app", "error": "Failed to create request"})
-			return
+		if err := natsRPC.Serve(); err != nil {
+			logger.Fatal("Failed to subscribe to NATS", zap.Error(err))
 		}
+		logger.Info("📡 NATS enabled", zap.String("subject", cfg.NATSSubject))
+	}
 
-		resp, err := httpClient.Do(req)
+	// RabbitMQ is optional; the consumer continues traces started by /api/enqueue
+	var rabbit *messaging.RabbitMQ
+	if cfg.RabbitMQURL != "" {
+		rabbit, err = messaging.NewRabbitMQ(sdk, cfg.RabbitMQURL, cfg.RabbitMQQueue)
 		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"service": "go-test-app", "called": "laravel-test-app", "error": err.Error()})
-			return
-		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		var response map[string]interface{}
-		json.Unmarshal(body, &response)
-
-		sdk.SetSuccess(span)
-		c.JSON(200, gin.H{
-			"service":  "go-test-app",
-			"called":   "laravel-test-app",
-			"response": response,
-			"status":   resp.StatusCode,
-		})
-	})
-
-	// Call PHP service
-	r.GET("/api/call-php", func(c *gin.Context) {
-		ctx := c.Request.Context()
-
-		ctx, span := sdk.StartSpan(ctx, "callPHPService")
-		defer span.End()
-
-		c.Request = c.Request.WithContext(ctx)
+			logger.Fatal("Failed to connect to RabbitMQ", zap.Error(err))
+		}
+		a.closers = append(a.closers, rabbit.Close)
 
-		sdk.AddAttribute(span, "target.service", "php-test-app")
+		a.spawn(rabbit.Consume)
+		logger.Info("🐇 RabbitMQ enabled", zap.String("queue", cfg.RabbitMQQueue))
+	}
 
-		req, err := http.NewRequestWithContext(ctx, "GET", phpServiceURL+"/api/data", nil)
+	// SQS is optional; the poller continues traces started by /api/enqueue-sqs
+	var sqsQueue *messaging.SQSQueue
+	if cfg.SQSQueueURL != "" {
+		sqsQueue, err = messaging.NewSQSQueue(context.Background(), sdk, cfg.SQSQueueURL, cfg.SQSEndpoint)
 		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"service": "go-test-app", "called": "php-test-app", "error": "Failed to create request"})
-			return
+			logger.Fatal("Failed to configure SQS", zap.Error(err))
 		}
 
-		resp, err := httpClient.Do(req)
-		if err != nil {
-			sdk.RecordError(span, err)
-			c.JSON(500, gin.H{"service": "go-test-app", "called": "php-test-app", "error": err.Error()})
-			return
-		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		var response map[string]interface{}
-		json.Unmarshal(body, &response)
-
-		sdk.SetSuccess(span)
-		c.JSON(200, gin.H{
-			"service":  "go-test-app",
-			"called":   "php-test-app",
-			"response": response,
-			"status":   resp.StatusCode,
-		})
-	})
+		a.spawn(sqsQueue.Poll)
+		logger.Info("☁️ SQS enabled", zap.String("queue_url", cfg.SQSQueueURL))
+	}
 
-	// Call all services
-	r.GET("/api/call-all", func(c *gin.Context) {
-		ctx := c.Request.Context()
+	// Outbound webhooks are optional; each delivery attempt is its own trace
+	var webhooks *webhook.Dispatcher
+	if cfg.WebhookTargetURL != "" {
+		webhooks = webhook.NewDispatcher(sdk, cfg.WebhookTargetURL, []byte(cfg.WebhookSecret), cfg.WebhookMaxAttempts)
 
-		ctx, span := sdk.StartSpan(ctx, "callAllServices")
-		defer span.End()
+		a.spawn(webhooks.Run)
+		logger.Info("🪝 Outbound webhooks enabled", zap.String("url", cfg.WebhookTargetURL))
+	}
 
-		c.Request = c.Request.WithContext(ctx)
+	// Embedded assets for the status page, served with a span per file
+	staticFiles, err := static.New(sdk)
+	if err != nil {
+		logger.Fatal("Failed to load static assets", zap.Error(err))
+	}
 
-		services := []struct {
-			name string
-			url  string
-		}{
-			{"node-test-app", nodeServiceURL},
-			{"python-test-app", pythonServiceURL},
-			{"laravel-test-app", laravelServiceURL},
-			{"php-test-app", phpServiceURL},
-		}
+	// The status page at / shows the trace IDs of the last few requests
+	recentRequests := recent.New(20, "/", "/static/*", "/health", "/livez", "/readyz")
 
-		var chain []map[string]interface{}
+	// Stock checks for /api/order go through an in-memory cache to the
+	// SQLite inventory table, or a simulated store without SQLite
+	stock := inventory.New(sdk, orderStore, cfg.InventoryCacheTTL, cfg.OutOfStockRate)
 
-		for _, svc := range services {
-			req, err := http.NewRequestWithContext(ctx, "GET", svc.url+"/api/data", nil)
-			if err != nil {
-				chain = append(chain, map[string]interface{}{
-					"service": svc.name,
-					"error":   "Failed to create request",
-				})
-				continue
-			}
+	// The demo users live in memory behind /api/users and /api/users/:id
+	userStore := userstore.NewStore(sdk, cfg.GeneratedUsers)
 
-			resp, err := httpClient.Do(req)
-			if err != nil {
-				chain = append(chain, map[string]interface{}{
-					"service": svc.name,
-					"error":   err.Error(),
-				})
-				continue
-			}
+	// Shopping carts live in memory, keyed by session cookie
+	carts := cart.NewStore(sdk, cfg.SessionTTL)
 
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
+	// Long polls on /api/poll wait for events published to this hub
+	a.polls = poll.New()
 
-			var response map[string]interface{}
-			json.Unmarshal(body, &response)
+	// /api/pay charges through an in-process gateway simulator
+	gateway := payments.NewGateway(sdk)
 
-			chain = append(chain, map[string]interface{}{
-				"service":  svc.name,
-				"status":   resp.StatusCode,
-				"response": response,
-			})
-		}
+	// Feature flags gate the v2 order validation and confirmation emails
+	featureFlags, err := loadFlags(sdk, cfg)
+	if err != nil {
+		logger.Fatal("Invalid feature flags", zap.String("file", cfg.FlagsFile), zap.Error(err))
+	}
 
-		sdk.SetSuccess(span)
-		c.JSON(200, gin.H{
-			"service": "go-test-app",
-			"chain":   chain,
-		})
+	h := handlers.New(handlers.Deps{
+		SDK:        sdk,
+		Client:     client,
+		Orders:     orders,
+		Workers:    workers,
+		Batcher:    batcher,
+		Mailer:     mailer,
+		Notifier:   notify.NewDispatcher(sdk),
+		GraphQL:    gql.NewHandler(sdk),
+		Gateway:    orderGateway,
+		SOAP:       soap.NewService(sdk),
+		Static:     staticFiles,
+		DB:         db,
+		OrderStore: orderStore,
+		Inventory:  stock,
+		ORM:        ormStore,
+		Mongo:      mongoStore,
+		Cache:      userCache,
+		Producer:   producer,
+		NATS:       natsRPC,
+		Rabbit:     rabbit,
+		SQS:        sqsQueue,
+		Webhooks:   webhooks,
+		Users:      userStore,
+		Carts:      carts,
+		Payments:   gateway,
+		Polls:      a.polls,
+		Flags:      featureFlags,
+		External:   external,
+		Proxy:      proxyTarget,
+
+		WebhookSecret: cfg.WebhookSecret,
+		CollectorAddr: collectorAddr,
+		Readiness:     readiness,
+		Sampler:       sampler,
+		Recent:        recentRequests,
+		ServiceName:   cfg.ServiceName,
+		Environment:   cfg.Environment,
 	})
 
-	// Endpoint with business logic and metrics
-	r.POST("/api/order", func(c *gin.Context) {
-		start := time.Now()
-		activeRequestsGauge.Inc()
-		defer func() {
-			activeRequestsGauge.Dec()
-			duration := float64(time.Since(start).Milliseconds())
-			requestDurationHisto.Record(duration)
-		}()
-
-		requestCounter.Inc()
-
-		ctx := c.Request.Context()
-
-		ctx, span := sdk.StartSpan(ctx, "createOrder")
-		defer span.End()
-
-		orderID := fmt.Sprintf("ORD-%d", time.Now().Unix())
-		amount := rand.Float64() * 1000
-
-		// Track order metrics
-		orderCounter.Inc()
-		orderAmountHisto.Record(amount)
-
-		sdk.AddBusinessAttributes(span, map[string]interface{}{
-			"order.id":     orderID,
-			"order.amount": amount,
-			"customer.id":  "cust-123",
-		})
-
-		sdk.AddEvent(span, "order.created")
-		time.Sleep(100 * time.Millisecond)
-		sdk.AddEvent(span, "order.validated")
-		time.Sleep(50 * time.Millisecond)
-		sdk.AddEvent(span, "order.processed")
-
-		sdk.SetSuccess(span)
-
-		c.JSON(201, gin.H{
-			"order_id": orderID,
-			"amount":   amount,
-			"status":   "created",
-		})
-	})
+	logger.Info("✅ TraceKit SDK initialized successfully!",
+		zap.String("service", cfg.ServiceName),
+		zap.String("environment", cfg.Environment),
+	)
+
+	// Prometheus metrics with trace-ID exemplars, scraped from the admin port
+	promMetrics := prom.New()
+	adminSrv.Handle("/metrics", promMetrics.Handler())
+
+	// Setup Gin with tracing; the Prometheus middleware runs inside the
+	// SDK middleware so it can read the server span for exemplars
+	r := gin.New()
+	a.router = r
+	// Only a listed proxy may set the client IP the rate limiter keys on
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES", zap.Error(err))
+	}
+	r.Use(gin.Recovery())
+	r.Use(sampler.Middleware(sdk.GinMiddleware()))
+	r.Use(tracing.RouteNames())
+	r.Use(certs.Middleware())
+	r.Use(requestid.Middleware())
+	r.Use(logging.GinMiddleware())
+	r.Use(recentRequests.Middleware())
+	r.Use(deadline.New(cfg.RouteBudgets).Middleware())
+	r.Use(runtimeSampler.Middleware(cfg.SlowRequestThreshold))
+	r.Use(promMetrics.Middleware())
+	if cfg.RateLimitRPS > 0 {
+		// Last so throttled requests still show up in traces, logs, and metrics
+		limiter := ratelimit.New(cfg.RateLimitRPS, cfg.RateLimitBurst, "/health", "/livez", "/readyz")
+		r.Use(limiter.Middleware())
+	}
+	// After rate limiting so throttled requests aren't slowed down too. The
+	// defaults are off until set through /admin/config.
+	chaosDefaults := &chaos.Defaults{}
+	r.Use(chaos.Middleware(sdk, chaosDefaults, cfg.ChaosHeader, "/health", "/livez", "/readyz"))
+	if cfg.CompressResponses {
+		// Before body capture so the captured body is the uncompressed one
+		compressor, err := compress.New(cfg.GzipLevel, cfg.GzipMinBytes)
+		if err != nil {
+			logger.Fatal("Invalid GZIP_LEVEL", zap.Error(err))
+		}
+		r.Use(compressor.Middleware())
+	}
+	if cfg.CaptureBodies {
+		redact := cfg.CaptureRedactFields
+		if len(redact) == 0 {
+			redact = capture.DefaultRedactFields
+		}
+		r.Use(capture.New(cfg.CaptureMaxBytes, redact).Middleware())
+		logger.Info("📝 Capturing request/response bodies on spans", zap.Strings("redact", redact))
+	}
+	if len(cfg.ResponseCacheRoutes) > 0 {
+		// Last so cached responses still pass through every other middleware
+		r.Use(respcache.New(cfg.ResponseCacheRoutes, cfg.ResponseCacheStale).Middleware())
+	}
+	h.Register(r)
+
+	adminSrv.Handle("/admin/config", admin.ConfigHandler(cfg.AdminToken, admin.LiveConfig{
+		Sampler: sampler,
+		Chaos:   chaosDefaults,
+		SpanLog: spanLog,
+	}))
+	return a
+}
 
-	// Endpoint that triggers an error
-	r.GET("/api/error", func(c *gin.Context) {
-		ctx := c.Request.Context()
+// spawn runs fn in the background until stopBackground cancels its context
+func (a *app) spawn(fn func(ctx context.Context)) {
+	a.background.Add(1)
+	go func() {
+		defer a.background.Done()
+		fn(a.bgCtx)
+	}()
+}
 
-		ctx, span := sdk.StartSpan(ctx, "triggerError")
-		defer span.End()
+// stopBackground cancels the background work and waits for it to return
+func (a *app) stopBackground() {
+	a.cancelBackground()
+	a.background.Wait()
+}
 
-		c.Request = c.Request.WithContext(ctx)
+// serveGRPC serves the OrderService on GRPC_PORT in the background
+func (a *app) serveGRPC() {
+	lis, err := net.Listen("tcp", ":"+a.cfg.GRPCPort)
+	if err != nil {
+		a.logger.Fatal("Failed to listen for gRPC", zap.Error(err))
+	}
+	a.logger.Info("🔌 gRPC OrderService listening", zap.String("port", a.cfg.GRPCPort))
+	go func() {
+		if err := a.grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			a.serverErr <- err
+		}
+	}()
+}
 
-		errorType := "gateway_timeout"
-		retryCount := 3
-		sdk.CheckAndCaptureWithContext(ctx, "error-handler", map[string]interface{}{
-			"error_type":  errorType,
-			"retry_count": retryCount,
-			"endpoint":    "/api/error",
-		})
+// close releases the connections newApp opened, the last opened first
+func (a *app) close() {
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+}
 
-		err := fmt.Errorf("simulated error: payment gateway timeout")
+// loadServices reads the service topology cfg points at
+func loadServices(cfg *config.Config) (clients.Services, error) {
+	topology := defaultTopology
+	if cfg.ServicesFile != "" {
+		var err error
+		if topology, err = os.ReadFile(cfg.ServicesFile); err != nil {
+			return clients.Services{}, err
+		}
+	}
+	return clients.ParseServices(topology)
+}
 
-		sdk.RecordError(span, err)
-		sdk.AddAttribute(span, "error.type", errorType)
-		sdk.AddAttribute(span, "retry.count", fmt.Sprintf("%d", retryCount))
+// loadFlags reads the feature flags cfg points at, or the built-in ones
+func loadFlags(sdk *tracekit.SDK, cfg *config.Config) (*flags.Provider, error) {
+	data, name := defaultFlags, "flags.yaml"
+	if cfg.FlagsFile != "" {
+		var err error
+		if data, err = os.ReadFile(cfg.FlagsFile); err != nil {
+			return nil, err
+		}
+		name = cfg.FlagsFile
+	}
+	defined, err := flags.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return flags.New(sdk, name, defined, cfg.FlagOverrides)
+}
 
-		sdk.AddEvent(span, "error.occurred")
+// downstreamTLS is the TLS configuration for calls to downstream services,
+// or nil for the defaults. A client certificate comes with its reloader, so
+// the caller can watch the files.
+func downstreamTLS(sdk *tracekit.SDK, cfg *config.Config) (*tls.Config, *certs.Reloader, error) {
+	if cfg.DownstreamCertFile == "" && cfg.DownstreamCAFile == "" {
+		return nil, nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	var reloader *certs.Reloader
+	if cfg.DownstreamCertFile != "" {
+		var err error
+		if reloader, err = certs.New(sdk, cfg.DownstreamCertFile, cfg.DownstreamKeyFile); err != nil {
+			return nil, nil, err
+		}
+		tlsConfig.GetClientCertificate = reloader.ClientCertificate
+	}
+	if cfg.DownstreamCAFile != "" {
+		roots, err := certs.LoadCAs(cfg.DownstreamCAFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		tlsConfig.RootCAs = roots
+	}
+	return tlsConfig, reloader, nil
+}
 
-		c.JSON(500, gin.H{
-			"error":   "Internal Server Error",
-			"message": "Payment gateway timeout",
-		})
-	})
+// samplingSettings are the sampler settings cfg describes
+func samplingSettings(cfg *config.Config) sampling.Settings {
+	return sampling.Settings{
+		Ratio:       cfg.SampleRate,
+		ParentBased: cfg.SampleParentBased,
+		Source:      cfg.SampleSource,
+		Routes:      cfg.SampleRoutes,
+		SkipPaths:   cfg.TraceSkipPaths,
+	}
+}
 
-	// Security test endpoint
-	r.GET("/security-test", func(c *gin.Context) {
-		ctx := c.Request.Context()
+// shutdown drains in-flight requests and then flushes the SDK so the last
+// batch of spans and metrics is exported before the process exits. The admin
+// listener stops last so the drain can still be scraped and profiled.
+func shutdown(logger *zap.Logger, sdk *tracekit.SDK, servers []*http.Server, adminSrv *http.Server, grpcServer *grpc.Server, stopBackground func()) {
+	serverCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
 
-		// Test sensitive data detection in snapshots
-		// NOTE: These are FAKE test values for demonstration purposes only
-		testVariables := map[string]interface{}{
-			"password":    "super_secret_password_123",
-			"api_key":     "test_key_abc123_NOT_REAL_demo_only",
-			"user_token":  "test_token_xyz789_fake_for_testing",
-			"credit_card": "4532015112830366",
-			"normal_var":  "This is just normal data",
-		}
+	for _, srv := range servers {
+		stopHTTP(serverCtx, logger, srv)
+	}
 
-		sdk.CheckAndCaptureWithContext(ctx, "security-test-with-sensitive-data", testVariables)
+	// GracefulStop waits for in-flight RPCs; fall back to Stop at the deadline
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		logger.Info("✅ gRPC server stopped")
+	case <-serverCtx.Done():
+		grpcServer.Stop()
+		logger.Warn("⚠️  gRPC server did not drain cleanly", zap.Error(serverCtx.Err()))
+	}
 
-		c.JSON(200, gin.H{
-			"message": "Security test completed - check for security events",
-			"note":    "Sensitive data should be redacted in the snapshot",
-		})
-	})
+	// Stop consumers and workers once the servers can no longer hand them work
+	backgroundDone := make(chan struct{})
+	go func() {
+		stopBackground()
+		close(backgroundDone)
+	}()
+	select {
+	case <-backgroundDone:
+		logger.Info("✅ Background workers stopped")
+	case <-serverCtx.Done():
+		logger.Warn("⚠️  Background workers did not stop in time", zap.Error(serverCtx.Err()))
+	}
 
-	// Metrics info endpoint
-	r.GET("/api/metrics", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "Metrics are being collected and sent to TraceKit",
-			"metrics": map[string]string{
-				"http.requests.total":     "Counter - Total HTTP requests",
-				"http.requests.active":    "Gauge - Currently active requests",
-				"http.request.duration":   "Histogram - Request duration in ms",
-				"orders.total":            "Counter - Total orders created",
-				"order.amount":            "Histogram - Order amounts in USD",
-			},
-			"note": "Metrics are flushed every 10 seconds or when 100 metrics are collected",
-		})
-	})
+	stopHTTP(serverCtx, logger, adminSrv)
 
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"service": "go-test-app",
-			"time":    time.Now().Format(time.RFC3339),
-		})
-	})
+	// The SDK gets its own budget so a slow drain can't starve the final export
+	sdkCtx, sdkCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer sdkCancel()
 
-	log.Println("🚀 Go Test App starting on http://localhost:8082")
-	log.Println("📊 All requests are automatically traced!")
-	log.Println("\nEndpoints:")
-	log.Println("  GET  /              - Hello message")
-	log.Println("  GET  /api/users     - Fetch users (with custom span)")
-	log.Println("  GET  /api/call-node - Call Node.js service (CLIENT span test)")
-	log.Println("  GET  /api/chain     - Chain call: Go -> Node -> Go")
-	log.Println("  GET  /api/internal  - Internal endpoint (called by Node)")
-	log.Println("  POST /api/order     - Create order (with business attributes)")
-	log.Println("  GET  /api/error     - Trigger an error (for testing)")
-	log.Println("  GET  /health        - Health check")
-	log.Println("\nPress Ctrl+C to stop")
-
-	if err := r.Run(":8082"); err != nil {
-		log.Fatal("Failed to start server:", err)
+	start := time.Now()
+	if err := sdk.Shutdown(sdkCtx); err != nil {
+		logger.Error("⚠️  Failed to flush spans to TraceKit", zap.Error(err))
+		return
 	}
+	logger.Info("✅ Spans flushed to TraceKit", zap.Duration("took", time.Since(start).Round(time.Millisecond)))
 }
 
-// getEnv retrieves an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// stopHTTP shuts srv down once its in-flight requests are done
+func stopHTTP(ctx context.Context, logger *zap.Logger, srv *http.Server) {
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Warn("⚠️  HTTP server did not drain cleanly", zap.String("addr", srv.Addr), zap.Error(err))
+		return
 	}
-	return defaultValue
+	logger.Info("✅ HTTP server stopped", zap.String("addr", srv.Addr))
 }